@@ -48,13 +48,25 @@ func (src *AWSCluster) ConvertTo(dstRaw conversion.Hub) error { // nolint
 		return err
 	}
 
-	// override the SSHKeyName conversion if we are roundtripping from v1alpha3 and the v1alpha3 value is nil
-	if src.Spec.SSHKeyName == "" && restored.Spec.SSHKeyName == nil {
-		dst.Spec.SSHKeyName = nil
+	// restore an explicitly empty SSHKeyName, which v1alpha2 cannot tell apart from an unset one
+	if src.Spec.SSHKeyName == "" && restored.Spec.SSHKeyName != nil && *restored.Spec.SSHKeyName == "" {
+		dst.Spec.SSHKeyName = pointer.StringPtr("")
 	}
 
 	dst.Spec.ImageLookupOrg = restored.Spec.ImageLookupOrg
 	dst.Spec.ImageLookupBaseOS = restored.Spec.ImageLookupBaseOS
+	dst.Spec.ImageLookupFormat = restored.Spec.ImageLookupFormat
+	dst.Spec.ImageLookupSSMParameter = restored.Spec.ImageLookupSSMParameter
+	dst.Spec.PlacementGroups = restored.Spec.PlacementGroups
+	dst.Spec.AccessMode = restored.Spec.AccessMode
+	dst.Spec.SessionManager = restored.Spec.SessionManager
+	dst.Spec.S3Bucket = restored.Spec.S3Bucket
+	dst.Spec.Bastion.InstanceMetadataOptions = restored.Spec.Bastion.InstanceMetadataOptions
+	dst.Spec.Bastion.InstanceType = restored.Spec.Bastion.InstanceType
+	dst.Spec.Bastion.AMI = restored.Spec.Bastion.AMI
+	dst.Spec.Bastion.ImageLookupSSMParameter = restored.Spec.Bastion.ImageLookupSSMParameter
+	dst.Spec.Bastion.AllowedCIDRBlocks = restored.Spec.Bastion.AllowedCIDRBlocks
+	dst.Spec.Bastion.AdditionalTags = restored.Spec.Bastion.AdditionalTags
 	if restored.Spec.ControlPlaneLoadBalancer != nil {
 		dst.Spec.ControlPlaneLoadBalancer = restored.Spec.ControlPlaneLoadBalancer
 	}
@@ -133,8 +145,11 @@ func Convert_v1alpha2_AWSClusterSpec_To_v1alpha3_AWSClusterSpec(in *AWSClusterSp
 	// Manually convert Bastion.
 	out.Bastion.Enabled = !in.DisableBastionHost
 
-	// Manually convert SSHKeyName
-	out.SSHKeyName = pointer.StringPtr(in.SSHKeyName)
+	// Manually convert SSHKeyName, an empty value meaning it is unset.
+	out.SSHKeyName = nil
+	if in.SSHKeyName != "" {
+		out.SSHKeyName = pointer.StringPtr(in.SSHKeyName)
+	}
 
 	return nil
 }
@@ -21,6 +21,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	infrav1alpha3 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
 )
 
@@ -41,6 +42,32 @@ func TestConvertAWSCluster(t *testing.T) {
 			g.Expect(dst.ConvertTo(restored)).To(Succeed())
 			g.Expect(restored.Spec.SSHKeyName).To(BeNil())
 		})
+		t.Run("should restore SSHKeyName, retaining an empty value", func(t *testing.T) {
+			src := &infrav1alpha3.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: infrav1alpha3.AWSClusterSpec{
+					SSHKeyName: pointer.StringPtr(""),
+				},
+			}
+			dst := &AWSCluster{}
+			g.Expect(dst.ConvertFrom(src)).To(Succeed())
+			restored := &infrav1alpha3.AWSCluster{}
+			g.Expect(dst.ConvertTo(restored)).To(Succeed())
+			g.Expect(restored.Spec.SSHKeyName).To(Equal(pointer.StringPtr("")))
+		})
+	})
+
+	t.Run("to hub", func(t *testing.T) {
+		t.Run("should convert an empty SSHKeyName to a nil value", func(t *testing.T) {
+			src := &AWSCluster{
+				Spec: AWSClusterSpec{
+					SSHKeyName: "",
+				},
+			}
+			dst := &infrav1alpha3.AWSCluster{}
+			g.Expect(src.ConvertTo(dst)).To(Succeed())
+			g.Expect(dst.Spec.SSHKeyName).To(BeNil())
+		})
 	})
 
 }
@@ -39,24 +39,52 @@ func (src *AWSMachine) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	}
 	restoreAWSMachineSpec(&restored.Spec, &dst.Spec)
 
+	dst.Status.InstanceLifecycle = restored.Status.InstanceLifecycle
+	dst.Status.VolumeAttachments = restored.Status.VolumeAttachments
+	dst.Status.Architecture = restored.Status.Architecture
+	dst.Status.InstanceType = restored.Status.InstanceType
+	dst.Status.Resize = restored.Status.Resize
+	dst.Status.FailedResizeInstanceType = restored.Status.FailedResizeInstanceType
+	dst.Status.CapacityReservationID = restored.Status.CapacityReservationID
+	dst.Status.Conditions = restored.Status.Conditions
+	dst.Status.ScheduledEvents = restored.Status.ScheduledEvents
+
 	return nil
 }
 
 func restoreAWSMachineSpec(restored *infrav1alpha3.AWSMachineSpec, dst *infrav1alpha3.AWSMachineSpec) {
 	dst.ImageLookupBaseOS = restored.ImageLookupBaseOS
+	dst.ImageLookupFormat = restored.ImageLookupFormat
+	dst.ImageLookupSSMParameter = restored.ImageLookupSSMParameter
 
 	// Note this may override the manual conversion in Convert_v1alpha2_AWSMachineSpec_To_v1alpha3_AWSMachineSpec.
 	if restored.RootVolume != nil {
 		restored.RootVolume.DeepCopyInto(dst.RootVolume)
 	}
 
-	// override the SSHKeyName conversion if we are roundtripping from v1alpha3 and the v1alpha3 value is nil
-	if dst.SSHKeyName != nil && *dst.SSHKeyName == "" && restored.SSHKeyName == nil {
-		dst.SSHKeyName = nil
+	// restore an explicitly empty SSHKeyName, which v1alpha2 cannot tell apart from an unset one
+	if dst.SSHKeyName == nil && restored.SSHKeyName != nil && *restored.SSHKeyName == "" {
+		dst.SSHKeyName = pointer.StringPtr("")
 	}
 
 	// manual conversion for UncompressedUserData
 	dst.UncompressedUserData = restored.UncompressedUserData
+
+	dst.SpotMarketOptions = restored.SpotMarketOptions
+	dst.AdditionalVolumes = restored.AdditionalVolumes
+	dst.Placement = restored.Placement
+	dst.InstanceMetadataOptions = restored.InstanceMetadataOptions
+	dst.FallbackInstanceTypes = restored.FallbackInstanceTypes
+	dst.FallbackFailureDomains = restored.FallbackFailureDomains
+	dst.PrivateIPAddress = restored.PrivateIPAddress
+	dst.ElasticIP = restored.ElasticIP
+	dst.ManagedNetworkInterfaces = restored.ManagedNetworkInterfaces
+	dst.InPlaceResize = restored.InPlaceResize
+	dst.DeletionPolicy = restored.DeletionPolicy
+	dst.DisableAPITermination = restored.DisableAPITermination
+	dst.CapacityReservation = restored.CapacityReservation
+	dst.CloudInit.SecureSecretsBackend = restored.CloudInit.SecureSecretsBackend
+	dst.BootstrapFormat = restored.BootstrapFormat
 }
 
 // ConvertFrom converts from the Hub version (v1alpha3) to this version.
@@ -96,8 +124,11 @@ func Convert_v1alpha2_AWSMachineSpec_To_v1alpha3_AWSMachineSpec(in *AWSMachineSp
 	// Manually convert dst.Spec.FailureDomain.
 	out.FailureDomain = in.AvailabilityZone
 
-	// Manually convert SSHKeyName
-	out.SSHKeyName = pointer.StringPtr(in.SSHKeyName)
+	// Manually convert SSHKeyName, an empty value meaning it is unset.
+	out.SSHKeyName = nil
+	if in.SSHKeyName != "" {
+		out.SSHKeyName = pointer.StringPtr(in.SSHKeyName)
+	}
 
 	if in.CloudInit == nil {
 		out.CloudInit.InsecureSkipSecretsManager = true
@@ -448,7 +448,13 @@ func autoConvert_v1alpha3_AWSClusterSpec_To_v1alpha2_AWSClusterSpec(in *v1alpha3
 	}
 	// WARNING: in.ImageLookupOrg requires manual conversion: does not exist in peer-type
 	// WARNING: in.ImageLookupBaseOS requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageLookupFormat requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageLookupSSMParameter requires manual conversion: does not exist in peer-type
 	// WARNING: in.Bastion requires manual conversion: does not exist in peer-type
+	// WARNING: in.PlacementGroups requires manual conversion: does not exist in peer-type
+	// WARNING: in.AccessMode requires manual conversion: does not exist in peer-type
+	// WARNING: in.SessionManager requires manual conversion: does not exist in peer-type
+	// WARNING: in.S3Bucket requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -591,20 +597,36 @@ func autoConvert_v1alpha3_AWSMachineSpec_To_v1alpha2_AWSMachineSpec(in *v1alpha3
 	}
 	out.ImageLookupOrg = in.ImageLookupOrg
 	// WARNING: in.ImageLookupBaseOS requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageLookupFormat requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageLookupSSMParameter requires manual conversion: does not exist in peer-type
 	out.InstanceType = in.InstanceType
+	// WARNING: in.InPlaceResize requires manual conversion: does not exist in peer-type
+	// WARNING: in.FallbackInstanceTypes requires manual conversion: does not exist in peer-type
 	out.AdditionalTags = *(*Tags)(unsafe.Pointer(&in.AdditionalTags))
 	out.IAMInstanceProfile = in.IAMInstanceProfile
 	out.PublicIP = (*bool)(unsafe.Pointer(in.PublicIP))
 	out.AdditionalSecurityGroups = *(*[]AWSResourceReference)(unsafe.Pointer(&in.AdditionalSecurityGroups))
 	// WARNING: in.FailureDomain requires manual conversion: does not exist in peer-type
+	// WARNING: in.FallbackFailureDomains requires manual conversion: does not exist in peer-type
 	out.Subnet = (*AWSResourceReference)(unsafe.Pointer(in.Subnet))
 	if err := v1.Convert_Pointer_string_To_string(&in.SSHKeyName, &out.SSHKeyName, s); err != nil {
 		return err
 	}
 	// WARNING: in.RootVolume requires manual conversion: does not exist in peer-type
+	// WARNING: in.AdditionalVolumes requires manual conversion: does not exist in peer-type
 	out.NetworkInterfaces = *(*[]string)(unsafe.Pointer(&in.NetworkInterfaces))
+	// WARNING: in.ManagedNetworkInterfaces requires manual conversion: does not exist in peer-type
+	// WARNING: in.PrivateIPAddress requires manual conversion: does not exist in peer-type
+	// WARNING: in.ElasticIP requires manual conversion: does not exist in peer-type
 	// WARNING: in.UncompressedUserData requires manual conversion: does not exist in peer-type
 	// WARNING: in.CloudInit requires manual conversion: inconvertible types (sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3.CloudInit vs *sigs.k8s.io/cluster-api-provider-aws/api/v1alpha2.CloudInit)
+	// WARNING: in.BootstrapFormat requires manual conversion: does not exist in peer-type
+	// WARNING: in.Placement requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstanceMetadataOptions requires manual conversion: does not exist in peer-type
+	// WARNING: in.SpotMarketOptions requires manual conversion: does not exist in peer-type
+	// WARNING: in.CapacityReservation requires manual conversion: does not exist in peer-type
+	// WARNING: in.DeletionPolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.DisableAPITermination requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -621,6 +643,15 @@ func autoConvert_v1alpha3_AWSMachineStatus_To_v1alpha2_AWSMachineStatus(in *v1al
 	out.Ready = in.Ready
 	out.Addresses = *(*[]corev1.NodeAddress)(unsafe.Pointer(&in.Addresses))
 	out.InstanceState = (*InstanceState)(unsafe.Pointer(in.InstanceState))
+	// WARNING: in.InstanceLifecycle requires manual conversion: does not exist in peer-type
+	// WARNING: in.VolumeAttachments requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstanceType requires manual conversion: does not exist in peer-type
+	// WARNING: in.Architecture requires manual conversion: does not exist in peer-type
+	// WARNING: in.CapacityReservationID requires manual conversion: does not exist in peer-type
+	// WARNING: in.Resize requires manual conversion: does not exist in peer-type
+	// WARNING: in.FailedResizeInstanceType requires manual conversion: does not exist in peer-type
+	// WARNING: in.ScheduledEvents requires manual conversion: does not exist in peer-type
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	// WARNING: in.FailureReason requires manual conversion: does not exist in peer-type
 	// WARNING: in.FailureMessage requires manual conversion: does not exist in peer-type
 	return nil
@@ -911,6 +942,7 @@ func autoConvert_v1alpha2_CloudInit_To_v1alpha3_CloudInit(in *CloudInit, out *v1
 
 func autoConvert_v1alpha3_CloudInit_To_v1alpha2_CloudInit(in *v1alpha3.CloudInit, out *CloudInit, s conversion.Scope) error {
 	// WARNING: in.InsecureSkipSecretsManager requires manual conversion: does not exist in peer-type
+	// WARNING: in.SecureSecretsBackend requires manual conversion: does not exist in peer-type
 	out.SecretCount = in.SecretCount
 	out.SecretPrefix = in.SecretPrefix
 	return nil
@@ -1005,7 +1037,19 @@ func autoConvert_v1alpha3_Instance_To_v1alpha2_Instance(in *v1alpha3.Instance, o
 	out.ENASupport = (*bool)(unsafe.Pointer(in.ENASupport))
 	out.EBSOptimized = (*bool)(unsafe.Pointer(in.EBSOptimized))
 	// WARNING: in.RootVolume requires manual conversion: does not exist in peer-type
+	// WARNING: in.AdditionalVolumes requires manual conversion: does not exist in peer-type
+	// WARNING: in.VolumeAttachments requires manual conversion: does not exist in peer-type
 	out.NetworkInterfaces = *(*[]string)(unsafe.Pointer(&in.NetworkInterfaces))
+	// WARNING: in.SecondaryNetworkInterfaces requires manual conversion: does not exist in peer-type
+	// WARNING: in.Placement requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstanceMetadataOptions requires manual conversion: does not exist in peer-type
+	// WARNING: in.SpotMarketOptions requires manual conversion: does not exist in peer-type
+	// WARNING: in.CapacityReservation requires manual conversion: does not exist in peer-type
+	// WARNING: in.DisableAPITermination requires manual conversion: does not exist in peer-type
+	// WARNING: in.Lifecycle requires manual conversion: does not exist in peer-type
+	// WARNING: in.Architecture requires manual conversion: does not exist in peer-type
+	// WARNING: in.SpotInstanceRequestID requires manual conversion: does not exist in peer-type
+	// WARNING: in.CapacityReservationID requires manual conversion: does not exist in peer-type
 	out.Tags = *(*map[string]string)(unsafe.Pointer(&in.Tags))
 	return nil
 }
@@ -35,7 +35,8 @@ type AWSClusterSpec struct {
 	// The AWS Region the cluster lives in.
 	Region string `json:"region,omitempty"`
 
-	// SSHKeyName is the name of the ssh key to attach to the bastion host. Valid values are empty string (do not use SSH keys), a valid SSH key name, or omitted (use the default SSH key name)
+	// SSHKeyName is the name of the ssh key to attach to the bastion host and to the machines that do not specify one. Valid values are empty string (do not use SSH keys), a valid SSH key name, or omitted (use the default SSH key name)
+	// When no machine of the cluster uses an SSH key, SSH ingress rules are not added to the control plane and node security groups.
 	// +optional
 	SSHKeyName *string `json:"sshKeyName,omitempty"`
 
@@ -64,9 +65,85 @@ type AWSClusterSpec struct {
 	// different ImageLookupBaseOS.
 	ImageLookupBaseOS string `json:"imageLookupBaseOS,omitempty"`
 
+	// ImageLookupFormat is the AMI naming format used to look up machine images
+	// when a machine does not specify an AMI. When set, this will be used for
+	// all cluster machines unless a machine specifies a different
+	// ImageLookupFormat. See AWSMachineSpec.ImageLookupFormat for the
+	// supported variables.
+	// +optional
+	ImageLookupFormat string `json:"imageLookupFormat,omitempty"`
+
+	// ImageLookupSSMParameter is the name of an SSM Parameter Store parameter
+	// holding the AMI ID to use when a machine does not specify an AMI. When
+	// set, this will be used for all cluster machines unless a machine
+	// specifies a different ImageLookupSSMParameter.
+	// +optional
+	ImageLookupSSMParameter string `json:"imageLookupSSMParameter,omitempty"`
+
 	// Bastion contains options to configure the bastion host.
 	// +optional
 	Bastion Bastion `json:"bastion"`
+
+	// PlacementGroups is an optional list of placement groups that are created and deleted
+	// along with the cluster. Machines are launched into them by setting spec.placement.groupName.
+	// +optional
+	PlacementGroups []PlacementGroupSpec `json:"placementGroups,omitempty"`
+
+	// AccessMode is how the cluster machines are accessed. With SSH, the default, machines are
+	// reachable with SSH through the bastion host, if enabled. With SessionManager, machines are
+	// only reachable through AWS Systems Manager Session Manager: the bastion host is not created,
+	// SSH ingress rules are not added to the security groups and machines are not given the
+	// default SSH key.
+	// +kubebuilder:validation:Enum=SSH;SessionManager
+	// +optional
+	AccessMode ClusterAccessMode `json:"accessMode,omitempty"`
+
+	// SessionManager contains options for the SessionManager access mode.
+	// +optional
+	SessionManager *SessionManagerSpec `json:"sessionManager,omitempty"`
+
+	// S3Bucket is an existing S3 bucket in which the bootstrap data of the machines using
+	// the s3 secure secrets backend is stored until they join the cluster.
+	// +optional
+	S3Bucket *S3Bucket `json:"s3Bucket,omitempty"`
+}
+
+// ClusterAccessMode defines how the machines of a cluster are accessed.
+type ClusterAccessMode string
+
+var (
+	// ClusterAccessModeSSH is the access mode where machines are accessed with SSH.
+	ClusterAccessModeSSH = ClusterAccessMode("SSH")
+
+	// ClusterAccessModeSessionManager is the access mode where machines are accessed
+	// through AWS Systems Manager Session Manager.
+	ClusterAccessModeSessionManager = ClusterAccessMode("SessionManager")
+)
+
+// SessionManagerSpec defines the options of the SessionManager access mode.
+type SessionManagerSpec struct {
+	// CreateVPCEndpoints creates the VPC interface endpoints of the ssm, ssmmessages and
+	// ec2messages services in the private subnets of the cluster, so that machines can
+	// reach Session Manager without going through a NAT gateway. Only applies to
+	// managed VPCs.
+	// +optional
+	CreateVPCEndpoints bool `json:"createVPCEndpoints,omitempty"`
+}
+
+// S3Bucket defines the S3 bucket used to deliver bootstrap data.
+type S3Bucket struct {
+	// Name is the name of the bucket. The bucket is not created nor deleted
+	// by the controller, and can be shared between clusters: objects are
+	// stored under a prefix specific to each cluster.
+	// +kubebuilder:validation:MinLength=3
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// KMSKeyID is the ID or ARN of the KMS key used to encrypt the objects.
+	// Defaults to the AWS managed key for S3. When set, the nodes IAM role
+	// must be allowed to use the key to decrypt the bootstrap data.
+	// +optional
+	KMSKeyID string `json:"kmsKeyID,omitempty"`
 }
 
 type Bastion struct {
@@ -74,6 +151,36 @@ type Bastion struct {
 	// with a public ip to access the VPC private network.
 	// +optional
 	Enabled bool `json:"enabled"`
+
+	// InstanceMetadataOptions configures the Instance Metadata Service (IMDS) of the bastion host.
+	// Changes are applied to the running bastion host.
+	// +optional
+	InstanceMetadataOptions *InstanceMetadataOptions `json:"instanceMetadataOptions,omitempty"`
+
+	// InstanceType is the instance type of the bastion host. Defaults to t2.micro.
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// AMI is the reference to the AMI the bastion host is launched from. Only the ID of
+	// the reference is supported. If not set, the AMI is looked up from ImageLookupSSMParameter.
+	// +optional
+	AMI AWSResourceReference `json:"ami,omitempty"`
+
+	// ImageLookupSSMParameter is the name of an SSM Parameter Store parameter holding
+	// the ID of the AMI the bastion host is launched from, when AMI is not set.
+	// Defaults to the public parameter of the latest Ubuntu 18.04 AMI published by Canonical.
+	// +optional
+	ImageLookupSSMParameter string `json:"imageLookupSSMParameter,omitempty"`
+
+	// AllowedCIDRBlocks is the list of CIDR blocks SSH access to the bastion host is
+	// allowed from. Defaults to 0.0.0.0/0.
+	// +optional
+	AllowedCIDRBlocks []string `json:"allowedCIDRBlocks,omitempty"`
+
+	// AdditionalTags is an optional set of tags to add to the bastion host, in addition
+	// to the ones added by default and the additional tags of the cluster.
+	// +optional
+	AdditionalTags Tags `json:"additionalTags,omitempty"`
 }
 
 // AWSLoadBalancerSpec defines the desired state of an AWS load balancer
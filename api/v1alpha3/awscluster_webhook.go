@@ -17,8 +17,13 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"net"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 // log is for logging in this package.
@@ -29,3 +34,65 @@ func (r *AWSCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
 		For(r).
 		Complete()
 }
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha3-awscluster,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsclusters,versions=v1alpha3,name=validation.awscluster.infrastructure.cluster.x-k8s.io
+
+var _ webhook.Validator = &AWSCluster{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *AWSCluster) ValidateCreate() error {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, r.validatePlacementGroups()...)
+	allErrs = append(allErrs, r.validateBastion()...)
+
+	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *AWSCluster) ValidateUpdate(old runtime.Object) error {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, r.validatePlacementGroups()...)
+	allErrs = append(allErrs, r.validateBastion()...)
+
+	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *AWSCluster) ValidateDelete() error {
+	return nil
+}
+
+func (r *AWSCluster) validatePlacementGroups() field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, pg := range r.Spec.PlacementGroups {
+		if pg.PartitionCount != nil && pg.Strategy != PlacementGroupStrategyPartition {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "placementGroups").Index(i).Child("partitionCount"),
+				"partitionCount is only valid if the strategy is partition"))
+		}
+	}
+
+	return allErrs
+}
+
+func (r *AWSCluster) validateBastion() field.ErrorList {
+	var allErrs field.ErrorList
+
+	amiPath := field.NewPath("spec", "bastion", "ami")
+	if r.Spec.Bastion.AMI.ARN != nil {
+		allErrs = append(allErrs, field.Forbidden(amiPath.Child("arn"), "only an AMI ID is supported for the bastion host"))
+	}
+	if len(r.Spec.Bastion.AMI.Filters) > 0 {
+		allErrs = append(allErrs, field.Forbidden(amiPath.Child("filters"), "only an AMI ID is supported for the bastion host"))
+	}
+
+	for i, cidr := range r.Spec.Bastion.AllowedCIDRBlocks {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "bastion", "allowedCIDRBlocks").Index(i), cidr, "must be a valid CIDR block"))
+		}
+	}
+
+	return allErrs
+}
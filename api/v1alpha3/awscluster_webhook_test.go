@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	"testing"
+
+	"k8s.io/utils/pointer"
+)
+
+func TestAWSCluster_ValidateCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster *AWSCluster
+		wantErr bool
+	}{
+		{
+			name: "partition count with the partition strategy",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					PlacementGroups: []PlacementGroupSpec{
+						{Name: "pg", Strategy: PlacementGroupStrategyPartition, PartitionCount: pointer.Int64Ptr(3)},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "no partition count with the spread strategy",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					PlacementGroups: []PlacementGroupSpec{
+						{Name: "pg", Strategy: PlacementGroupStrategySpread},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "partition count with the cluster strategy",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					PlacementGroups: []PlacementGroupSpec{
+						{Name: "pg", Strategy: PlacementGroupStrategyCluster, PartitionCount: pointer.Int64Ptr(3)},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "bastion AMI given by ID",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					Bastion: Bastion{AMI: AWSResourceReference{ID: pointer.StringPtr("ami-1")}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "bastion AMI given by ARN",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					Bastion: Bastion{AMI: AWSResourceReference{ARN: pointer.StringPtr("arn:aws:ec2:us-east-1::image/ami-1")}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "bastion AMI given by filters",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					Bastion: Bastion{AMI: AWSResourceReference{Filters: []Filter{{Name: "name", Values: []string{"bastion"}}}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "bastion allowed CIDR blocks are valid",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					Bastion: Bastion{AllowedCIDRBlocks: []string{"203.0.113.0/24", "2001:db8::/32"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "bastion allowed CIDR block is not a CIDR",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					Bastion: Bastion{AllowedCIDRBlocks: []string{"203.0.113.0/24", "203.0.113.1"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cluster.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -28,6 +28,11 @@ const (
 	MachineFinalizer = "awsmachine.infrastructure.cluster.x-k8s.io"
 )
 
+const (
+	// SpotInstanceTerminationError is the failure reason set when EC2 reclaims a spot instance.
+	SpotInstanceTerminationError errors.MachineStatusError = "SpotInstanceTermination"
+)
+
 // AWSMachineSpec defines the desired state of AWSMachine
 type AWSMachineSpec struct {
 	// ProviderID is the unique identifier as specified by the cloud provider.
@@ -43,9 +48,38 @@ type AWSMachineSpec struct {
 	// image lookup the AMI is not set.
 	ImageLookupBaseOS string `json:"imageLookupBaseOS,omitempty"`
 
+	// ImageLookupFormat is the AMI naming format to look up the image for this
+	// machine. It is a Go text/template that is evaluated with the
+	// .BaseOS and .K8sVersion variables, where .K8sVersion has no "v" prefix.
+	// Shell-style wildcards may be used. If unset, the format defined on the
+	// AWSCluster is used, or "capa-ami-{{.BaseOS}}-{{.K8sVersion}}-??-??????????".
+	// +optional
+	ImageLookupFormat string `json:"imageLookupFormat,omitempty"`
+
+	// ImageLookupSSMParameter is the name of an SSM Parameter Store parameter
+	// holding the AMI ID for this machine, for example
+	// "/my-org/images/{{.BaseOS}}/{{.K8sVersion}}/image_id". It is a Go
+	// text/template evaluated with the same variables as ImageLookupFormat.
+	// When set, it takes precedence over looking up the image by name.
+	// +optional
+	ImageLookupSSMParameter string `json:"imageLookupSSMParameter,omitempty"`
+
 	// InstanceType is the type of instance to create. Example: m4.xlarge
 	InstanceType string `json:"instanceType,omitempty"`
 
+	// InPlaceResize enables changes to InstanceType and RootVolume.Size after the instance
+	// was created. The changes are applied by stopping the instance, modifying it and starting
+	// it again, instead of replacing the machine. Instances running one of the
+	// FallbackInstanceTypes are not resized to InstanceType.
+	// +optional
+	InPlaceResize bool `json:"inPlaceResize,omitempty"`
+
+	// FallbackInstanceTypes is an ordered list of instance types to try when an
+	// instance of InstanceType cannot be launched due to insufficient capacity.
+	// Instance types that do not support the architecture of the AMI are skipped.
+	// +optional
+	FallbackInstanceTypes []string `json:"fallbackInstanceTypes,omitempty"`
+
 	// AdditionalTags is an optional set of tags to add to an instance, in addition to the ones added by default by the
 	// AWS provider. If both the AWSCluster and the AWSMachine specify the same tag name with different values, the
 	// AWSMachine's value takes precedence.
@@ -75,12 +109,18 @@ type AWSMachineSpec struct {
 	// If multiple subnets are matched for the availability zone, the first one returned is picked.
 	FailureDomain *string `json:"failureDomain,omitempty"`
 
+	// FallbackFailureDomains is an ordered list of failure domains to try when the
+	// instance cannot be launched in the machine's failure domain due to
+	// insufficient capacity. It is ignored when a subnet is specified.
+	// +optional
+	FallbackFailureDomains []string `json:"fallbackFailureDomains,omitempty"`
+
 	// Subnet is a reference to the subnet to use for this instance. If not specified,
 	// the cluster subnet will be used.
 	// +optional
 	Subnet *AWSResourceReference `json:"subnet,omitempty"`
 
-	// SSHKeyName is the name of the ssh key to attach to the instance. Valid values are empty string (do not use SSH keys), a valid SSH key name, or omitted (use the default SSH key name)
+	// SSHKeyName is the name of the ssh key to attach to the instance. Valid values are empty string (do not use SSH keys), a valid SSH key name, or omitted (use the SSH key name of the cluster)
 	// +optional
 	SSHKeyName *string `json:"sshKeyName,omitempty"`
 
@@ -88,12 +128,33 @@ type AWSMachineSpec struct {
 	// +optional
 	RootVolume *RootVolume `json:"rootVolume,omitempty"`
 
+	// AdditionalVolumes is a list of additional EBS volumes to attach to the instance.
+	// The volumes are deleted when the instance is terminated.
+	// +optional
+	AdditionalVolumes []Volume `json:"additionalVolumes,omitempty"`
+
 	// NetworkInterfaces is a list of ENIs to associate with the instance.
 	// A maximum of 2 may be specified.
 	// +optional
 	// +kubebuilder:validation:MaxItems=2
 	NetworkInterfaces []string `json:"networkInterfaces,omitempty"`
 
+	// ManagedNetworkInterfaces is a list of network interfaces to create for the instance.
+	// They are attached in order after the primary network interface and NetworkInterfaces,
+	// and deleted when the machine is deleted.
+	// +optional
+	ManagedNetworkInterfaces []ManagedNetworkInterface `json:"managedNetworkInterfaces,omitempty"`
+
+	// PrivateIPAddress is the static primary private IPv4 address to assign to the instance.
+	// It must be within the range of the subnet the instance is launched in, and cannot be
+	// combined with NetworkInterfaces.
+	// +optional
+	PrivateIPAddress *string `json:"privateIPAddress,omitempty"`
+
+	// ElasticIP configures an Elastic IP address to associate with the instance.
+	// +optional
+	ElasticIP *ElasticIP `json:"elasticIP,omitempty"`
+
 	// UncompressedUserData specify whether the user data is gzip-compressed before it is sent to ec2 instance.
 	// cloud-init has built-in support for gzip-compressed user data
 	// user data stored in aws secret manager is always gzip-compressed.
@@ -105,28 +166,98 @@ type AWSMachineSpec struct {
 	// CloudInit is used.
 	// +optional
 	CloudInit CloudInit `json:"cloudInit,omitempty"`
+
+	// BootstrapFormat is the format of the bootstrap data of the machine. Defaults to
+	// cloud-config. Machines running Flatcar Container Linux or Fedora CoreOS use ignition:
+	// the userdata is then an Ignition config, which is never compressed. With the s3
+	// secure secrets backend, the only one supported by ignition, it references the
+	// compressed bootstrap data stored in S3 instead of including a cloud-init boothook.
+	// +kubebuilder:validation:Enum=cloud-config;ignition
+	// +optional
+	BootstrapFormat BootstrapFormat `json:"bootstrapFormat,omitempty"`
+
+	// Placement configures the placement group, tenancy and dedicated host of the instance.
+	// +optional
+	Placement *Placement `json:"placement,omitempty"`
+
+	// InstanceMetadataOptions configures the Instance Metadata Service (IMDS) of the instance.
+	// Changes are applied to the running instance.
+	// +optional
+	InstanceMetadataOptions *InstanceMetadataOptions `json:"instanceMetadataOptions,omitempty"`
+
+	// SpotMarketOptions allows users to configure instances to be run using AWS Spot instances.
+	// +optional
+	SpotMarketOptions *SpotMarketOptions `json:"spotMarketOptions,omitempty"`
+
+	// CapacityReservation configures whether the instance runs in an On-Demand Capacity Reservation.
+	// +optional
+	CapacityReservation *CapacityReservation `json:"capacityReservation,omitempty"`
+
+	// DeletionPolicy defines what happens to the instance when the AWSMachine is deleted.
+	// Retained instances are no longer owned by the cluster. Defaults to Terminate.
+	// +kubebuilder:validation:Enum=Terminate;StopAndRetain;Retain
+	// +optional
+	DeletionPolicy MachineDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// DisableAPITermination enables termination protection on the instance when it is launched,
+	// guarding it against termination outside of the cluster. The protection is lifted when the
+	// AWSMachine is deleted with the Terminate deletion policy.
+	// +optional
+	DisableAPITermination bool `json:"disableApiTermination,omitempty"`
 }
 
 // CloudInit defines options related to the bootstrapping systems where
 // CloudInit is used.
 type CloudInit struct {
 	// InsecureSkipSecretsManager, when set to true will not use AWS Secrets Manager
-	// to ensure privacy of userdata.
+	// nor any other secure secrets backend to ensure privacy of userdata.
 	// By default, a cloud-init boothook shell script is prepended to download
 	// the userdata from Secrets Manager and additionally delete the secret.
 	InsecureSkipSecretsManager bool `json:"insecureSkipSecretsManager,omitempty"`
 
-	// SecretCount is the number of secrets used to form the complete secret
+	// SecureSecretsBackend is the backend storing the userdata until the machine
+	// joins the cluster. Defaults to secrets-manager, which splits the userdata in
+	// chunks stored as AWS Secrets Manager secrets. With s3, the userdata is stored
+	// as a single object in the S3 bucket of the cluster, which is better suited
+	// to large userdata.
+	// +kubebuilder:validation:Enum=secrets-manager;s3
+	// +optional
+	SecureSecretsBackend SecretBackend `json:"secureSecretsBackend,omitempty"`
+
+	// SecretCount is the number of secrets used to form the complete secret.
+	// Always 1 with the s3 backend.
 	// +optional
 	SecretCount int32 `json:"secretCount,omitempty"`
 
-	// SecretPrefix is the prefix for the secret name. This is stored
-	// temporarily, and deleted when the machine registers as a node against
-	// the workload cluster.
+	// SecretPrefix is the prefix for the secret name, or the object key with
+	// the s3 backend. This is stored temporarily, and deleted when the machine
+	// registers as a node against the workload cluster.
 	// +optional
 	SecretPrefix string `json:"secretPrefix,omitempty"`
 }
 
+// BootstrapFormat defines the format of the bootstrap data of a machine.
+type BootstrapFormat string
+
+var (
+	// BootstrapFormatCloudConfig is the format of bootstrap data processed by cloud-init.
+	BootstrapFormatCloudConfig = BootstrapFormat("cloud-config")
+
+	// BootstrapFormatIgnition is the format of bootstrap data processed by Ignition.
+	BootstrapFormatIgnition = BootstrapFormat("ignition")
+)
+
+// SecretBackend defines the backend storing the userdata of a machine until it joins the cluster.
+type SecretBackend string
+
+var (
+	// SecretBackendSecretsManager is the backend storing userdata as AWS Secrets Manager secrets.
+	SecretBackendSecretsManager = SecretBackend("secrets-manager")
+
+	// SecretBackendS3 is the backend storing userdata as an object in the S3 bucket of the cluster.
+	SecretBackendS3 = SecretBackend("s3")
+)
+
 // AWSMachineStatus defines the observed state of AWSMachine
 type AWSMachineStatus struct {
 	// Ready is true when the provider resource is ready.
@@ -140,6 +271,46 @@ type AWSMachineStatus struct {
 	// +optional
 	InstanceState *InstanceState `json:"instanceState,omitempty"`
 
+	// InstanceLifecycle indicates whether the AWS instance is a spot or an on-demand instance.
+	// +optional
+	InstanceLifecycle *InstanceLifecycle `json:"instanceLifecycle,omitempty"`
+
+	// VolumeAttachments are the EBS volumes attached to the AWS instance for this machine.
+	// +optional
+	VolumeAttachments []VolumeAttachment `json:"volumeAttachments,omitempty"`
+
+	// InstanceType is the type of the AWS instance that was launched for this machine.
+	// It differs from the spec when a fallback instance type was used.
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// Architecture is the processor architecture of the AWS instance for this machine,
+	// as resolved from its instance type.
+	// +optional
+	Architecture string `json:"architecture,omitempty"`
+
+	// CapacityReservationID is the ID of the Capacity Reservation the AWS instance for this machine
+	// is running in, if any.
+	// +optional
+	CapacityReservationID *string `json:"capacityReservationId,omitempty"`
+
+	// Resize describes the in-place resize of the AWS instance for this machine in progress, if any.
+	// +optional
+	Resize *InstanceResize `json:"resize,omitempty"`
+
+	// FailedResizeInstanceType is the instance type AWS rejected during the last in-place resize
+	// of the AWS instance for this machine. It is not attempted again until the spec changes.
+	// +optional
+	FailedResizeInstanceType string `json:"failedResizeInstanceType,omitempty"`
+
+	// ScheduledEvents are the events AWS has scheduled for the AWS instance of this machine.
+	// +optional
+	ScheduledEvents []InstanceScheduledEvent `json:"scheduledEvents,omitempty"`
+
+	// Conditions describe the progress of the reconciliation of the AWS instance for this machine.
+	// +optional
+	Conditions []AWSMachineProviderCondition `json:"conditions,omitempty"`
+
 	// FailureReason will be set in the event that there is a terminal problem
 	// reconciling the Machine and will contain a succinct value suitable
 	// for machine interpretation.
@@ -188,6 +359,7 @@ type AWSMachineStatus struct {
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Machine ready status"
 // +kubebuilder:printcolumn:name="InstanceID",type="string",JSONPath=".spec.providerID",description="EC2 instance ID"
 // +kubebuilder:printcolumn:name="Machine",type="string",JSONPath=".metadata.ownerReferences[?(@.kind==\"Machine\")].name",description="Machine object which owns with this AWSMachine"
+// +kubebuilder:printcolumn:name="CapacityReservation",type="string",JSONPath=".status.capacityReservationId",description="Capacity Reservation the EC2 instance is running in",priority=1
 
 // AWSMachine is the Schema for the awsmachines API
 type AWSMachine struct {
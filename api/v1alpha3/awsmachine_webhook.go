@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"net"
 	"reflect"
 
 	"github.com/pkg/errors"
@@ -47,6 +48,14 @@ func (r *AWSMachine) ValidateCreate() error {
 
 	allErrs = append(allErrs, r.validateCloudInitSecret()...)
 	allErrs = append(allErrs, r.validateVolumeTypeIOPS()...)
+	allErrs = append(allErrs, r.validateAdditionalVolumes()...)
+	allErrs = append(allErrs, r.validatePlacement()...)
+	allErrs = append(allErrs, r.validateAdditionalSecurityGroups()...)
+	allErrs = append(allErrs, r.validatePrivateIPAddress()...)
+	allErrs = append(allErrs, r.validateManagedNetworkInterfaces()...)
+	allErrs = append(allErrs, r.validateInPlaceResize()...)
+	allErrs = append(allErrs, r.validateDeletionPolicy()...)
+	allErrs = append(allErrs, r.validateCapacityReservation()...)
 
 	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
@@ -69,6 +78,9 @@ func (r *AWSMachine) ValidateUpdate(old runtime.Object) error {
 	var allErrs field.ErrorList
 
 	allErrs = append(allErrs, r.validateCloudInitSecret()...)
+	allErrs = append(allErrs, r.validateAdditionalSecurityGroups()...)
+	allErrs = append(allErrs, r.validateInPlaceResize()...)
+	allErrs = append(allErrs, r.validateDeletionPolicy()...)
 
 	newAWSMachineSpec := newAWSMachine["spec"].(map[string]interface{})
 	oldAWSMachineSpec := oldAWSMachine["spec"].(map[string]interface{})
@@ -85,6 +97,36 @@ func (r *AWSMachine) ValidateUpdate(old runtime.Object) error {
 	delete(oldAWSMachineSpec, "additionalSecurityGroups")
 	delete(newAWSMachineSpec, "additionalSecurityGroups")
 
+	// allow changes to instanceMetadataOptions
+	delete(oldAWSMachineSpec, "instanceMetadataOptions")
+	delete(newAWSMachineSpec, "instanceMetadataOptions")
+
+	// allow changes to inPlaceResize
+	delete(oldAWSMachineSpec, "inPlaceResize")
+	delete(newAWSMachineSpec, "inPlaceResize")
+
+	// allow changes to deletionPolicy
+	delete(oldAWSMachineSpec, "deletionPolicy")
+	delete(newAWSMachineSpec, "deletionPolicy")
+
+	// allow changes to instanceType & rootVolume.size if the instance is resized in place
+	if r.Spec.InPlaceResize {
+		allErrs = append(allErrs, r.validateRootVolumeResize(old.(*AWSMachine))...)
+
+		delete(oldAWSMachineSpec, "instanceType")
+		delete(newAWSMachineSpec, "instanceType")
+
+		for _, spec := range []map[string]interface{}{oldAWSMachineSpec, newAWSMachineSpec} {
+			if rootVolume, ok := spec["rootVolume"].(map[string]interface{}); ok {
+				delete(rootVolume, "size")
+				// a root volume with only a size set is the same as no root volume
+				if len(rootVolume) == 0 {
+					delete(spec, "rootVolume")
+				}
+			}
+		}
+	}
+
 	// allow changes to secretPrefix & secretCount
 	if cloudInit, ok := oldAWSMachineSpec["cloudInit"].(map[string]interface{}); ok {
 		delete(cloudInit, "secretPrefix")
@@ -113,6 +155,14 @@ func (r *AWSMachine) validateCloudInitSecret() field.ErrorList {
 		if r.Spec.CloudInit.SecretCount != 0 {
 			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "cloudInit", "secretCount"), "cannot be set if spec.cloudInit.insecureSkipSecretsManager is true"))
 		}
+		if r.Spec.CloudInit.SecureSecretsBackend != "" {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "cloudInit", "secureSecretsBackend"), "cannot be set if spec.cloudInit.insecureSkipSecretsManager is true"))
+		}
+	}
+
+	// Ignition can only fetch bootstrap data stored in S3.
+	if r.Spec.BootstrapFormat == BootstrapFormatIgnition && !r.Spec.CloudInit.InsecureSkipSecretsManager && r.Spec.CloudInit.SecureSecretsBackend != SecretBackendS3 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "cloudInit", "secureSecretsBackend"), r.Spec.CloudInit.SecureSecretsBackend, "must be s3 if spec.bootstrapFormat is ignition, unless spec.cloudInit.insecureSkipSecretsManager is true"))
 	}
 
 	if (r.Spec.CloudInit.SecretPrefix != "") != (r.Spec.CloudInit.SecretCount != 0) {
@@ -132,6 +182,170 @@ func (r *AWSMachine) validateVolumeTypeIOPS() field.ErrorList {
 	return allErrs
 }
 
+func (r *AWSMachine) validateAdditionalVolumes() field.ErrorList {
+	var allErrs field.ErrorList
+
+	deviceNames := map[string]bool{}
+	for i, volume := range r.Spec.AdditionalVolumes {
+		volumePath := field.NewPath("spec", "additionalVolumes").Index(i)
+
+		if volume.DeviceName == "" {
+			allErrs = append(allErrs, field.Required(volumePath.Child("deviceName"), "deviceName is required"))
+		} else if deviceNames[volume.DeviceName] {
+			allErrs = append(allErrs, field.Duplicate(volumePath.Child("deviceName"), volume.DeviceName))
+		}
+		deviceNames[volume.DeviceName] = true
+
+		if volume.Type == "io1" && volume.IOPS == 0 {
+			allErrs = append(allErrs, field.Required(volumePath.Child("iops"), "iops required if type is 'io1'"))
+		}
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validatePlacement() field.ErrorList {
+	var allErrs field.ErrorList
+
+	placement := r.Spec.Placement
+	if placement == nil {
+		return allErrs
+	}
+
+	placementPath := field.NewPath("spec", "placement")
+
+	if placement.PartitionNumber != nil && placement.GroupName == "" {
+		allErrs = append(allErrs, field.Required(placementPath.Child("groupName"), "groupName required if partitionNumber is set"))
+	}
+
+	if placement.HostID != "" && placement.HostResourceGroupARN != "" {
+		allErrs = append(allErrs, field.Forbidden(placementPath.Child("hostResourceGroupARN"), "cannot be set together with spec.placement.hostID"))
+	}
+
+	if (placement.HostID != "" || placement.HostResourceGroupARN != "") && placement.Tenancy != "host" {
+		allErrs = append(allErrs, field.Invalid(placementPath.Child("tenancy"), placement.Tenancy, "tenancy must be 'host' if hostID or hostResourceGroupARN is set"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validateAdditionalSecurityGroups() field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, sg := range r.Spec.AdditionalSecurityGroups {
+		allErrs = append(allErrs, validateResourceReference(field.NewPath("spec", "additionalSecurityGroups").Index(i), sg)...)
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validateManagedNetworkInterfaces() field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, eni := range r.Spec.ManagedNetworkInterfaces {
+		eniPath := field.NewPath("spec", "managedNetworkInterfaces").Index(i)
+		if eni.Subnet != nil {
+			allErrs = append(allErrs, validateResourceReference(eniPath.Child("subnet"), *eni.Subnet)...)
+		}
+		for j, sg := range eni.SecurityGroups {
+			allErrs = append(allErrs, validateResourceReference(eniPath.Child("securityGroups").Index(j), sg)...)
+		}
+	}
+
+	return allErrs
+}
+
+// validateResourceReference checks that exactly one of the ID, ARN or filters of the reference is set.
+func validateResourceReference(path *field.Path, ref AWSResourceReference) field.ErrorList {
+	set := 0
+	if ref.ID != nil {
+		set++
+	}
+	if ref.ARN != nil {
+		set++
+	}
+	if len(ref.Filters) > 0 {
+		set++
+	}
+
+	if set != 1 {
+		return field.ErrorList{field.Invalid(path, ref, "exactly one of id, arn or filters must be set")}
+	}
+	return nil
+}
+
+func (r *AWSMachine) validateInPlaceResize() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.InPlaceResize && r.Spec.SpotMarketOptions != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "inPlaceResize"), "cannot be set together with spec.spotMarketOptions"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validateDeletionPolicy() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.SpotMarketOptions == nil {
+		return allErrs
+	}
+
+	if r.Spec.DeletionPolicy == MachineDeletionPolicyStopAndRetain {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "deletionPolicy"), "spot instances cannot be stopped and retained"))
+	}
+	if r.Spec.DisableAPITermination {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "disableApiTermination"), "cannot be set together with spec.spotMarketOptions"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validateCapacityReservation() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.CapacityReservation == nil {
+		return allErrs
+	}
+
+	if r.Spec.CapacityReservation.ID != nil && r.Spec.CapacityReservation.Preference != "" {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "capacityReservation", "preference"), "cannot be set together with spec.capacityReservation.id"))
+	}
+	if r.Spec.SpotMarketOptions != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "capacityReservation"), "cannot be set together with spec.spotMarketOptions"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validateRootVolumeResize(old *AWSMachine) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.RootVolume != nil && old.Spec.RootVolume != nil && r.Spec.RootVolume.Size < old.Spec.RootVolume.Size {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "rootVolume", "size"), r.Spec.RootVolume.Size, "cannot be decreased"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validatePrivateIPAddress() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.PrivateIPAddress == nil {
+		return allErrs
+	}
+
+	ipPath := field.NewPath("spec", "privateIPAddress")
+	if ip := net.ParseIP(*r.Spec.PrivateIPAddress); ip == nil || ip.To4() == nil {
+		allErrs = append(allErrs, field.Invalid(ipPath, *r.Spec.PrivateIPAddress, "must be a valid IPv4 address"))
+	}
+	if len(r.Spec.NetworkInterfaces) > 0 {
+		allErrs = append(allErrs, field.Forbidden(ipPath, "cannot be set together with spec.networkInterfaces"))
+	}
+
+	return allErrs
+}
+
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
 func (r *AWSMachine) ValidateDelete() error {
 	return nil
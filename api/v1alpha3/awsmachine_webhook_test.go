@@ -39,6 +39,346 @@ func TestAWSMachine_ValidateCreate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "additional volumes are valid",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					AdditionalVolumes: []Volume{
+						{
+							DeviceName: "/dev/sdb",
+							Size:       20,
+							Type:       "io1",
+							IOPS:       1000,
+						},
+						{
+							DeviceName: "/dev/sdc",
+							Size:       50,
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ensure IOPS exists if additional volume type equal to io1",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					AdditionalVolumes: []Volume{
+						{
+							DeviceName: "/dev/sdb",
+							Size:       20,
+							Type:       "io1",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure additional volumes have a device name",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					AdditionalVolumes: []Volume{
+						{
+							Size: 20,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure additional volumes have unique device names",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					AdditionalVolumes: []Volume{
+						{
+							DeviceName: "/dev/sdb",
+							Size:       20,
+						},
+						{
+							DeviceName: "/dev/sdb",
+							Size:       50,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "placement on a dedicated host is valid",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					Placement: &Placement{
+						Tenancy: "host",
+						HostID:  "h-1",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ensure host tenancy if a dedicated host is set",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					Placement: &Placement{
+						Tenancy: "dedicated",
+						HostID:  "h-1",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure group name if a partition number is set",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					Placement: &Placement{
+						PartitionNumber: pointer.Int64Ptr(1),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "additional security groups by id, arn and filters are valid",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					AdditionalSecurityGroups: []AWSResourceReference{
+						{
+							ID: pointer.StringPtr("sg-1"),
+						},
+						{
+							ARN: pointer.StringPtr("arn:aws:ec2:us-east-1:123456789012:security-group/sg-2"),
+						},
+						{
+							Filters: []Filter{
+								{
+									Name:   "tag:Name",
+									Values: []string{"corp-monitoring"},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ensure additional security groups set only one of id, arn or filters",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					AdditionalSecurityGroups: []AWSResourceReference{
+						{
+							ID: pointer.StringPtr("sg-1"),
+							Filters: []Filter{
+								{
+									Name:   "tag:Name",
+									Values: []string{"corp-monitoring"},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure additional security groups set one of id, arn or filters",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					AdditionalSecurityGroups: []AWSResourceReference{
+						{},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "static private IP address is valid",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					PrivateIPAddress: pointer.StringPtr("10.0.1.10"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ensure private IP address is a valid IPv4 address",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					PrivateIPAddress: pointer.StringPtr("fd00::10"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure private IP address is not set together with network interfaces",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					PrivateIPAddress:  pointer.StringPtr("10.0.1.10"),
+					NetworkInterfaces: []string{"eni-1"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "managed network interfaces with subnet and security group references are valid",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					ManagedNetworkInterfaces: []ManagedNetworkInterface{
+						{},
+						{
+							Subnet:         &AWSResourceReference{ID: pointer.StringPtr("subnet-1")},
+							SecurityGroups: []AWSResourceReference{{ID: pointer.StringPtr("sg-1")}},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ensure managed network interface subnet sets one of id, arn or filters",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					ManagedNetworkInterfaces: []ManagedNetworkInterface{
+						{
+							Subnet: &AWSResourceReference{},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure managed network interface security groups set only one of id, arn or filters",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					ManagedNetworkInterfaces: []ManagedNetworkInterface{
+						{
+							SecurityGroups: []AWSResourceReference{
+								{
+									ID:  pointer.StringPtr("sg-1"),
+									ARN: pointer.StringPtr("arn:aws:ec2:us-east-1:123456789012:security-group/sg-1"),
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure termination protection is not enabled for spot instances",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					SpotMarketOptions:     &SpotMarketOptions{},
+					DisableAPITermination: true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure spot instances are not stopped and retained",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					SpotMarketOptions: &SpotMarketOptions{},
+					DeletionPolicy:    MachineDeletionPolicyStopAndRetain,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "capacity reservation target is valid",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					CapacityReservation: &CapacityReservation{
+						ID: pointer.StringPtr("cr-1"),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ensure capacity reservation target and preference are not both set",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					CapacityReservation: &CapacityReservation{
+						ID:         pointer.StringPtr("cr-1"),
+						Preference: CapacityReservationPreferenceOpen,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure capacity reservations are not used by spot instances",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					SpotMarketOptions: &SpotMarketOptions{},
+					CapacityReservation: &CapacityReservation{
+						Preference: CapacityReservationPreferenceOpen,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "s3 secure secrets backend",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					CloudInit: CloudInit{
+						SecureSecretsBackend: SecretBackendS3,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ensure secure secrets backend is not set when skipping secrets manager",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					CloudInit: CloudInit{
+						InsecureSkipSecretsManager: true,
+						SecureSecretsBackend:       SecretBackendS3,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ignition with the s3 secure secrets backend",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					BootstrapFormat: BootstrapFormatIgnition,
+					CloudInit: CloudInit{
+						SecureSecretsBackend: SecretBackendS3,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ignition without secure secrets backend",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					BootstrapFormat: BootstrapFormatIgnition,
+					CloudInit: CloudInit{
+						InsecureSkipSecretsManager: true,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ensure ignition is not used with the secrets-manager secure secrets backend",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					BootstrapFormat: BootstrapFormatIgnition,
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -110,6 +450,136 @@ func TestAWSMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "change in instance metadata options",
+			oldMachine: &AWSMachine{
+				Spec: AWSMachineSpec{},
+			},
+			newMachine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceMetadataOptions: &InstanceMetadataOptions{
+						HTTPTokens:              "required",
+						HTTPPutResponseHopLimit: 2,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "change in instance type and root volume size with in-place resize",
+			oldMachine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType: "m5.large",
+					RootVolume:   &RootVolume{Size: 20},
+				},
+			},
+			newMachine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType:  "m5.xlarge",
+					RootVolume:    &RootVolume{Size: 40},
+					InPlaceResize: true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "root volume size set for the first time with in-place resize",
+			oldMachine: &AWSMachine{
+				Spec: AWSMachineSpec{},
+			},
+			newMachine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					RootVolume:    &RootVolume{Size: 40},
+					InPlaceResize: true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "root volume type set for the first time with in-place resize",
+			oldMachine: &AWSMachine{
+				Spec: AWSMachineSpec{},
+			},
+			newMachine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					RootVolume:    &RootVolume{Size: 40, Type: "gp2"},
+					InPlaceResize: true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "change in instance type without in-place resize",
+			oldMachine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType: "m5.large",
+				},
+			},
+			newMachine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType: "m5.xlarge",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure root volume size is not decreased with in-place resize",
+			oldMachine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					RootVolume:    &RootVolume{Size: 40},
+					InPlaceResize: true,
+				},
+			},
+			newMachine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					RootVolume:    &RootVolume{Size: 20},
+					InPlaceResize: true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure in-place resize is not enabled for spot instances",
+			oldMachine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					SpotMarketOptions: &SpotMarketOptions{},
+				},
+			},
+			newMachine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					SpotMarketOptions: &SpotMarketOptions{},
+					InPlaceResize:     true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "change in deletion policy",
+			oldMachine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					DisableAPITermination: true,
+				},
+			},
+			newMachine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					DisableAPITermination: true,
+					DeletionPolicy:        MachineDeletionPolicyRetain,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "change in termination protection",
+			oldMachine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					DisableAPITermination: true,
+				},
+			},
+			newMachine: &AWSMachine{
+				Spec: AWSMachineSpec{},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,189 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/errors"
+)
+
+const (
+	// MachinePoolFinalizer allows ReconcileAWSMachinePool to clean up AWS resources associated with AWSMachinePool before
+	// removing it from the apiserver.
+	MachinePoolFinalizer = "awsmachinepool.infrastructure.cluster.x-k8s.io"
+)
+
+// AWSMachinePoolSpec defines the desired state of AWSMachinePool
+type AWSMachinePoolSpec struct {
+	// ProviderID is the ARN of the associated Auto Scaling Group.
+	// +optional
+	ProviderID string `json:"providerID,omitempty"`
+
+	// ProviderIDList are the identification IDs of machine instances provided by the provider.
+	// This field must match the provider IDs as seen on the node objects corresponding to a machine pool's machine instances.
+	// +optional
+	ProviderIDList []string `json:"providerIDList,omitempty"`
+
+	// MinSize defines the minimum size of the group.
+	// +kubebuilder:validation:Minimum=0
+	MinSize int32 `json:"minSize"`
+
+	// MaxSize defines the maximum size of the group.
+	// +kubebuilder:validation:Minimum=1
+	MaxSize int32 `json:"maxSize"`
+
+	// Subnets is an array of references to the subnets the Auto Scaling Group should launch instances in.
+	// Subnets must be referenced by ID. If not specified, the private subnets of the cluster will be used.
+	// +optional
+	Subnets []AWSResourceReference `json:"subnets,omitempty"`
+
+	// AdditionalTags is an optional set of tags to add to the Auto Scaling Group and its instances, in addition
+	// to the ones added by default by the AWS provider. If both the AWSCluster and the AWSMachinePool specify
+	// the same tag name with different values, the AWSMachinePool's value takes precedence.
+	// +optional
+	AdditionalTags Tags `json:"additionalTags,omitempty"`
+
+	// AWSLaunchTemplate specifies the launch template used by the Auto Scaling Group to launch instances.
+	AWSLaunchTemplate AWSLaunchTemplate `json:"awsLaunchTemplate"`
+}
+
+// AWSLaunchTemplate defines the desired state of the launch template backing an AWSMachinePool.
+// The fields mirror the ones available on AWSMachineSpec.
+type AWSLaunchTemplate struct {
+	// AMI is the reference to the AMI from which to create the machine instances.
+	// +optional
+	AMI AWSResourceReference `json:"ami,omitempty"`
+
+	// ImageLookupOrg is the AWS Organization ID to use for image lookup if AMI is not set.
+	// +optional
+	ImageLookupOrg string `json:"imageLookupOrg,omitempty"`
+
+	// ImageLookupBaseOS is the name of the base operating system to use for
+	// image lookup the AMI is not set.
+	// +optional
+	ImageLookupBaseOS string `json:"imageLookupBaseOS,omitempty"`
+
+	// InstanceType is the type of instance to create. Example: m4.xlarge
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// IAMInstanceProfile is a name of an IAM instance profile to assign to the instances
+	// +optional
+	IAMInstanceProfile string `json:"iamInstanceProfile,omitempty"`
+
+	// AdditionalSecurityGroups is an array of references to security groups that should be applied to the
+	// instances. These security groups would be set in addition to any security groups defined
+	// at the cluster level or in the actuator.
+	// +optional
+	AdditionalSecurityGroups []AWSResourceReference `json:"additionalSecurityGroups,omitempty"`
+
+	// SSHKeyName is the name of the ssh key to attach to the instances. Valid values are empty string (do not use SSH keys), a valid SSH key name, or omitted (use the SSH key name of the cluster)
+	// +optional
+	SSHKeyName *string `json:"sshKeyName,omitempty"`
+
+	// RootVolume encapsulates the configuration options for the root volume
+	// +optional
+	RootVolume *RootVolume `json:"rootVolume,omitempty"`
+}
+
+// AWSMachinePoolStatus defines the observed state of AWSMachinePool
+type AWSMachinePoolStatus struct {
+	// Ready is true when the provider resource is ready.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// Replicas is the most recently observed number of replicas
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// Instances contains the status of the instances in the Auto Scaling Group.
+	// +optional
+	Instances []AWSMachinePoolInstanceStatus `json:"instances,omitempty"`
+
+	// LaunchTemplateID is the ID of the launch template backing the Auto Scaling Group.
+	// +optional
+	LaunchTemplateID string `json:"launchTemplateID,omitempty"`
+
+	// Conditions describe the progress of the reconciliation of the Auto Scaling Group for this machine pool.
+	// +optional
+	Conditions []AWSMachineProviderCondition `json:"conditions,omitempty"`
+
+	// FailureReason will be set in the event that there is a terminal problem
+	// reconciling the MachinePool and will contain a succinct value suitable
+	// for machine interpretation.
+	// +optional
+	FailureReason *errors.MachineStatusError `json:"failureReason,omitempty"`
+
+	// FailureMessage will be set in the event that there is a terminal problem
+	// reconciling the MachinePool and will contain a more verbose string suitable
+	// for logging and human consumption.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+}
+
+// AWSMachinePoolInstanceStatus defines the status of an instance in the Auto Scaling Group.
+type AWSMachinePoolInstanceStatus struct {
+	// InstanceID is the ID of the EC2 instance.
+	InstanceID string `json:"instanceID"`
+
+	// ProviderID is the provider ID of the instance, as seen on the corresponding node.
+	// +optional
+	ProviderID string `json:"providerID,omitempty"`
+
+	// AvailabilityZone is the availability zone the instance was launched in.
+	// +optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+
+	// LifecycleState is the Auto Scaling lifecycle state of the instance.
+	// +optional
+	LifecycleState string `json:"lifecycleState,omitempty"`
+
+	// Ready is true when the instance is in service and healthy.
+	// +optional
+	Ready bool `json:"ready"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=awsmachinepools,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Machine ready status"
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".status.replicas",description="Number of instances in the Auto Scaling Group"
+// +kubebuilder:printcolumn:name="MinSize",type="integer",JSONPath=".spec.minSize",description="Minimum size of the Auto Scaling Group"
+// +kubebuilder:printcolumn:name="MaxSize",type="integer",JSONPath=".spec.maxSize",description="Maximum size of the Auto Scaling Group"
+// +kubebuilder:printcolumn:name="LaunchTemplate ID",type="string",JSONPath=".status.launchTemplateID",description="ID of the launch template"
+
+// AWSMachinePool is the Schema for the awsmachinepools API
+type AWSMachinePool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AWSMachinePoolSpec   `json:"spec,omitempty"`
+	Status AWSMachinePoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AWSMachinePoolList contains a list of AWSMachinePool
+type AWSMachinePoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AWSMachinePool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AWSMachinePool{}, &AWSMachinePoolList{})
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var _ = logf.Log.WithName("awsmachinepool-resource")
+
+func (r *AWSMachinePool) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha3-awsmachinepool,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsmachinepools,versions=v1alpha3,name=validation.awsmachinepool.infrastructure.cluster.x-k8s.io
+
+var _ webhook.Validator = &AWSMachinePool{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *AWSMachinePool) ValidateCreate() error {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, r.validateSize()...)
+	allErrs = append(allErrs, r.validateVolumeTypeIOPS()...)
+	allErrs = append(allErrs, r.validateSubnets()...)
+
+	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *AWSMachinePool) ValidateUpdate(old runtime.Object) error {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, r.validateSize()...)
+	allErrs = append(allErrs, r.validateVolumeTypeIOPS()...)
+	allErrs = append(allErrs, r.validateSubnets()...)
+
+	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+}
+
+func (r *AWSMachinePool) validateSize() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.MaxSize < r.Spec.MinSize {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "maxSize"), r.Spec.MaxSize, "must be greater than or equal to spec.minSize"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachinePool) validateVolumeTypeIOPS() field.ErrorList {
+	var allErrs field.ErrorList
+
+	rootVolume := r.Spec.AWSLaunchTemplate.RootVolume
+	if rootVolume != nil && rootVolume.Type == "io1" && rootVolume.IOPS == 0 {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "awsLaunchTemplate", "rootVolume", "iops"), "iops required if type is 'io1'"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachinePool) validateSubnets() field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, subnet := range r.Spec.Subnets {
+		subnetPath := field.NewPath("spec", "subnets").Index(i)
+		if subnet.ARN != nil {
+			allErrs = append(allErrs, field.Forbidden(subnetPath.Child("arn"), "only subnet IDs are supported"))
+		}
+		if len(subnet.Filters) > 0 {
+			allErrs = append(allErrs, field.Forbidden(subnetPath.Child("filters"), "only subnet IDs are supported"))
+		}
+		if subnet.ID == nil {
+			allErrs = append(allErrs, field.Required(subnetPath.Child("id"), "subnet ID is required"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *AWSMachinePool) ValidateDelete() error {
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	"testing"
+
+	"k8s.io/utils/pointer"
+)
+
+func TestAWSMachinePool_ValidateCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		pool    *AWSMachinePool
+		wantErr bool
+	}{
+		{
+			name: "valid size",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					MinSize: 1,
+					MaxSize: 3,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "max size smaller than min size",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					MinSize: 3,
+					MaxSize: 1,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "subnet given by ID",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					MinSize: 1,
+					MaxSize: 1,
+					Subnets: []AWSResourceReference{{ID: pointer.StringPtr("subnet-1")}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "subnet given by ARN",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					MinSize: 1,
+					MaxSize: 1,
+					Subnets: []AWSResourceReference{{ARN: pointer.StringPtr("arn:aws:ec2:us-east-1:123456789012:subnet/subnet-1")}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "subnet given by filters",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					MinSize: 1,
+					MaxSize: 1,
+					Subnets: []AWSResourceReference{{Filters: []Filter{{Name: "tag:Name", Values: []string{"private"}}}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure IOPS exists if type equal to io1",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					MinSize: 1,
+					MaxSize: 1,
+					AWSLaunchTemplate: AWSLaunchTemplate{
+						RootVolume: &RootVolume{
+							Type: "io1",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.pool.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
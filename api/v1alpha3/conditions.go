@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reasons for AWS machine conditions.
+const (
+	// WaitingForClusterInfrastructureReason is used while the infrastructure of the cluster is not ready.
+	WaitingForClusterInfrastructureReason = "WaitingForClusterInfrastructure"
+
+	// WaitingForBootstrapDataReason is used while the bootstrap data secret of the machine is not available.
+	WaitingForBootstrapDataReason = "WaitingForBootstrapData"
+
+	// BootstrapDataFailedReason is used when the bootstrap data of the machine can't be read or stored.
+	BootstrapDataFailedReason = "BootstrapDataFailed"
+
+	// InstanceProvisionFailedReason is used when the EC2 instance of the machine can't be created.
+	InstanceProvisionFailedReason = "InstanceProvisionFailed"
+
+	// InstanceNotFoundReason is used when the EC2 instance of the machine can't be found.
+	InstanceNotFoundReason = "InstanceNotFound"
+
+	// InstanceNotRunningReason is used while the EC2 instance of the machine is pending, stopping or stopped.
+	InstanceNotRunningReason = "InstanceNotRunning"
+
+	// InstanceTerminatedReason is used when the EC2 instance of the machine is shutting down or terminated.
+	InstanceTerminatedReason = "InstanceTerminated"
+
+	// InstanceStateUnknownReason is used when the EC2 instance of the machine is in an undefined state.
+	InstanceStateUnknownReason = "InstanceStateUnknown"
+
+	// SecurityGroupsFailedReason is used when the security groups of the EC2 instance can't be reconciled.
+	SecurityGroupsFailedReason = "SecurityGroupsFailed"
+
+	// ELBAttachFailedReason is used when the EC2 instance can't be registered with the API server load balancer.
+	ELBAttachFailedReason = "ELBAttachFailed"
+
+	// TagsFailedReason is used when the tags of the EC2 instance can't be reconciled.
+	TagsFailedReason = "TagsFailed"
+
+	// InstanceEventScheduledReason is used when AWS has scheduled a maintenance event for the EC2 instance.
+	InstanceEventScheduledReason = "InstanceEventScheduled"
+
+	// SpotInstanceInterruptionReason is used when an interruption notice was received for the spot instance.
+	SpotInstanceInterruptionReason = "SpotInstanceInterruption"
+
+	// InstanceInSyncReason is used when the EC2 instance matches the machine spec.
+	InstanceInSyncReason = "InstanceInSync"
+
+	// InstanceDriftedReason is used when the EC2 instance differs from the machine spec.
+	InstanceDriftedReason = "InstanceDrifted"
+
+	// InstanceResizeFailedReason is used when AWS rejected the instance type of an in-place resize.
+	InstanceResizeFailedReason = "InstanceResizeFailed"
+)
+
+// Reasons for AWS machine pool conditions.
+const (
+	// InstancesNotInServiceReason is used while fewer instances of the Auto Scaling Group are in service
+	// than the machine pool desires.
+	InstancesNotInServiceReason = "InstancesNotInService"
+)
+
+// GetCondition returns the condition of the given type, or nil if it is not set.
+func (s *AWSMachineStatus) GetCondition(conditionType AWSMachineProviderConditionType) *AWSMachineProviderCondition {
+	return getCondition(s.Conditions, conditionType)
+}
+
+// SetCondition adds the condition, or updates the existing condition of the same type.
+// The last transition time only changes when the status of the condition does.
+func (s *AWSMachineStatus) SetCondition(condition AWSMachineProviderCondition) {
+	setCondition(&s.Conditions, condition)
+}
+
+// IsConditionTrue returns whether the condition of the given type is set and true.
+func (s *AWSMachineStatus) IsConditionTrue(conditionType AWSMachineProviderConditionType) bool {
+	condition := s.GetCondition(conditionType)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// GetCondition returns the condition of the given type, or nil if it is not set.
+func (s *AWSMachinePoolStatus) GetCondition(conditionType AWSMachineProviderConditionType) *AWSMachineProviderCondition {
+	return getCondition(s.Conditions, conditionType)
+}
+
+// SetCondition adds the condition, or updates the existing condition of the same type.
+// The last transition time only changes when the status of the condition does.
+func (s *AWSMachinePoolStatus) SetCondition(condition AWSMachineProviderCondition) {
+	setCondition(&s.Conditions, condition)
+}
+
+func getCondition(conditions []AWSMachineProviderCondition, conditionType AWSMachineProviderConditionType) *AWSMachineProviderCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+func setCondition(conditions *[]AWSMachineProviderCondition, condition AWSMachineProviderCondition) {
+	existing := getCondition(*conditions, condition.Type)
+	if existing == nil {
+		if condition.LastTransitionTime.IsZero() {
+			condition.LastTransitionTime = metav1.Now()
+		}
+		*conditions = append(*conditions, condition)
+		return
+	}
+
+	if existing.Status != condition.Status {
+		existing.Status = condition.Status
+		existing.LastTransitionTime = condition.LastTransitionTime
+		if existing.LastTransitionTime.IsZero() {
+			existing.LastTransitionTime = metav1.Now()
+		}
+	}
+	existing.Reason = condition.Reason
+	existing.Message = condition.Message
+}
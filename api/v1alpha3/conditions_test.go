@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAWSMachineStatus_SetCondition(t *testing.T) {
+	past := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+
+	status := &AWSMachineStatus{
+		Conditions: []AWSMachineProviderCondition{
+			{
+				Type:               InstanceProvisioned,
+				Status:             corev1.ConditionFalse,
+				LastTransitionTime: past,
+				Reason:             InstanceNotRunningReason,
+				Message:            "EC2 instance is pending",
+			},
+		},
+	}
+
+	status.SetCondition(AWSMachineProviderCondition{
+		Type:    InstanceProvisioned,
+		Status:  corev1.ConditionFalse,
+		Reason:  InstanceNotRunningReason,
+		Message: "EC2 instance is stopped",
+	})
+	condition := status.GetCondition(InstanceProvisioned)
+	if !condition.LastTransitionTime.Equal(&past) {
+		t.Fatalf("expected transition time to be kept when the status does not change, got %v", condition.LastTransitionTime)
+	}
+	if condition.Message != "EC2 instance is stopped" {
+		t.Fatalf("expected message to be updated, got %q", condition.Message)
+	}
+
+	status.SetCondition(AWSMachineProviderCondition{
+		Type:   InstanceProvisioned,
+		Status: corev1.ConditionTrue,
+	})
+	condition = status.GetCondition(InstanceProvisioned)
+	if condition.LastTransitionTime.Equal(&past) {
+		t.Fatal("expected transition time to be updated when the status changes")
+	}
+	if condition.Reason != "" || condition.Message != "" {
+		t.Fatalf("expected reason and message to be cleared, got %q and %q", condition.Reason, condition.Message)
+	}
+	if !status.IsConditionTrue(InstanceProvisioned) {
+		t.Fatal("expected condition to be true")
+	}
+
+	status.SetCondition(AWSMachineProviderCondition{
+		Type:   TagsApplied,
+		Status: corev1.ConditionTrue,
+	})
+	if len(status.Conditions) != 2 {
+		t.Fatalf("expected 2 conditions, got %d", len(status.Conditions))
+	}
+	if status.GetCondition(TagsApplied).LastTransitionTime.IsZero() {
+		t.Fatal("expected transition time to be set on a new condition")
+	}
+	if status.IsConditionTrue(SecurityGroupsReady) {
+		t.Fatal("expected unset condition not to be true")
+	}
+}
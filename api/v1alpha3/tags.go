@@ -118,6 +118,12 @@ const (
 
 	// PrivateRoleTagValue describes the value for the private role
 	PrivateRoleTagValue = "private"
+
+	// NetworkInterfaceRoleTagValue describes the value for the role of network interfaces managed for machines
+	NetworkInterfaceRoleTagValue = "network-interface"
+
+	// VPCEndpointRoleTagValue describes the value for the role of the VPC endpoints used by Session Manager
+	VPCEndpointRoleTagValue = "vpc-endpoint"
 )
 
 // ClusterTagKey generates the key for resources associated with a cluster.
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
@@ -66,9 +67,62 @@ type AWSMachineProviderConditionType string
 const (
 	// MachineCreated indicates whether the machine has been created or not. If not,
 	// it should include a reason and message for the failure.
+	// Deprecated: InstanceProvisioned is set instead.
 	MachineCreated AWSMachineProviderConditionType = "MachineCreated"
+
+	// InstanceProvisioned indicates whether the EC2 instance of the machine exists and is running.
+	InstanceProvisioned AWSMachineProviderConditionType = "InstanceProvisioned"
+
+	// BootstrapDataSecretReady indicates whether the bootstrap data of the machine is available
+	// to the EC2 instance.
+	BootstrapDataSecretReady AWSMachineProviderConditionType = "BootstrapDataSecretReady"
+
+	// SecurityGroupsReady indicates whether the security groups of the EC2 instance match the machine spec.
+	SecurityGroupsReady AWSMachineProviderConditionType = "SecurityGroupsReady"
+
+	// ELBAttached indicates whether a control plane EC2 instance is registered with the API server load balancer.
+	ELBAttached AWSMachineProviderConditionType = "ELBAttached"
+
+	// TagsApplied indicates whether the tags of the EC2 instance and its volumes match the machine spec.
+	TagsApplied AWSMachineProviderConditionType = "TagsApplied"
+
+	// InstanceAvailable indicates whether the EC2 instance is expected to remain available, i.e.
+	// AWS has not scheduled a maintenance event for it and no spot interruption notice was received.
+	InstanceAvailable AWSMachineProviderConditionType = "InstanceAvailable"
+
+	// Drifted indicates whether the EC2 instance differs from the machine spec, e.g. because it was
+	// changed outside of the controller. The message of the condition lists the differing fields.
+	Drifted AWSMachineProviderConditionType = "Drifted"
+
+	// InstanceResized indicates whether the last in-place resize of the EC2 instance succeeded.
+	InstanceResized AWSMachineProviderConditionType = "InstanceResized"
+
+	// ASGReady indicates whether the Auto Scaling Group of a machine pool exists and has
+	// the desired number of instances in service.
+	ASGReady AWSMachineProviderConditionType = "ASGReady"
 )
 
+// AWSMachineProviderCondition describes the state of an AWS machine at a certain point.
+type AWSMachineProviderCondition struct {
+	// Type is the type of the condition.
+	Type AWSMachineProviderConditionType `json:"type"`
+
+	// Status is the status of the condition, one of True, False or Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+
+	// LastTransitionTime is the last time the condition transitioned from one status to another.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Reason is a unique, one-word, CamelCase reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human-readable message indicating details about the last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // Network encapsulates AWS networking resources.
 type Network struct {
 	// SecurityGroups is a map from the role/kind of the security group to its unique name, if any.
@@ -253,6 +307,15 @@ func (s Subnets) ToMap() map[string]*SubnetSpec {
 	return res
 }
 
+// IDs returns the IDs of the subnets.
+func (s Subnets) IDs() []string {
+	res := make([]string, 0, len(s))
+	for _, x := range s {
+		res = append(res, x.ID)
+	}
+	return res
+}
+
 // FindByID returns a single subnet matching the given id or nil.
 func (s Subnets) FindByID(id string) *SubnetSpec {
 	for _, x := range s {
@@ -317,6 +380,9 @@ var (
 
 	// SecurityGroupLB defines a container for the cloud provider to inject its load balancer ingress rules
 	SecurityGroupLB = SecurityGroupRole("lb")
+
+	// SecurityGroupVPCEndpoint defines the role of the VPC interface endpoints used by Session Manager
+	SecurityGroupVPCEndpoint = SecurityGroupRole("vpc-endpoint")
 )
 
 // SecurityGroup defines an AWS security group.
@@ -494,6 +560,98 @@ var (
 	)
 )
 
+// InstanceResizePhase describes the progress of an in-place resize of an AWS instance.
+type InstanceResizePhase string
+
+var (
+	// InstanceResizePhaseStopping is the phase of a resize in which the instance is being stopped
+	InstanceResizePhaseStopping = InstanceResizePhase("Stopping")
+
+	// InstanceResizePhaseModifying is the phase of a resize in which the instance type
+	// and root volume of the stopped instance are being modified
+	InstanceResizePhaseModifying = InstanceResizePhase("Modifying")
+
+	// InstanceResizePhaseStarting is the phase of a resize in which the modified instance
+	// is being started again
+	InstanceResizePhaseStarting = InstanceResizePhase("Starting")
+)
+
+// InstanceResize describes an in-place resize of an AWS instance in progress.
+type InstanceResize struct {
+	// Phase is the current phase of the resize.
+	Phase InstanceResizePhase `json:"phase"`
+
+	// InstanceType is the instance type the instance is resized to, if it changes.
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// RootVolumeSize is the size (in Gi) the root volume is resized to, if it changes.
+	// +optional
+	RootVolumeSize int64 `json:"rootVolumeSize,omitempty"`
+}
+
+// InstanceLifecycle describes the purchasing option of an AWS instance.
+type InstanceLifecycle string
+
+var (
+	// InstanceLifecycleOnDemand is the string representing an on-demand instance
+	InstanceLifecycleOnDemand = InstanceLifecycle("on-demand")
+
+	// InstanceLifecycleSpot is the string representing a spot instance
+	InstanceLifecycleSpot = InstanceLifecycle("spot")
+)
+
+// SpotInterruptionBehavior describes what happens to a spot instance when it is interrupted.
+type SpotInterruptionBehavior string
+
+var (
+	// SpotInterruptionBehaviorTerminate terminates the instance when it is interrupted
+	SpotInterruptionBehaviorTerminate = SpotInterruptionBehavior("terminate")
+
+	// SpotInterruptionBehaviorStop stops the instance when it is interrupted
+	SpotInterruptionBehaviorStop = SpotInterruptionBehavior("stop")
+
+	// SpotInterruptionBehaviorHibernate hibernates the instance when it is interrupted
+	SpotInterruptionBehaviorHibernate = SpotInterruptionBehavior("hibernate")
+)
+
+// SpotMarketOptions defines the options available to a user when configuring
+// Machines to run on Spot instances.
+type SpotMarketOptions struct {
+	// MaxPrice defines the maximum price the user is willing to pay for Spot instances.
+	// If not set, the maximum price defaults to the on-demand price.
+	// +optional
+	MaxPrice *string `json:"maxPrice,omitempty"`
+
+	// InterruptionBehavior defines what happens to the instance when it is interrupted.
+	// Stop and hibernate require a persistent spot request, which is created automatically.
+	// Defaults to terminate.
+	// +kubebuilder:validation:Enum=terminate;stop;hibernate
+	// +optional
+	InterruptionBehavior SpotInterruptionBehavior `json:"interruptionBehavior,omitempty"`
+}
+
+// InstanceScheduledEvent describes an event AWS has scheduled for an instance, such as a
+// retirement or a system reboot, or a spot instance interruption notice.
+type InstanceScheduledEvent struct {
+	// Code is the code of the event, e.g. instance-retirement or system-reboot.
+	Code string `json:"code"`
+
+	// Description is the description of the event.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// NotBefore is the earliest scheduled start time of the event.
+	// +optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+}
+
+const (
+	// SpotInstanceInterruptionEventCode is the code of the event recorded when a spot instance
+	// interruption notice is received.
+	SpotInstanceInterruptionEventCode = "spot-interruption"
+)
+
 // Instance describes an AWS instance.
 type Instance struct {
 	ID string `json:"id"`
@@ -542,9 +700,63 @@ type Instance struct {
 	// +optional
 	RootVolume *RootVolume `json:"rootVolume,omitempty"`
 
+	// Configuration options for the additional storage volumes.
+	// This field should only be used when running a new instance.
+	// +optional
+	AdditionalVolumes []Volume `json:"additionalVolumes,omitempty"`
+
+	// VolumeAttachments are the EBS volumes attached to the instance.
+	// +optional
+	VolumeAttachments []VolumeAttachment `json:"volumeAttachments,omitempty"`
+
 	// Specifies ENIs attached to instance
 	NetworkInterfaces []string `json:"networkInterfaces,omitempty"`
 
+	// SecondaryNetworkInterfaces are the IDs of the ENIs to attach to the instance in addition to
+	// its primary network interface and NetworkInterfaces.
+	// This field should only be used when running a new instance.
+	// +optional
+	SecondaryNetworkInterfaces []string `json:"secondaryNetworkInterfaces,omitempty"`
+
+	// Placement is where the instance is placed.
+	// +optional
+	Placement *Placement `json:"placement,omitempty"`
+
+	// InstanceMetadataOptions are the metadata options of the instance.
+	// +optional
+	InstanceMetadataOptions *InstanceMetadataOptions `json:"instanceMetadataOptions,omitempty"`
+
+	// SpotMarketOptions are the spot options the instance was requested with.
+	// This field should only be used when running a new instance.
+	// +optional
+	SpotMarketOptions *SpotMarketOptions `json:"spotMarketOptions,omitempty"`
+
+	// CapacityReservation is the Capacity Reservation the instance was requested with.
+	// This field should only be used when running a new instance.
+	// +optional
+	CapacityReservation *CapacityReservation `json:"capacityReservation,omitempty"`
+
+	// DisableAPITermination enables termination protection on the instance.
+	// This field should only be used when running a new instance.
+	// +optional
+	DisableAPITermination bool `json:"disableApiTermination,omitempty"`
+
+	// Lifecycle indicates whether the instance is a spot or an on-demand instance.
+	// +optional
+	Lifecycle InstanceLifecycle `json:"lifecycle,omitempty"`
+
+	// Architecture is the processor architecture of the instance.
+	// +optional
+	Architecture string `json:"architecture,omitempty"`
+
+	// SpotInstanceRequestID is the ID of the spot request that launched the instance, if applicable.
+	// +optional
+	SpotInstanceRequestID *string `json:"spotInstanceRequestId,omitempty"`
+
+	// CapacityReservationID is the ID of the Capacity Reservation the instance is running in, if any.
+	// +optional
+	CapacityReservationID *string `json:"capacityReservationId,omitempty"`
+
 	// The tags associated with the instance.
 	Tags map[string]string `json:"tags,omitempty"`
 }
@@ -574,3 +786,229 @@ type RootVolume struct {
 	// +optional
 	EncryptionKey string `json:"encryptionKey,omitempty"`
 }
+
+// Placement defines where an instance is placed.
+type Placement struct {
+	// GroupName is the name of the placement group to launch the instance into.
+	// +optional
+	GroupName string `json:"groupName,omitempty"`
+
+	// PartitionNumber is the number of the partition to launch the instance into.
+	// Only valid if the placement group uses the partition strategy.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PartitionNumber *int64 `json:"partitionNumber,omitempty"`
+
+	// Tenancy is the tenancy of the instance.
+	// +kubebuilder:validation:Enum=default;dedicated;host
+	// +optional
+	Tenancy string `json:"tenancy,omitempty"`
+
+	// HostID is the ID of the dedicated host to launch the instance on.
+	// Requires the host tenancy.
+	// +optional
+	HostID string `json:"hostID,omitempty"`
+
+	// HostResourceGroupARN is the ARN of the host resource group to launch the instance in.
+	// Requires the host tenancy and cannot be combined with HostID.
+	// +optional
+	HostResourceGroupARN string `json:"hostResourceGroupARN,omitempty"`
+}
+
+// CapacityReservationPreference describes whether an instance can run in an open Capacity Reservation.
+type CapacityReservationPreference string
+
+var (
+	// CapacityReservationPreferenceOpen runs the instance in any open Capacity Reservation with matching attributes
+	CapacityReservationPreferenceOpen = CapacityReservationPreference("open")
+
+	// CapacityReservationPreferenceNone runs the instance outside of Capacity Reservations, even if one is available
+	CapacityReservationPreferenceNone = CapacityReservationPreference("none")
+)
+
+// CapacityReservation configures the On-Demand Capacity Reservation an instance runs in.
+// Only one of Preference and ID can be set.
+type CapacityReservation struct {
+	// Preference defines whether the instance can run in any open Capacity Reservation.
+	// If neither Preference nor ID is set, AWS defaults to open.
+	// +kubebuilder:validation:Enum=open;none
+	// +optional
+	Preference CapacityReservationPreference `json:"preference,omitempty"`
+
+	// ID is the ID of the Capacity Reservation to run the instance in.
+	// +optional
+	ID *string `json:"id,omitempty"`
+}
+
+// MachineDeletionPolicy describes what happens to the instance of a machine when the machine is deleted.
+type MachineDeletionPolicy string
+
+var (
+	// MachineDeletionPolicyTerminate terminates the instance
+	MachineDeletionPolicyTerminate = MachineDeletionPolicy("Terminate")
+
+	// MachineDeletionPolicyStopAndRetain stops the instance and removes it from the cluster
+	MachineDeletionPolicyStopAndRetain = MachineDeletionPolicy("StopAndRetain")
+
+	// MachineDeletionPolicyRetain leaves the instance running and removes it from the cluster
+	MachineDeletionPolicyRetain = MachineDeletionPolicy("Retain")
+)
+
+// PlacementGroupStrategy describes the strategy of a placement group.
+type PlacementGroupStrategy string
+
+var (
+	// PlacementGroupStrategyCluster packs instances close together inside an Availability Zone
+	PlacementGroupStrategyCluster = PlacementGroupStrategy("cluster")
+
+	// PlacementGroupStrategySpread places instances on distinct underlying hardware
+	PlacementGroupStrategySpread = PlacementGroupStrategy("spread")
+
+	// PlacementGroupStrategyPartition spreads instances across logical partitions
+	PlacementGroupStrategyPartition = PlacementGroupStrategy("partition")
+)
+
+// PlacementGroupSpec defines a placement group managed along with the cluster.
+type PlacementGroupSpec struct {
+	// Name is the name of the placement group. It must be unique within the AWS account and region.
+	Name string `json:"name"`
+
+	// Strategy is the placement strategy of the group.
+	// +kubebuilder:validation:Enum=cluster;spread;partition
+	Strategy PlacementGroupStrategy `json:"strategy"`
+
+	// PartitionCount is the number of partitions. Only valid if the strategy is partition.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=7
+	// +optional
+	PartitionCount *int64 `json:"partitionCount,omitempty"`
+}
+
+// ManagedNetworkInterface describes a network interface created and deleted along with a machine.
+type ManagedNetworkInterface struct {
+	// Subnet is a reference to the subnet to create the network interface in. It must be
+	// in the availability zone of the instance. If not specified, the subnet of the instance is used.
+	// +optional
+	Subnet *AWSResourceReference `json:"subnet,omitempty"`
+
+	// SecurityGroups is a list of references to the security groups of the network interface.
+	// If not specified, the security groups of the instance are used.
+	// The security groups are only set when the network interface is created.
+	// +optional
+	SecurityGroups []AWSResourceReference `json:"securityGroups,omitempty"`
+
+	// SecondaryPrivateIPAddressCount is the number of secondary private IPv4 addresses
+	// to assign to the network interface.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SecondaryPrivateIPAddressCount *int64 `json:"secondaryPrivateIPAddressCount,omitempty"`
+
+	// Description is the description of the network interface.
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// ElasticIP describes the Elastic IP address associated with an instance.
+type ElasticIP struct {
+	// AllocationID is the allocation ID of an existing Elastic IP address to associate
+	// with the instance. The address is not released when the machine is deleted.
+	// If unset, an Elastic IP address is allocated for the machine and released
+	// when the machine is deleted.
+	// +optional
+	AllocationID *string `json:"allocationID,omitempty"`
+}
+
+// InstanceMetadataOptions describes the options of the Instance Metadata Service (IMDS) of an instance.
+type InstanceMetadataOptions struct {
+	// HTTPTokens is the state of token usage for instance metadata requests.
+	// If set to required, only IMDSv2 requests with a session token are accepted.
+	// Defaults to optional.
+	// +kubebuilder:validation:Enum=optional;required
+	// +optional
+	HTTPTokens string `json:"httpTokens,omitempty"`
+
+	// HTTPPutResponseHopLimit is the desired HTTP PUT response hop limit for instance metadata requests.
+	// The larger the number, the further instance metadata requests can travel.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	// +optional
+	HTTPPutResponseHopLimit int64 `json:"httpPutResponseHopLimit,omitempty"`
+
+	// HTTPEndpoint enables or disables the HTTP metadata endpoint on the instance.
+	// If disabled, instance metadata can't be accessed at all.
+	// Defaults to enabled.
+	// +kubebuilder:validation:Enum=enabled;disabled
+	// +optional
+	HTTPEndpoint string `json:"httpEndpoint,omitempty"`
+}
+
+// Volume encapsulates the configuration options for an additional EBS volume.
+type Volume struct {
+	// DeviceName is the device name to expose to the instance (e.g. /dev/sdb).
+	DeviceName string `json:"deviceName"`
+
+	// Size specifies size (in Gi) of the storage device.
+	// +kubebuilder:validation:Minimum=1
+	Size int64 `json:"size"`
+
+	// Type is the type of the volume (e.g. gp2, io1, etc...).
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// IOPS is the number of IOPS requested for the disk. Not applicable to all types.
+	// +optional
+	IOPS int64 `json:"iops,omitempty"`
+
+	// Encrypted is whether the volume should be encrypted or not.
+	// +optional
+	Encrypted bool `json:"encrypted,omitempty"`
+
+	// EncryptionKey is the KMS key to use to encrypt the volume. Can be either a KMS key ID or ARN.
+	// If Encrypted is set and this is omitted, the default AWS key will be used.
+	// The key must already exist and be accessible by the controller.
+	// +optional
+	EncryptionKey string `json:"encryptionKey,omitempty"`
+}
+
+// VolumeAttachment describes an EBS volume attached to an instance.
+type VolumeAttachment struct {
+	// DeviceName is the device name the volume is exposed as to the instance.
+	DeviceName string `json:"deviceName"`
+
+	// VolumeID is the ID of the EBS volume.
+	VolumeID string `json:"volumeId"`
+}
+
+// AutoScalingGroup describes an AWS Auto Scaling Group.
+type AutoScalingGroup struct {
+	// ID is the ARN of the Auto Scaling Group.
+	ID string `json:"id,omitempty"`
+
+	// Name is the name of the Auto Scaling Group.
+	Name string `json:"name,omitempty"`
+
+	// DesiredCapacity is the number of instances the group attempts to maintain.
+	DesiredCapacity *int32 `json:"desiredCapacity,omitempty"`
+
+	// MinSize is the minimum size of the group.
+	MinSize int32 `json:"minSize,omitempty"`
+
+	// MaxSize is the maximum size of the group.
+	MaxSize int32 `json:"maxSize,omitempty"`
+
+	// Subnets are the IDs of the subnets the group launches instances in.
+	Subnets []string `json:"subnets,omitempty"`
+
+	// LaunchTemplateName is the name of the launch template used by the group.
+	LaunchTemplateName string `json:"launchTemplateName,omitempty"`
+
+	// Status is the current state of the group, only set while it is being deleted.
+	Status string `json:"status,omitempty"`
+
+	// Instances are the instances that are part of the group.
+	Instances []AWSMachinePoolInstanceStatus `json:"instances,omitempty"`
+
+	// The tags associated with the group.
+	Tags Tags `json:"tags,omitempty"`
+}
@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	"reflect"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -34,7 +35,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // AWSClusterReconciler reconciles a AwsCluster object
@@ -46,6 +51,7 @@ type AWSClusterReconciler struct {
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsclusters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachines;awsmachinepools,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
 
 func (r *AWSClusterReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, reterr error) {
@@ -123,6 +129,10 @@ func reconcileDelete(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 		return reconcile.Result{}, errors.Wrapf(err, "error deleting bastion for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
 	}
 
+	if err := ec2svc.DeletePlacementGroups(); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "error deleting placement groups for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
+	}
+
 	if err := ec2svc.DeleteNetwork(); err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "error deleting network for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
 	}
@@ -153,6 +163,10 @@ func reconcileNormal(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile network for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
 	}
 
+	if err := ec2Service.ReconcilePlacementGroups(); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile placement groups for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
+	}
+
 	if err := ec2Service.ReconcileBastion(); err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile bastion host for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
 	}
@@ -190,9 +204,65 @@ func reconcileNormal(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 }
 
 func (r *AWSClusterReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	controller, err := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(options).
 		For(&infrav1.AWSCluster{}).
 		WithEventFilter(pausePredicates).
-		Complete(r)
+		Build(r)
+
+	if err != nil {
+		return err
+	}
+
+	// The SSH rules of the cluster's security groups depend on the SSH key names of its
+	// AWSMachines and AWSMachinePools, so the AWSCluster is reconciled again whenever a
+	// machine is created or deleted or its SSH key name changes.
+	if err := controller.Watch(
+		&source.Kind{Type: &infrav1.AWSMachine{}},
+		&handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.machineToAWSCluster)},
+		predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldMachine := e.ObjectOld.(*infrav1.AWSMachine)
+				newMachine := e.ObjectNew.(*infrav1.AWSMachine)
+				return !reflect.DeepEqual(oldMachine.Spec.SSHKeyName, newMachine.Spec.SSHKeyName)
+			},
+		},
+	); err != nil {
+		return err
+	}
+
+	return controller.Watch(
+		&source.Kind{Type: &infrav1.AWSMachinePool{}},
+		&handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.machineToAWSCluster)},
+		predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldPool := e.ObjectOld.(*infrav1.AWSMachinePool)
+				newPool := e.ObjectNew.(*infrav1.AWSMachinePool)
+				return !reflect.DeepEqual(oldPool.Spec.AWSLaunchTemplate.SSHKeyName, newPool.Spec.AWSLaunchTemplate.SSHKeyName)
+			},
+		},
+	)
+}
+
+// machineToAWSCluster is a handler.ToRequestsFunc to be used to enqueue a request for reconciliation
+// of the AWSCluster of the cluster an AWSMachine or AWSMachinePool belongs to.
+func (r *AWSClusterReconciler) machineToAWSCluster(o handler.MapObject) []ctrl.Request {
+	clusterName, ok := o.Meta.GetLabels()[clusterv1.ClusterLabelName]
+	if !ok {
+		return nil
+	}
+	log := r.Log.WithValues("Cluster", clusterName, "Namespace", o.Meta.GetNamespace())
+
+	cluster := &clusterv1.Cluster{}
+	if err := r.Get(context.TODO(), client.ObjectKey{Namespace: o.Meta.GetNamespace(), Name: clusterName}, cluster); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "failed to get Cluster")
+		}
+		return nil
+	}
+
+	return util.ClusterToInfrastructureMapFunc(infrav1.GroupVersion.WithKind("AWSCluster"))(handler.MapObject{
+		Meta:   cluster,
+		Object: cluster,
+	})
 }
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/klogr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+func TestAWSClusterReconciler_machineToAWSCluster(t *testing.T) {
+	scheme, err := setupScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cluster := newCluster("my-cluster")
+	cluster.Spec.InfrastructureRef = &v1.ObjectReference{
+		Kind:       "AWSCluster",
+		Name:       "my-aws-cluster",
+		APIVersion: infrav1.GroupVersion.String(),
+	}
+
+	reconciler := &AWSClusterReconciler{
+		Client: fake.NewFakeClientWithScheme(scheme, cluster),
+		Log:    klogr.New(),
+	}
+
+	machine := &infrav1.AWSMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-machine",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+		},
+	}
+	pool := &infrav1.AWSMachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-pool",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+		},
+	}
+	for _, o := range []handler.MapObject{{Meta: machine, Object: machine}, {Meta: pool, Object: pool}} {
+		requests := reconciler.machineToAWSCluster(o)
+		if len(requests) != 1 {
+			t.Fatalf("Expected 1 but found %d requests for %T", len(requests), o.Object)
+		}
+		if requests[0].Namespace != "default" || requests[0].Name != "my-aws-cluster" {
+			t.Fatalf("Expected a request for default/my-aws-cluster but found %v", requests[0].NamespacedName)
+		}
+	}
+
+	unlabeled := &infrav1.AWSMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "unlabeled",
+			Namespace: "default",
+		},
+	}
+	if requests := reconciler.machineToAWSCluster(handler.MapObject{Meta: unlabeled, Object: unlabeled}); len(requests) != 0 {
+		t.Fatalf("Expected no requests for a machine without a cluster label but found %d", len(requests))
+	}
+
+	orphan := &infrav1.AWSMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "orphan",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "missing-cluster"},
+		},
+	}
+	if requests := reconciler.machineToAWSCluster(handler.MapObject{Meta: orphan, Object: orphan}); len(requests) != 0 {
+		t.Fatalf("Expected no requests for a machine of a missing cluster but found %d", len(requests))
+	}
+}
@@ -41,6 +41,9 @@ func (r *AWSMachineReconciler) updateMachineAnnotationJSON(machine *infrav1.AWSM
 func (r *AWSMachineReconciler) updateMachineAnnotation(machine *infrav1.AWSMachine, annotation string, content string) {
 	// Get the annotations
 	annotations := machine.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
 
 	// Set our annotation to the given content.
 	annotations[annotation] = content
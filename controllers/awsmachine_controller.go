@@ -44,6 +44,8 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/s3"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/secretsmanager"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/userdata"
 )
@@ -54,7 +56,20 @@ type AWSMachineReconciler struct {
 	Log                          logr.Logger
 	Recorder                     record.EventRecorder
 	ec2ServiceFactory            func(*scope.ClusterScope) services.EC2MachineInterface
-	secretsManagerServiceFactory func(*scope.ClusterScope) services.SecretsManagerInterface
+	secretsManagerServiceFactory func(*scope.ClusterScope) services.SecretInterface
+	s3ServiceFactory             func(*scope.ClusterScope) services.SecretInterface
+
+	// InstanceEvents receives EC2 instance events, so that the AWSMachines of the instances
+	// are reconciled as soon as their state changes. Optional.
+	InstanceEvents <-chan instancestate.InstanceEvent
+
+	// MaintenanceAnnotation is the annotation set on the Machine of an AWSMachine whose instance
+	// has scheduled events. Defaults to DefaultInstanceMaintenanceAnnotation.
+	MaintenanceAnnotation string
+
+	// DriftMetrics enables the capa_awsmachine_drifted metric, reporting whether the instance of
+	// each AWSMachine differs from its spec.
+	DriftMetrics bool
 }
 
 func (r *AWSMachineReconciler) getEC2Service(scope *scope.ClusterScope) services.EC2MachineInterface {
@@ -65,7 +80,7 @@ func (r *AWSMachineReconciler) getEC2Service(scope *scope.ClusterScope) services
 	return ec2.NewService(scope)
 }
 
-func (r *AWSMachineReconciler) getSecretsManagerService(scope *scope.ClusterScope) services.SecretsManagerInterface {
+func (r *AWSMachineReconciler) getSecretsManagerService(scope *scope.ClusterScope) services.SecretInterface {
 	if r.secretsManagerServiceFactory != nil {
 		return r.secretsManagerServiceFactory(scope)
 	}
@@ -73,9 +88,27 @@ func (r *AWSMachineReconciler) getSecretsManagerService(scope *scope.ClusterScop
 	return secretsmanager.NewService(scope)
 }
 
+func (r *AWSMachineReconciler) getS3Service(scope *scope.ClusterScope) services.SecretInterface {
+	if r.s3ServiceFactory != nil {
+		return r.s3ServiceFactory(scope)
+	}
+
+	return s3.NewService(scope)
+}
+
+// getSecretService returns the service of the secure secrets backend storing the userdata of the machine.
+func (r *AWSMachineReconciler) getSecretService(machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) services.SecretInterface {
+	if machineScope.SecureSecretsBackend() == infrav1.SecretBackendS3 {
+		return r.getS3Service(clusterScope)
+	}
+
+	return r.getSecretsManagerService(clusterScope)
+}
+
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachines/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=patch
 // +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
 
@@ -193,6 +226,15 @@ func (r *AWSMachineReconciler) SetupWithManager(mgr ctrl.Manager, options contro
 		return err
 	}
 
+	if r.InstanceEvents != nil {
+		if err := mgr.GetFieldIndexer().IndexField(&infrav1.AWSMachine{}, awsMachineInstanceIDField, indexAWSMachineInstanceID); err != nil {
+			return err
+		}
+		if err := controller.Watch(source.Func(r.enqueueInstanceEvents), &handler.EnqueueRequestForObject{}); err != nil {
+			return err
+		}
+	}
+
 	return controller.Watch(
 		&source.Kind{Type: &clusterv1.Cluster{}},
 		&handler.EnqueueRequestsFromMapFunc{
@@ -218,8 +260,10 @@ func (r *AWSMachineReconciler) SetupWithManager(mgr ctrl.Manager, options contro
 func (r *AWSMachineReconciler) reconcileDelete(machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) (ctrl.Result, error) {
 	machineScope.Info("Handling deleted AWSMachine")
 
+	r.deleteDriftMetric(machineScope)
+
 	ec2Service := r.getEC2Service(clusterScope)
-	secretSvc := r.getSecretsManagerService(clusterScope)
+	secretSvc := r.getSecretService(machineScope, clusterScope)
 
 	if err := r.deleteEncryptedBootstrapDataSecret(machineScope, secretSvc); err != nil {
 		return ctrl.Result{}, err
@@ -240,12 +284,26 @@ func (r *AWSMachineReconciler) reconcileDelete(machineScope *scope.MachineScope,
 		// 4. Scale controller deployment to 1
 		machineScope.V(2).Info("Unable to locate EC2 instance by ID or tags")
 		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "NoInstanceFound", "Unable to find matching EC2 instance")
+		if err := r.releaseElasticIP(machineScope, ec2Service); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.deleteManagedNetworkInterfaces(machineScope, ec2Service); err != nil {
+			return ctrl.Result{}, err
+		}
 		controllerutil.RemoveFinalizer(machineScope.AWSMachine, infrav1.MachineFinalizer)
 		return ctrl.Result{}, nil
 	}
 
 	machineScope.V(3).Info("EC2 instance found matching deleted AWSMachine", "instance-id", instance.ID)
 
+	// Persistent spot requests would otherwise replace the instance once it's gone.
+	if instance.SpotInstanceRequestID != nil {
+		if err := ec2Service.CancelSpotInstanceRequest(*instance.SpotInstanceRequestID); err != nil {
+			r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedCancelSpotRequest", "Failed to cancel spot instance request %q: %v", *instance.SpotInstanceRequestID, err)
+			return ctrl.Result{}, errors.Wrap(err, "failed to cancel spot instance request")
+		}
+	}
+
 	// Check the instance state. If it's already shutting down or terminated,
 	// do nothing. Otherwise attempt to delete it.
 	// This decision is based on the ec2-instance-lifecycle graph at
@@ -254,6 +312,28 @@ func (r *AWSMachineReconciler) reconcileDelete(machineScope *scope.MachineScope,
 	case infrav1.InstanceStateShuttingDown, infrav1.InstanceStateTerminated:
 		machineScope.Info("EC2 instance is shutting down or already terminated", "instance-id", instance.ID)
 	default:
+		switch machineScope.AWSMachine.Spec.DeletionPolicy {
+		case infrav1.MachineDeletionPolicyRetain, infrav1.MachineDeletionPolicyStopAndRetain:
+			if err := r.retainInstance(machineScope, clusterScope, ec2Service, instance); err != nil {
+				return ctrl.Result{}, err
+			}
+
+			// The instance is no longer owned by the cluster so remove the finalizer.
+			controllerutil.RemoveFinalizer(machineScope.AWSMachine, infrav1.MachineFinalizer)
+			return ctrl.Result{}, nil
+		}
+
+		// Termination protection only guards the instance against accidental termination outside of the
+		// cluster, the Terminate deletion policy of the machine takes precedence over it.
+		if machineScope.AWSMachine.Spec.DisableAPITermination {
+			machineScope.Info("Disabling termination protection of EC2 instance", "instance-id", instance.ID)
+			if err := ec2Service.DisableTerminationProtection(instance.ID); err != nil {
+				r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedDisableTerminationProtection",
+					"Failed to disable termination protection of instance %q: %v", instance.ID, err)
+				return ctrl.Result{}, errors.Wrap(err, "failed to disable termination protection")
+			}
+		}
+
 		machineScope.Info("Terminating EC2 instance", "instance-id", instance.ID)
 		if err := ec2Service.TerminateInstanceAndWait(instance.ID); err != nil {
 			r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedTerminate", "Failed to terminate instance %q: %v", instance.ID, err)
@@ -284,12 +364,88 @@ func (r *AWSMachineReconciler) reconcileDelete(machineScope *scope.MachineScope,
 		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "SuccessfulTerminate", "Terminated instance %q", instance.ID)
 	}
 
+	if err := r.releaseElasticIP(machineScope, ec2Service); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.deleteManagedNetworkInterfaces(machineScope, ec2Service); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Instance is deleted so remove the finalizer.
 	controllerutil.RemoveFinalizer(machineScope.AWSMachine, infrav1.MachineFinalizer)
 
 	return ctrl.Result{}, nil
 }
 
+// retainInstance stops the instance if required by the deletion policy of the machine, and removes
+// it from the cluster so that it is left behind when the cluster is deleted. Retained control plane
+// instances no longer receive traffic from the API server load balancer, and none of the retained
+// instances keep the cluster's core security groups.
+func (r *AWSMachineReconciler) retainInstance(machineScope *scope.MachineScope, clusterScope *scope.ClusterScope, ec2svc services.EC2MachineInterface, instance *infrav1.Instance) error {
+	if machineScope.AWSMachine.Spec.DeletionPolicy == infrav1.MachineDeletionPolicyStopAndRetain {
+		switch instance.State {
+		case infrav1.InstanceStateStopping, infrav1.InstanceStateStopped:
+		default:
+			machineScope.Info("Stopping EC2 instance", "instance-id", instance.ID)
+			if err := ec2svc.StopInstance(instance.ID); err != nil {
+				r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedStop", "Failed to stop instance %q: %v", instance.ID, err)
+				return errors.Wrap(err, "failed to stop instance")
+			}
+		}
+	}
+
+	if machineScope.IsControlPlane() {
+		elbsvc := elb.NewService(clusterScope)
+		if err := elbsvc.DeregisterInstanceFromAPIServerELB(instance); err != nil {
+			r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedDetachControlPlaneELB",
+				"Failed to deregister control plane instance %q from load balancer: %v", instance.ID, err)
+			return errors.Wrapf(err, "could not deregister control plane instance %q from load balancer", instance.ID)
+		}
+	}
+
+	if err := ec2svc.DetachCoreSecurityGroupsFromInstance(machineScope, instance.ID); err != nil {
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedRetain", "Failed to detach security groups from instance %q: %v", instance.ID, err)
+		return errors.Wrap(err, "failed to detach security groups from instance")
+	}
+
+	if err := ec2svc.DisownInstance(machineScope, instance.ID); err != nil {
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedRetain", "Failed to remove instance %q from the cluster: %v", instance.ID, err)
+		return errors.Wrap(err, "failed to remove instance from the cluster")
+	}
+
+	machineScope.Info("EC2 instance retained", "instance-id", instance.ID, "deletion-policy", machineScope.AWSMachine.Spec.DeletionPolicy)
+	r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "InstanceRetained",
+		"Retained instance %q according to the %s deletion policy, it is no longer owned by the cluster", instance.ID, machineScope.AWSMachine.Spec.DeletionPolicy)
+	return nil
+}
+
+// releaseElasticIP releases the Elastic IP address allocated for the machine by the controller.
+// Addresses referenced by allocation ID are left untouched.
+func (r *AWSMachineReconciler) releaseElasticIP(machineScope *scope.MachineScope, ec2svc services.EC2MachineInterface) error {
+	eip := machineScope.AWSMachine.Spec.ElasticIP
+	if eip == nil || eip.AllocationID != nil {
+		return nil
+	}
+
+	if err := ec2svc.ReleaseElasticIP(machineScope); err != nil {
+		return errors.Wrap(err, "failed to release Elastic IP")
+	}
+	return nil
+}
+
+// deleteManagedNetworkInterfaces deletes the network interfaces created for the machine.
+func (r *AWSMachineReconciler) deleteManagedNetworkInterfaces(machineScope *scope.MachineScope, ec2svc services.EC2MachineInterface) error {
+	if len(machineScope.AWSMachine.Spec.ManagedNetworkInterfaces) == 0 {
+		return nil
+	}
+
+	if err := ec2svc.DeleteManagedNetworkInterfaces(machineScope); err != nil {
+		return errors.Wrap(err, "failed to delete managed network interfaces")
+	}
+	return nil
+}
+
 // findInstance queries the EC2 apis and retrieves the instance if it exists, returns nil otherwise.
 func (r *AWSMachineReconciler) findInstance(scope *scope.MachineScope, ec2svc services.EC2MachineInterface) (*infrav1.Instance, error) {
 	// Parse the ProviderID.
@@ -316,10 +472,10 @@ func (r *AWSMachineReconciler) findInstance(scope *scope.MachineScope, ec2svc se
 	return instance, nil
 }
 
-func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) (ctrl.Result, error) {
+func (r *AWSMachineReconciler) reconcileNormal(ctx context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) (ctrl.Result, error) {
 	machineScope.Info("Reconciling AWSMachine")
 
-	secretSvc := r.getSecretsManagerService(clusterScope)
+	secretSvc := r.getSecretService(machineScope, clusterScope)
 
 	// If the AWSMachine is in an error state, return early.
 	if machineScope.HasFailed() {
@@ -342,12 +498,15 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 
 	if !machineScope.Cluster.Status.InfrastructureReady {
 		machineScope.Info("Cluster infrastructure is not ready yet")
+		machineScope.SetConditionFalse(infrav1.InstanceProvisioned, infrav1.WaitingForClusterInfrastructureReason, "Cluster infrastructure is not ready yet")
 		return ctrl.Result{}, nil
 	}
 
 	// Make sure bootstrap data is available and populated.
 	if machineScope.Machine.Spec.Bootstrap.DataSecretName == nil {
 		machineScope.Info("Bootstrap data secret reference is not yet available")
+		machineScope.SetConditionFalse(infrav1.BootstrapDataSecretReady, infrav1.WaitingForBootstrapDataReason, "Bootstrap data secret reference is not yet available")
+		machineScope.SetConditionFalse(infrav1.InstanceProvisioned, infrav1.WaitingForBootstrapDataReason, "Bootstrap data secret reference is not yet available")
 		return ctrl.Result{}, nil
 	}
 
@@ -362,6 +521,7 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 	// Set an failure message if we couldn't find the instance.
 	if instance == nil {
 		machineScope.Info("EC2 instance cannot be found")
+		machineScope.SetConditionFalse(infrav1.InstanceProvisioned, infrav1.InstanceNotFoundReason, "EC2 instance cannot be found")
 		machineScope.SetFailureReason(capierrors.UpdateMachineError)
 		machineScope.SetFailureMessage(errors.New("EC2 instance cannot be found"))
 		return ctrl.Result{}, nil
@@ -374,6 +534,11 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 
 	existingInstanceState := machineScope.GetInstanceState()
 	machineScope.SetInstanceState(instance.State)
+	machineScope.SetInstanceLifecycle(instance.Lifecycle)
+	machineScope.SetVolumeAttachments(instance.VolumeAttachments)
+	machineScope.SetArchitecture(instance.Architecture)
+	machineScope.SetInstanceType(instance.Type)
+	machineScope.SetCapacityReservationID(instance.CapacityReservationID)
 
 	// Proceed to reconcile the AWSMachine state.
 	if existingInstanceState == nil || *existingInstanceState != instance.State {
@@ -386,14 +551,24 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 	switch instance.State {
 	case infrav1.InstanceStatePending, infrav1.InstanceStateStopping, infrav1.InstanceStateStopped:
 		machineScope.SetNotReady()
+		machineScope.SetConditionFalse(infrav1.InstanceProvisioned, infrav1.InstanceNotRunningReason, "EC2 instance %q is %s", instance.ID, instance.State)
 	case infrav1.InstanceStateRunning:
 		machineScope.SetReady()
+		machineScope.SetConditionTrue(infrav1.InstanceProvisioned)
 	case infrav1.InstanceStateShuttingDown, infrav1.InstanceStateTerminated:
 		machineScope.SetNotReady()
-		machineScope.Info("Unexpected EC2 instance termination", "state", instance.State, "instance-id", *machineScope.GetInstanceID())
-		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "InstanceUnexpectedTermination", "Unexpected EC2 instance termination")
+		machineScope.SetConditionFalse(infrav1.InstanceProvisioned, infrav1.InstanceTerminatedReason, "EC2 instance %q is %s", instance.ID, instance.State)
+		machineScope.SetScheduledEvents(nil)
+		if instance.Lifecycle == infrav1.InstanceLifecycleSpot {
+			machineScope.Info("EC2 spot instance termination", "state", instance.State, "instance-id", *machineScope.GetInstanceID())
+			r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "SpotInstanceTermination", "EC2 spot instance was terminated")
+		} else {
+			machineScope.Info("Unexpected EC2 instance termination", "state", instance.State, "instance-id", *machineScope.GetInstanceID())
+			r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "InstanceUnexpectedTermination", "Unexpected EC2 instance termination")
+		}
 	default:
 		machineScope.SetNotReady()
+		machineScope.SetConditionFalse(infrav1.InstanceProvisioned, infrav1.InstanceStateUnknownReason, "EC2 instance state %q is undefined", instance.State)
 		machineScope.Info("EC2 instance state is undefined", "state", instance.State, "instance-id", *machineScope.GetInstanceID())
 		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "InstanceUnhandledState", "EC2 instance state is undefined")
 		machineScope.SetFailureReason(capierrors.UpdateMachineError)
@@ -406,16 +581,33 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 	}
 
 	if instance.State == infrav1.InstanceStateTerminated {
-		machineScope.SetFailureReason(capierrors.UpdateMachineError)
-		machineScope.SetFailureMessage(errors.Errorf("EC2 instance state %q is unexpected", instance.State))
+		if instance.Lifecycle == infrav1.InstanceLifecycleSpot {
+			machineScope.SetFailureReason(infrav1.SpotInstanceTerminationError)
+			machineScope.SetFailureMessage(errors.New("EC2 spot instance was terminated, most likely reclaimed by EC2"))
+		} else {
+			machineScope.SetFailureReason(capierrors.UpdateMachineError)
+			machineScope.SetFailureMessage(errors.Errorf("EC2 instance state %q is unexpected", instance.State))
+		}
+	}
+
+	if !machineScope.HasFailed() {
+		resizing, err := r.reconcileInPlaceResize(machineScope, ec2svc, instance)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if resizing {
+			return ctrl.Result{RequeueAfter: instanceResizeRequeueAfter}, nil
+		}
 	}
 
 	// tasks that can take place during all known instance states
 	if machineScope.InstanceIsInKnownState() {
 		_, err = r.ensureTags(ec2svc, machineScope.AWSMachine, machineScope.GetInstanceID(), machineScope.AdditionalTags())
 		if err != nil {
+			machineScope.SetConditionFalse(infrav1.TagsApplied, infrav1.TagsFailedReason, "Failed to ensure tags: %v", err)
 			return ctrl.Result{}, errors.Errorf("failed to ensure tags: %+v", err)
 		}
+		machineScope.SetConditionTrue(infrav1.TagsApplied)
 	}
 
 	// tasks that can only take place during operational instance states
@@ -423,25 +615,80 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 		machineScope.SetAddresses(instance.Addresses)
 
 		if err := r.reconcileLBAttachment(machineScope, clusterScope, instance); err != nil {
+			machineScope.SetConditionFalse(infrav1.ELBAttached, infrav1.ELBAttachFailedReason, "Failed to register instance with the API server load balancer: %v", err)
 			return ctrl.Result{}, errors.Errorf("failed to reconcile LB attachment: %+v", err)
 		}
+		if machineScope.IsControlPlane() {
+			machineScope.SetConditionTrue(infrav1.ELBAttached)
+		}
+
+		if machineScope.AWSMachine.Spec.ElasticIP != nil {
+			if err := ec2svc.ReconcileElasticIP(machineScope, instance); err != nil {
+				return ctrl.Result{}, errors.Errorf("failed to reconcile Elastic IP: %+v", err)
+			}
+		}
 
 		existingSecurityGroups, err := ec2svc.GetInstanceSecurityGroups(*machineScope.GetInstanceID())
 		if err != nil {
+			machineScope.SetConditionFalse(infrav1.SecurityGroupsReady, infrav1.SecurityGroupsFailedReason, "Failed to get security groups of the instance: %v", err)
 			return ctrl.Result{}, err
 		}
 
+		var additionalSecurityGroups []string
+		if len(machineScope.AWSMachine.Spec.AdditionalSecurityGroups) > 0 {
+			additionalSecurityGroups, err = ec2svc.GetAdditionalSecurityGroupsIDs(machineScope.AWSMachine.Spec.AdditionalSecurityGroups)
+			if err != nil {
+				r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedResolveSecurityGroups", "Failed to resolve additional security groups: %v", err)
+				machineScope.SetConditionFalse(infrav1.SecurityGroupsReady, infrav1.SecurityGroupsFailedReason, "Failed to resolve additional security groups: %v", err)
+				return ctrl.Result{}, errors.Errorf("failed to resolve additional security groups: %+v", err)
+			}
+		}
+
+		coreSecurityGroups, err := ec2svc.GetCoreSecurityGroups(machineScope)
+		if err != nil {
+			machineScope.SetConditionFalse(infrav1.SecurityGroupsReady, infrav1.SecurityGroupsFailedReason, "Failed to get core security groups: %v", err)
+			return ctrl.Result{}, errors.Errorf("failed to get core security groups: %+v", err)
+		}
+
+		// Report changes made to the instance outside of the controller before correcting them.
+		if err := r.reconcileDrift(machineScope, instance, coreSecurityGroups, existingSecurityGroups); err != nil {
+			return ctrl.Result{}, errors.Errorf("failed to reconcile drift: %+v", err)
+		}
+
 		// Ensure that the security groups are correct.
-		_, err = r.ensureSecurityGroups(ec2svc, machineScope, machineScope.AWSMachine.Spec.AdditionalSecurityGroups, existingSecurityGroups)
+		_, err = r.ensureSecurityGroups(ec2svc, machineScope, coreSecurityGroups, additionalSecurityGroups, existingSecurityGroups)
 		if err != nil {
+			machineScope.SetConditionFalse(infrav1.SecurityGroupsReady, infrav1.SecurityGroupsFailedReason, "Failed to apply security groups: %v", err)
 			return ctrl.Result{}, errors.Errorf("failed to apply security groups: %+v", err)
 		}
+		machineScope.SetConditionTrue(infrav1.SecurityGroupsReady)
+
+		// Ensure that the instance metadata options are up to date.
+		if machineScope.AWSMachine.Spec.InstanceMetadataOptions != nil {
+			updated, err := ec2svc.UpdateInstanceMetadataOptions(instance, machineScope.AWSMachine.Spec.InstanceMetadataOptions)
+			if err != nil {
+				r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedUpdateMetadataOptions", "Failed to update metadata options of instance %q: %v", instance.ID, err)
+				return ctrl.Result{}, errors.Errorf("failed to update instance metadata options: %+v", err)
+			}
+			if updated {
+				r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "SuccessfulUpdateMetadataOptions", "Updated metadata options of instance %q", instance.ID)
+			}
+		}
+
+		if instance.State == infrav1.InstanceStateRunning {
+			if err := r.reconcileScheduledEvents(ctx, machineScope, ec2svc, instance); err != nil {
+				return ctrl.Result{}, errors.Errorf("failed to reconcile scheduled events: %+v", err)
+			}
+			if len(machineScope.AWSMachine.Status.ScheduledEvents) > 0 {
+				return ctrl.Result{RequeueAfter: instanceScheduledEventsRequeueAfter}, nil
+			}
+		}
 	}
 
 	return ctrl.Result{}, nil
 }
 
-func (r *AWSMachineReconciler) deleteEncryptedBootstrapDataSecret(machineScope *scope.MachineScope, secretSvc services.SecretsManagerInterface) error {
+func (r *AWSMachineReconciler) deleteEncryptedBootstrapDataSecret(machineScope *scope.MachineScope, secretSvc services.SecretInterface) error {
 	// do nothing if there isn't a secret
 	if machineScope.GetSecretPrefix() == "" {
 		return nil
@@ -454,13 +701,14 @@ func (r *AWSMachineReconciler) deleteEncryptedBootstrapDataSecret(machineScope *
 	if !machineScope.HasFailed() && machineScope.InstanceIsOperational() && machineScope.Machine.Status.NodeRef == nil && !machineScope.AWSMachineIsDeleted() {
 		return nil
 	}
-	machineScope.Info("Deleting unneeded entry from AWS Secrets Manager", "secretPrefix", machineScope.GetSecretPrefix())
+	backend := machineScope.SecureSecretsBackend()
+	machineScope.Info("Deleting unneeded entry from secure secrets backend", "backend", backend, "secretPrefix", machineScope.GetSecretPrefix())
 	if err := secretSvc.Delete(machineScope); err != nil {
-		machineScope.Info("Unable to delete entries from AWS Secrets Manager containing encrypted userdata", "secretPrefix", machineScope.GetSecretPrefix())
-		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedDeleteEncryptedBootstrapDataSecrets", "AWS Secret Manager entries containing userdata not deleted")
+		machineScope.Info("Unable to delete entries from secure secrets backend containing encrypted userdata", "backend", backend, "secretPrefix", machineScope.GetSecretPrefix())
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedDeleteEncryptedBootstrapDataSecrets", "%s entries containing userdata not deleted", backend)
 		return err
 	}
-	r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "SuccessfulDeleteEncryptedBootstrapDataSecrets", "AWS Secret Manager entries containing userdata deleted")
+	r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "SuccessfulDeleteEncryptedBootstrapDataSecrets", "%s entries containing userdata deleted", backend)
 
 	machineScope.DeleteSecretPrefix()
 	machineScope.SetSecretCount(0)
@@ -468,7 +716,7 @@ func (r *AWSMachineReconciler) deleteEncryptedBootstrapDataSecret(machineScope *
 	return nil
 }
 
-func (r *AWSMachineReconciler) getOrCreate(scope *scope.MachineScope, ec2svc services.EC2MachineInterface, secretSvc services.SecretsManagerInterface) (*infrav1.Instance, error) {
+func (r *AWSMachineReconciler) getOrCreate(scope *scope.MachineScope, ec2svc services.EC2MachineInterface, secretSvc services.SecretInterface) (*infrav1.Instance, error) {
 	instance, err := r.findInstance(scope, ec2svc)
 	if err != nil {
 		return nil, err
@@ -476,6 +724,7 @@ func (r *AWSMachineReconciler) getOrCreate(scope *scope.MachineScope, ec2svc ser
 
 	// If we find an instance, return it
 	if instance != nil {
+		scope.SetConditionTrue(infrav1.BootstrapDataSecretReady)
 		return instance, nil
 	}
 	// Otherwise create a new instance
@@ -484,16 +733,18 @@ func (r *AWSMachineReconciler) getOrCreate(scope *scope.MachineScope, ec2svc ser
 	userData, err := scope.GetRawBootstrapData()
 	if err != nil {
 		r.Recorder.Eventf(scope.AWSMachine, corev1.EventTypeWarning, "FailedGetBootstrapData", err.Error())
+		scope.SetConditionFalse(infrav1.BootstrapDataSecretReady, infrav1.BootstrapDataFailedReason, "Failed to get bootstrap data: %v", err)
 		return nil, err
 	}
 
 	if scope.UseSecretsManager() {
 		compressedUserData, err := userdata.GzipBytes(userData)
 		if err != nil {
+			scope.SetConditionFalse(infrav1.BootstrapDataSecretReady, infrav1.BootstrapDataFailedReason, "Failed to compress bootstrap data: %v", err)
 			return nil, err
 		}
 		prefix, chunks, serviceErr := secretSvc.Create(scope, compressedUserData)
-		// Only persist the AWS Secrets Manager entries if there is at least one
+		// Only persist the secure secrets backend entries if there is at least one
 		if chunks > 0 {
 			scope.SetSecretPrefix(prefix)
 			scope.SetSecretCount(chunks)
@@ -503,20 +754,24 @@ func (r *AWSMachineReconciler) getOrCreate(scope *scope.MachineScope, ec2svc ser
 			return nil, err
 		}
 		if serviceErr != nil {
-			r.Recorder.Eventf(scope.AWSMachine, corev1.EventTypeWarning, "FailedCreateAWSSecretsManagerSecrets", serviceErr.Error())
-			scope.Error(serviceErr, "Failed to create AWS Secret entry", "secretPrefix", prefix)
+			r.Recorder.Eventf(scope.AWSMachine, corev1.EventTypeWarning, "FailedCreateSecureSecrets", "Failed to create %s secrets: %v", scope.SecureSecretsBackend(), serviceErr)
+			scope.Error(serviceErr, "Failed to create secure secrets entries", "backend", scope.SecureSecretsBackend(), "secretPrefix", prefix)
+			scope.SetConditionFalse(infrav1.BootstrapDataSecretReady, infrav1.BootstrapDataFailedReason, "Failed to create %s secrets: %v", scope.SecureSecretsBackend(), serviceErr)
 			return nil, serviceErr
 		}
-		encryptedCloudInit, err := secretsmanager.GenerateCloudInitMIMEDocument(scope.GetSecretPrefix(), scope.GetSecretCount(), scope.AWSCluster.Spec.Region)
+		encryptedUserData, err := secretSvc.UserData(scope.GetSecretPrefix(), scope.GetSecretCount(), scope.AWSCluster.Spec.Region, scope.BootstrapFormat())
 		if err != nil {
-			r.Recorder.Eventf(scope.AWSMachine, corev1.EventTypeWarning, "FailedGenerateAWSSecretsManagerCloudInit", err.Error())
+			r.Recorder.Eventf(scope.AWSMachine, corev1.EventTypeWarning, "FailedGenerateSecureSecretsUserData", "Failed to generate %s userdata for %s: %v", scope.BootstrapFormat(), scope.SecureSecretsBackend(), err)
+			scope.SetConditionFalse(infrav1.BootstrapDataSecretReady, infrav1.BootstrapDataFailedReason, "Failed to generate %s userdata for %s: %v", scope.BootstrapFormat(), scope.SecureSecretsBackend(), err)
 			return nil, err
 		}
-		userData = encryptedCloudInit
+		userData = encryptedUserData
 	}
+	scope.SetConditionTrue(infrav1.BootstrapDataSecretReady)
 
 	instance, err = ec2svc.CreateInstance(scope, userData)
 	if err != nil {
+		scope.SetConditionFalse(infrav1.InstanceProvisioned, infrav1.InstanceProvisionFailedReason, "Failed to create EC2 instance: %v", err)
 		return nil, errors.Wrapf(err, "failed to create AWSMachine instance")
 	}
 
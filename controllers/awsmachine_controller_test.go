@@ -22,6 +22,7 @@ import (
 	"flag"
 	"fmt"
 
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -36,9 +37,11 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope" //nolint
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/mock_services" //nolint
@@ -51,7 +54,8 @@ var _ = Describe("AWSMachineReconciler", func() {
 		ms         *scope.MachineScope
 		mockCtrl   *gomock.Controller
 		ec2Svc     *mock_services.MockEC2MachineInterface
-		secretSvc  *mock_services.MockSecretsManagerInterface
+		secretSvc  *mock_services.MockSecretInterface
+		s3Svc      *mock_services.MockSecretInterface
 		recorder   *record.FakeRecorder
 	)
 
@@ -115,7 +119,8 @@ var _ = Describe("AWSMachineReconciler", func() {
 
 		mockCtrl = gomock.NewController(GinkgoT())
 		ec2Svc = mock_services.NewMockEC2MachineInterface(mockCtrl)
-		secretSvc = mock_services.NewMockSecretsManagerInterface(mockCtrl)
+		secretSvc = mock_services.NewMockSecretInterface(mockCtrl)
+		s3Svc = mock_services.NewMockSecretInterface(mockCtrl)
 
 		// If your test hangs for 9 minutes, increase the value here to the number of events during a reconciliation loop
 		recorder = record.NewFakeRecorder(2)
@@ -124,9 +129,12 @@ var _ = Describe("AWSMachineReconciler", func() {
 			ec2ServiceFactory: func(*scope.ClusterScope) services.EC2MachineInterface {
 				return ec2Svc
 			},
-			secretsManagerServiceFactory: func(*scope.ClusterScope) services.SecretsManagerInterface {
+			secretsManagerServiceFactory: func(*scope.ClusterScope) services.SecretInterface {
 				return secretSvc
 			},
+			s3ServiceFactory: func(*scope.ClusterScope) services.SecretInterface {
+				return s3Svc
+			},
 			Recorder: recorder,
 		}
 
@@ -183,6 +191,21 @@ var _ = Describe("AWSMachineReconciler", func() {
 				Expect(buf.String()).To(ContainSubstring("Bootstrap data secret reference is not yet available"))
 			})
 
+			It("should set conditions while waiting for the bootstrap data secret reference", func() {
+				ms.Machine.Spec.Bootstrap.DataSecretName = nil
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs)
+				Expect(err).To(BeNil())
+				Expect(ms.AWSMachine.Status.GetCondition(infrav1.BootstrapDataSecretReady)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Status": Equal(corev1.ConditionFalse),
+					"Reason": Equal(infrav1.WaitingForBootstrapDataReason),
+				})))
+				Expect(ms.AWSMachine.Status.GetCondition(infrav1.InstanceProvisioned)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Status": Equal(corev1.ConditionFalse),
+					"Reason": Equal(infrav1.WaitingForBootstrapDataReason),
+				})))
+			})
+
 			It("should return an error when we can't list instances by tags", func() {
 
 				_, err := reconciler.reconcileNormal(context.Background(), ms, cs)
@@ -211,11 +234,27 @@ var _ = Describe("AWSMachineReconciler", func() {
 				expectedErr := errors.New("Invalid instance")
 				ec2Svc.EXPECT().InstanceIfExists(gomock.Any()).Return(nil, nil)
 				secretSvc.EXPECT().Create(gomock.Any(), gomock.Any()).Return("test", int32(1), nil).Times(1)
+				secretSvc.EXPECT().UserData(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("userdata"), nil).Times(1)
 				ec2Svc.EXPECT().CreateInstance(gomock.Any(), gomock.Any()).Return(nil, expectedErr)
 
 				_, err := reconciler.reconcileNormal(context.Background(), ms, cs)
 				Expect(errors.Cause(err)).To(MatchError(expectedErr))
 			})
+
+			It("should set conditions when the instance can't be created", func() {
+				ec2Svc.EXPECT().InstanceIfExists(gomock.Any()).Return(nil, nil)
+				secretSvc.EXPECT().Create(gomock.Any(), gomock.Any()).Return("test", int32(1), nil).Times(1)
+				secretSvc.EXPECT().UserData(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("userdata"), nil).Times(1)
+				ec2Svc.EXPECT().CreateInstance(gomock.Any(), gomock.Any()).Return(nil, errors.New("Invalid instance"))
+
+				_, _ = reconciler.reconcileNormal(context.Background(), ms, cs)
+				Expect(ms.AWSMachine.Status.IsConditionTrue(infrav1.BootstrapDataSecretReady)).To(BeTrue())
+				Expect(ms.AWSMachine.Status.GetCondition(infrav1.InstanceProvisioned)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Status":  Equal(corev1.ConditionFalse),
+					"Reason":  Equal(infrav1.InstanceProvisionFailedReason),
+					"Message": ContainSubstring("Invalid instance"),
+				})))
+			})
 		})
 
 		When("instance creation succeeds", func() {
@@ -228,6 +267,7 @@ var _ = Describe("AWSMachineReconciler", func() {
 
 				ec2Svc.EXPECT().GetRunningInstanceByTags(gomock.Any()).Return(nil, nil)
 				secretSvc.EXPECT().Create(gomock.Any(), gomock.Any()).Return("test", int32(1), nil).Times(1)
+				secretSvc.EXPECT().UserData(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("userdata"), nil).Times(1)
 				ec2Svc.EXPECT().CreateInstance(gomock.Any(), gomock.Any()).Return(instance, nil)
 			})
 
@@ -282,6 +322,37 @@ var _ = Describe("AWSMachineReconciler", func() {
 				})
 			})
 
+			Context("Security Groups fail to resolve", func() {
+				BeforeEach(func() {
+					ec2Svc.EXPECT().GetInstanceSecurityGroups(gomock.Any()).
+						Return(map[string][]string{"eid": {}}, nil)
+				})
+
+				It("should record an event when additional security groups cannot be resolved", func() {
+					ms.AWSMachine.Spec.AdditionalSecurityGroups = []infrav1.AWSResourceReference{
+						{
+							Filters: []infrav1.Filter{
+								{
+									Name:   "tag:Name",
+									Values: []string{"corp-monitoring"},
+								},
+							},
+						},
+					}
+					ec2Svc.EXPECT().GetAdditionalSecurityGroupsIDs(ms.AWSMachine.Spec.AdditionalSecurityGroups).
+						Return(nil, errors.New("security group filters matched no security groups"))
+					ec2Svc.EXPECT().UpdateInstanceSecurityGroups(gomock.Any(), gomock.Any()).Times(0)
+
+					_, err := reconciler.reconcileNormal(context.Background(), ms, cs)
+					Expect(err).ToNot(BeNil())
+					Eventually(recorder.Events).Should(Receive(ContainSubstring("FailedResolveSecurityGroups")))
+					Expect(ms.AWSMachine.Status.GetCondition(infrav1.SecurityGroupsReady)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+						"Status": Equal(corev1.ConditionFalse),
+						"Reason": Equal(infrav1.SecurityGroupsFailedReason),
+					})))
+				})
+			})
+
 			Context("Security Groups succeed", func() {
 				BeforeEach(func() {
 					ec2Svc.EXPECT().GetInstanceSecurityGroups(gomock.Any()).
@@ -296,11 +367,142 @@ var _ = Describe("AWSMachineReconciler", func() {
 						},
 					}
 					// ms.AWSMachine.Spec.AdditionalSecurityGroups = []infrav1
+					ec2Svc.EXPECT().GetAdditionalSecurityGroupsIDs(ms.AWSMachine.Spec.AdditionalSecurityGroups).Return([]string{"sg-2345"}, nil)
 					ec2Svc.EXPECT().UpdateInstanceSecurityGroups(instance.ID, []string{"sg-2345"})
 
 					_, _ = reconciler.reconcileNormal(context.Background(), ms, cs)
 				})
 
+				It("should set conditions once the instance is running", func() {
+					instance.State = infrav1.InstanceStateRunning
+					secretSvc.EXPECT().Delete(gomock.Any()).Return(nil).AnyTimes()
+					ec2Svc.EXPECT().GetInstanceScheduledEvents(instance.ID).Return(nil, nil)
+
+					_, err := reconciler.reconcileNormal(context.Background(), ms, cs)
+					Expect(err).To(BeNil())
+					Expect(ms.AWSMachine.Status.IsConditionTrue(infrav1.InstanceProvisioned)).To(BeTrue())
+					Expect(ms.AWSMachine.Status.IsConditionTrue(infrav1.BootstrapDataSecretReady)).To(BeTrue())
+					Expect(ms.AWSMachine.Status.IsConditionTrue(infrav1.TagsApplied)).To(BeTrue())
+					Expect(ms.AWSMachine.Status.IsConditionTrue(infrav1.SecurityGroupsReady)).To(BeTrue())
+					Expect(ms.AWSMachine.Status.GetCondition(infrav1.ELBAttached)).To(BeNil())
+					Expect(ms.AWSMachine.Status.IsConditionTrue(infrav1.InstanceAvailable)).To(BeTrue())
+					Expect(ms.AWSMachine.Status.IsConditionTrue(infrav1.Drifted)).To(BeFalse())
+				})
+
+				It("should not report a change to the additional security groups as drift", func() {
+					instance.State = infrav1.InstanceStateRunning
+					ms.AWSMachine.Annotations = map[string]string{SecurityGroupsLastAppliedAnnotation: "{}"}
+					ms.AWSMachine.Spec.AdditionalSecurityGroups = []infrav1.AWSResourceReference{
+						{
+							ID: pointer.StringPtr("sg-2345"),
+						},
+					}
+					secretSvc.EXPECT().Delete(gomock.Any()).Return(nil).AnyTimes()
+					ec2Svc.EXPECT().GetInstanceScheduledEvents(instance.ID).Return(nil, nil)
+					ec2Svc.EXPECT().GetAdditionalSecurityGroupsIDs(ms.AWSMachine.Spec.AdditionalSecurityGroups).Return([]string{"sg-2345"}, nil)
+					ec2Svc.EXPECT().UpdateInstanceSecurityGroups(instance.ID, []string{"sg-2345"}).Return(nil)
+
+					_, err := reconciler.reconcileNormal(context.Background(), ms, cs)
+					Expect(err).To(BeNil())
+					Expect(ms.AWSMachine.Status.GetCondition(infrav1.Drifted)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+						"Status": Equal(corev1.ConditionFalse),
+						"Reason": Equal(infrav1.InstanceInSyncReason),
+					})))
+					Expect(ms.AWSMachine.Annotations[SecurityGroupsLastAppliedAnnotation]).To(Equal(`{"sg-2345":{}}`))
+					Consistently(recorder.Events).ShouldNot(Receive(ContainSubstring("InstanceDrifted")))
+				})
+
+				It("should report changes made to the instance outside of the controller", func() {
+					instance.State = infrav1.InstanceStateRunning
+					instance.Type = "t3.medium"
+					ms.AWSMachine.Spec.InstanceType = "t3.large"
+					secretSvc.EXPECT().Delete(gomock.Any()).Return(nil).AnyTimes()
+					ec2Svc.EXPECT().GetInstanceScheduledEvents(instance.ID).Return(nil, nil)
+
+					_, err := reconciler.reconcileNormal(context.Background(), ms, cs)
+					Expect(err).To(BeNil())
+					Expect(ms.AWSMachine.Status.GetCondition(infrav1.Drifted)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+						"Status":  Equal(corev1.ConditionTrue),
+						"Reason":  Equal(infrav1.InstanceDriftedReason),
+						"Message": ContainSubstring(`instanceType: expected "t3.large", found "t3.medium"`),
+					})))
+					Eventually(recorder.Events).Should(Receive(ContainSubstring("InstanceDrifted")))
+				})
+
+				Context("with scheduled events", func() {
+					var machine *clusterv1.Machine
+
+					BeforeEach(func() {
+						instance.State = infrav1.InstanceStateRunning
+						secretSvc.EXPECT().Delete(gomock.Any()).Return(nil).AnyTimes()
+
+						machine = ms.Machine
+						machine.Name = "test"
+						machine.Namespace = "default"
+						testScheme, err := setupScheme()
+						Expect(err).To(BeNil())
+						reconciler.Client = fake.NewFakeClientWithScheme(testScheme, machine.DeepCopy())
+						reconciler.MaintenanceAnnotation = "example.com/maintenance"
+					})
+
+					It("should record scheduled events and annotate the Machine", func() {
+						ec2Svc.EXPECT().GetInstanceScheduledEvents(instance.ID).Return([]infrav1.InstanceScheduledEvent{
+							{Code: "instance-retirement", Description: "The instance is running on degraded hardware"},
+						}, nil)
+
+						result, err := reconciler.reconcileNormal(context.Background(), ms, cs)
+						Expect(err).To(BeNil())
+						Expect(result.RequeueAfter).To(Equal(instanceScheduledEventsRequeueAfter))
+						Expect(ms.AWSMachine.Status.ScheduledEvents).To(HaveLen(1))
+						Expect(ms.AWSMachine.Status.GetCondition(infrav1.InstanceAvailable)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+							"Status": Equal(corev1.ConditionFalse),
+							"Reason": Equal(infrav1.InstanceEventScheduledReason),
+						})))
+						Eventually(recorder.Events).Should(Receive(ContainSubstring("InstanceEventScheduled")))
+
+						updated := &clusterv1.Machine{}
+						Expect(reconciler.Client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "test"}, updated)).To(Succeed())
+						Expect(updated.Annotations).To(HaveKeyWithValue("example.com/maintenance", "instance-retirement"))
+					})
+
+					It("should remove the annotation once the events are over", func() {
+						machine.Annotations = map[string]string{"example.com/maintenance": "system-reboot"}
+						ms.AWSMachine.Status.ScheduledEvents = []infrav1.InstanceScheduledEvent{{Code: "system-reboot"}}
+						ec2Svc.EXPECT().GetInstanceScheduledEvents(instance.ID).Return(nil, nil)
+
+						result, err := reconciler.reconcileNormal(context.Background(), ms, cs)
+						Expect(err).To(BeNil())
+						Expect(result.RequeueAfter).To(BeZero())
+						Expect(ms.AWSMachine.Status.ScheduledEvents).To(BeEmpty())
+						Expect(ms.AWSMachine.Status.IsConditionTrue(infrav1.InstanceAvailable)).To(BeTrue())
+						Expect(machine.Annotations).NotTo(HaveKey("example.com/maintenance"))
+					})
+
+					It("should report spot interruption notices", func() {
+						ms.AWSMachine.Status.ScheduledEvents = []infrav1.InstanceScheduledEvent{
+							{Code: infrav1.SpotInstanceInterruptionEventCode, Description: "Spot instance interruption, instance action is terminate"},
+						}
+						ec2Svc.EXPECT().GetInstanceScheduledEvents(instance.ID).Return(nil, nil)
+
+						result, err := reconciler.reconcileNormal(context.Background(), ms, cs)
+						Expect(err).To(BeNil())
+						Expect(result.RequeueAfter).To(Equal(instanceScheduledEventsRequeueAfter))
+						Expect(ms.AWSMachine.Status.ScheduledEvents).To(HaveLen(1))
+						Expect(ms.AWSMachine.Status.GetCondition(infrav1.InstanceAvailable)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+							"Status": Equal(corev1.ConditionFalse),
+							"Reason": Equal(infrav1.SpotInstanceInterruptionReason),
+						})))
+						Expect(machine.Annotations).To(HaveKeyWithValue("example.com/maintenance", infrav1.SpotInstanceInterruptionEventCode))
+					})
+
+					It("should return an error when the scheduled events can't be retrieved", func() {
+						ec2Svc.EXPECT().GetInstanceScheduledEvents(instance.ID).Return(nil, errors.New("failed to describe instance status"))
+
+						_, err := reconciler.reconcileNormal(context.Background(), ms, cs)
+						Expect(err).NotTo(BeNil())
+					})
+				})
+
 				It("should not tag anything if there's not tags", func() {
 					ec2Svc.EXPECT().UpdateInstanceSecurityGroups(gomock.Any(), gomock.Any()).Times(0)
 					if _, err := reconciler.reconcileNormal(context.Background(), ms, cs); err != nil {
@@ -308,6 +510,18 @@ var _ = Describe("AWSMachineReconciler", func() {
 					}
 				})
 
+				It("should update the instance metadata options", func() {
+					ms.AWSMachine.Spec.InstanceMetadataOptions = &infrav1.InstanceMetadataOptions{
+						HTTPTokens:              "required",
+						HTTPPutResponseHopLimit: 2,
+					}
+					ec2Svc.EXPECT().UpdateInstanceMetadataOptions(instance, ms.AWSMachine.Spec.InstanceMetadataOptions).Return(true, nil)
+
+					_, err := reconciler.reconcileNormal(context.Background(), ms, cs)
+					Expect(err).To(BeNil())
+					Eventually(recorder.Events).Should(Receive(ContainSubstring("SuccessfulUpdateMetadataOptions")))
+				})
+
 				It("should tag instances from machine and cluster tags", func() {
 
 					ms.AWSMachine.Spec.AdditionalTags = infrav1.Tags{"kind": "alicorn"}
@@ -355,6 +569,7 @@ var _ = Describe("AWSMachineReconciler", func() {
 
 				It("should then set instance to running and ready once it is restarted", func() {
 					instance.State = infrav1.InstanceStateRunning
+					ec2Svc.EXPECT().GetInstanceScheduledEvents(instance.ID).Return(nil, nil)
 					_, _ = reconciler.reconcileNormal(context.Background(), ms, cs)
 					Expect(ms.AWSMachine.Status.InstanceState).To(PointTo(Equal(infrav1.InstanceStateRunning)))
 					Expect(ms.AWSMachine.Status.Ready).To(Equal(true))
@@ -386,6 +601,17 @@ var _ = Describe("AWSMachineReconciler", func() {
 					Eventually(recorder.Events).Should(Receive(ContainSubstring("UnexpectedTermination")))
 					Expect(ms.AWSMachine.Status.FailureMessage).To(PointTo(Equal("EC2 instance state \"terminated\" is unexpected")))
 				})
+
+				It("should set a distinct failure reason when a spot instance is terminated", func() {
+					instance.State = infrav1.InstanceStateTerminated
+					instance.Lifecycle = infrav1.InstanceLifecycleSpot
+					_, _ = reconciler.reconcileNormal(context.Background(), ms, cs)
+					Expect(ms.AWSMachine.Status.Ready).To(Equal(false))
+					Expect(buf.String()).To(ContainSubstring(("EC2 spot instance termination")))
+					Eventually(recorder.Events).Should(Receive(ContainSubstring("SpotInstanceTermination")))
+					Expect(ms.AWSMachine.Status.FailureReason).To(PointTo(Equal(infrav1.SpotInstanceTerminationError)))
+					Expect(ms.AWSMachine.Status.InstanceLifecycle).To(PointTo(Equal(infrav1.InstanceLifecycleSpot)))
+				})
 			})
 		})
 	})
@@ -397,6 +623,7 @@ var _ = Describe("AWSMachineReconciler", func() {
 			BeforeEach(func() {
 				ec2Svc.EXPECT().GetRunningInstanceByTags(gomock.Any()).Return(nil, nil).AnyTimes()
 				secretSvc.EXPECT().Create(gomock.Any(), gomock.Any()).Return(secretPrefix, int32(1), nil).Times(1)
+				secretSvc.EXPECT().UserData(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("userdata"), nil).Times(1)
 				ec2Svc.EXPECT().CreateInstance(gomock.Any(), gomock.Any()).Return(instance, nil).AnyTimes()
 			})
 
@@ -409,6 +636,69 @@ var _ = Describe("AWSMachineReconciler", func() {
 			})
 		})
 
+		When("creating EC2 instances with the s3 secure secrets backend", func() {
+			objectKey := "test/object"
+			BeforeEach(func() {
+				ms.AWSMachine.Spec.CloudInit.SecureSecretsBackend = infrav1.SecretBackendS3
+				ec2Svc.EXPECT().GetRunningInstanceByTags(gomock.Any()).Return(nil, nil).AnyTimes()
+				s3Svc.EXPECT().Create(gomock.Any(), gomock.Any()).Return(objectKey, int32(1), nil).Times(1)
+				s3Svc.EXPECT().UserData(objectKey, int32(1), gomock.Any(), infrav1.BootstrapFormatCloudConfig).Return([]byte("userdata"), nil).Times(1)
+				ec2Svc.EXPECT().CreateInstance(gomock.Any(), []byte("userdata")).Return(instance, nil).AnyTimes()
+			})
+
+			It("should leverage S3", func() {
+				_, _ = reconciler.reconcileNormal(context.Background(), ms, cs)
+				Expect(ms.AWSMachine.Spec.CloudInit.SecretPrefix).To(Equal(objectKey))
+				Expect(ms.AWSMachine.Spec.CloudInit.SecretCount).To(Equal(int32(1)))
+			})
+		})
+
+		When("creating EC2 instances with ignition bootstrap data", func() {
+			objectKey := "test/object"
+			BeforeEach(func() {
+				ms.AWSMachine.Spec.BootstrapFormat = infrav1.BootstrapFormatIgnition
+				ms.AWSMachine.Spec.CloudInit.SecureSecretsBackend = infrav1.SecretBackendS3
+				ec2Svc.EXPECT().GetRunningInstanceByTags(gomock.Any()).Return(nil, nil).AnyTimes()
+				s3Svc.EXPECT().Create(gomock.Any(), gomock.Any()).Return(objectKey, int32(1), nil).Times(1)
+				s3Svc.EXPECT().UserData(objectKey, int32(1), gomock.Any(), infrav1.BootstrapFormatIgnition).Return([]byte("{}"), nil).Times(1)
+				ec2Svc.EXPECT().CreateInstance(gomock.Any(), []byte("{}")).Return(instance, nil).AnyTimes()
+			})
+
+			It("should reference the config stored in S3", func() {
+				_, _ = reconciler.reconcileNormal(context.Background(), ms, cs)
+				Expect(ms.AWSMachine.Spec.CloudInit.SecretPrefix).To(Equal(objectKey))
+			})
+		})
+
+		When("there's a node ref and an object stored with the s3 secure secrets backend", func() {
+			BeforeEach(func() {
+				instance = &infrav1.Instance{
+					ID: "myMachine",
+				}
+
+				ms.Machine.Status.NodeRef = &corev1.ObjectReference{
+					Kind:       "Node",
+					Name:       "myMachine",
+					APIVersion: "v1",
+				}
+
+				ms.AWSMachine.Spec.CloudInit = infrav1.CloudInit{
+					SecureSecretsBackend: infrav1.SecretBackendS3,
+					SecretPrefix:         "object",
+					SecretCount:          1,
+				}
+				ec2Svc.EXPECT().GetRunningInstanceByTags(gomock.Any()).Return(instance, nil).AnyTimes()
+			})
+
+			It("should delete the object if the instance is terminated", func() {
+				instance.State = infrav1.InstanceStateTerminated
+				s3Svc.EXPECT().Delete(gomock.Any()).Return(nil).Times(1)
+				secretSvc.EXPECT().Delete(gomock.Any()).Times(0)
+				_, _ = reconciler.reconcileNormal(context.Background(), ms, cs)
+				Expect(ms.GetSecretPrefix()).To(Equal(""))
+			})
+		})
+
 		When("there's a node ref and a secret ARN", func() {
 			BeforeEach(func() {
 				instance = &infrav1.Instance{
@@ -430,6 +720,7 @@ var _ = Describe("AWSMachineReconciler", func() {
 
 			It("should delete the secret if the instance is running", func() {
 				instance.State = infrav1.InstanceStateRunning
+				ec2Svc.EXPECT().GetInstanceScheduledEvents(instance.ID).Return(nil, nil)
 				ec2Svc.EXPECT().GetInstanceSecurityGroups(gomock.Any()).
 					Return(map[string][]string{"eid": {}}, nil).Times(1)
 				secretSvc.EXPECT().Delete(gomock.Any()).Return(nil).Times(1)
@@ -473,6 +764,7 @@ var _ = Describe("AWSMachineReconciler", func() {
 
 			It("should not delete the secret if the instance is running", func() {
 				instance.State = infrav1.InstanceStateRunning
+				ec2Svc.EXPECT().GetInstanceScheduledEvents(instance.ID).Return(nil, nil)
 				ec2Svc.EXPECT().GetInstanceSecurityGroups(gomock.Any()).
 					Return(map[string][]string{"eid": {}}, nil).Times(1)
 				ec2Svc.EXPECT().GetCoreSecurityGroups(gomock.Any()).Return([]string{}, nil).Times(1)
@@ -509,6 +801,7 @@ var _ = Describe("AWSMachineReconciler", func() {
 				secretSvc.EXPECT().Create(gomock.Any(), gomock.Any()).Return(secretPrefix, int32(0), errors.New("connection error")).Times(1)
 				_, err := reconciler.reconcileNormal(context.Background(), ms, cs)
 				Expect(err).ToNot(BeNil())
+				Eventually(recorder.Events).Should(Receive(ContainSubstring("FailedCreateSecureSecrets")))
 				Expect(ms.GetSecretPrefix()).To(Equal(""))
 				Expect(ms.GetSecretCount()).To(Equal(int32(0)))
 				instance = &infrav1.Instance{
@@ -516,6 +809,7 @@ var _ = Describe("AWSMachineReconciler", func() {
 				}
 				instance.State = infrav1.InstanceStatePending
 				secretSvc.EXPECT().Create(gomock.Any(), gomock.Any()).Return(secretPrefix, int32(10), nil).Times(1)
+				secretSvc.EXPECT().UserData(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("userdata"), nil).Times(1)
 				ec2Svc.EXPECT().CreateInstance(gomock.Any(), gomock.Any()).Return(instance, nil).AnyTimes()
 				ec2Svc.EXPECT().GetInstanceSecurityGroups(gomock.Any()).Return(map[string][]string{"eid": {}}, nil).Times(1)
 				ec2Svc.EXPECT().GetCoreSecurityGroups(gomock.Any()).Return([]string{}, nil).Times(1)
@@ -528,6 +822,173 @@ var _ = Describe("AWSMachineReconciler", func() {
 
 	})
 
+	Context("resizing an AWSMachine in place", func() {
+		var instance *infrav1.Instance
+
+		BeforeEach(func() {
+			instance = &infrav1.Instance{
+				ID:    "myMachine",
+				Type:  "m5.large",
+				State: infrav1.InstanceStateRunning,
+			}
+			ms.AWSMachine.Spec.InstanceType = "m5.xlarge"
+			ms.AWSMachine.Spec.InPlaceResize = true
+		})
+
+		It("should not resize the instance when in-place resize is disabled", func() {
+			ms.AWSMachine.Spec.InPlaceResize = false
+
+			resizing, err := reconciler.reconcileInPlaceResize(ms, ec2Svc, instance)
+			Expect(err).To(BeNil())
+			Expect(resizing).To(BeFalse())
+			Expect(ms.AWSMachine.Status.Resize).To(BeNil())
+		})
+
+		It("should not resize an instance running a fallback instance type", func() {
+			ms.AWSMachine.Spec.FallbackInstanceTypes = []string{"m5.large"}
+
+			resizing, err := reconciler.reconcileInPlaceResize(ms, ec2Svc, instance)
+			Expect(err).To(BeNil())
+			Expect(resizing).To(BeFalse())
+		})
+
+		It("should stop the instance when its type changes", func() {
+			ec2Svc.EXPECT().StopInstance("myMachine").Return(nil)
+
+			resizing, err := reconciler.reconcileInPlaceResize(ms, ec2Svc, instance)
+			Expect(err).To(BeNil())
+			Expect(resizing).To(BeTrue())
+			Expect(ms.AWSMachine.Status.Resize).To(Equal(&infrav1.InstanceResize{
+				Phase:        infrav1.InstanceResizePhaseStopping,
+				InstanceType: "m5.xlarge",
+			}))
+			Eventually(recorder.Events).Should(Receive(ContainSubstring("ResizeStarted")))
+		})
+
+		It("should stop the instance when its root volume grows", func() {
+			ms.AWSMachine.Spec.InstanceType = "m5.large"
+			ms.AWSMachine.Spec.RootVolume = &infrav1.RootVolume{Size: 40}
+			ec2Svc.EXPECT().GetRootVolumeSize("myMachine").Return(int64(20), nil)
+			ec2Svc.EXPECT().StopInstance("myMachine").Return(nil)
+
+			resizing, err := reconciler.reconcileInPlaceResize(ms, ec2Svc, instance)
+			Expect(err).To(BeNil())
+			Expect(resizing).To(BeTrue())
+			Expect(ms.AWSMachine.Status.Resize).To(Equal(&infrav1.InstanceResize{
+				Phase:          infrav1.InstanceResizePhaseStopping,
+				RootVolumeSize: 40,
+			}))
+		})
+
+		It("should wait for the instance to be stopped", func() {
+			instance.State = infrav1.InstanceStateStopping
+			ms.AWSMachine.Status.Resize = &infrav1.InstanceResize{
+				Phase:        infrav1.InstanceResizePhaseStopping,
+				InstanceType: "m5.xlarge",
+			}
+
+			resizing, err := reconciler.reconcileInPlaceResize(ms, ec2Svc, instance)
+			Expect(err).To(BeNil())
+			Expect(resizing).To(BeTrue())
+			Expect(ms.AWSMachine.Status.Resize.Phase).To(Equal(infrav1.InstanceResizePhaseStopping))
+		})
+
+		It("should modify and start the stopped instance", func() {
+			instance.State = infrav1.InstanceStateStopped
+			ms.AWSMachine.Status.Resize = &infrav1.InstanceResize{
+				Phase:          infrav1.InstanceResizePhaseStopping,
+				InstanceType:   "m5.xlarge",
+				RootVolumeSize: 40,
+			}
+			ec2Svc.EXPECT().ModifyInstanceType("myMachine", "m5.xlarge").Return(nil)
+			ec2Svc.EXPECT().ModifyRootVolumeSize("myMachine", int64(40)).Return(nil)
+			ec2Svc.EXPECT().StartInstance("myMachine").Return(nil)
+
+			resizing, err := reconciler.reconcileInPlaceResize(ms, ec2Svc, instance)
+			Expect(err).To(BeNil())
+			Expect(resizing).To(BeTrue())
+			Expect(ms.AWSMachine.Status.Resize.Phase).To(Equal(infrav1.InstanceResizePhaseStarting))
+		})
+
+		It("should keep the resize in progress when the instance can't be modified", func() {
+			expected := errors.New("can't reach AWS to modify instance")
+			instance.State = infrav1.InstanceStateStopped
+			ms.AWSMachine.Status.Resize = &infrav1.InstanceResize{
+				Phase:        infrav1.InstanceResizePhaseModifying,
+				InstanceType: "m5.xlarge",
+			}
+			ec2Svc.EXPECT().ModifyInstanceType("myMachine", "m5.xlarge").Return(expected)
+
+			resizing, err := reconciler.reconcileInPlaceResize(ms, ec2Svc, instance)
+			Expect(errors.Cause(err)).To(MatchError(expected))
+			Expect(resizing).To(BeTrue())
+			Expect(ms.AWSMachine.Status.Resize.Phase).To(Equal(infrav1.InstanceResizePhaseModifying))
+			Eventually(recorder.Events).Should(Receive(ContainSubstring("FailedResize")))
+		})
+
+		It("should start the instance with its original type when AWS rejects the new one", func() {
+			instance.State = infrav1.InstanceStateStopped
+			ms.AWSMachine.Status.Resize = &infrav1.InstanceResize{
+				Phase:        infrav1.InstanceResizePhaseModifying,
+				InstanceType: "m5.xlarge",
+			}
+			rejected := awserr.New(awserrors.InvalidParameterValue, "instance type is not supported", nil)
+			ec2Svc.EXPECT().ModifyInstanceType("myMachine", "m5.xlarge").Return(errors.Wrap(rejected, "failed to modify type"))
+			ec2Svc.EXPECT().StartInstance("myMachine").Return(nil)
+
+			resizing, err := reconciler.reconcileInPlaceResize(ms, ec2Svc, instance)
+			Expect(err).To(BeNil())
+			Expect(resizing).To(BeTrue())
+			Expect(ms.AWSMachine.Status.FailedResizeInstanceType).To(Equal("m5.xlarge"))
+			Expect(ms.AWSMachine.Status.GetCondition(infrav1.InstanceResized)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Status": Equal(corev1.ConditionFalse),
+				"Reason": Equal(infrav1.InstanceResizeFailedReason),
+			})))
+			Eventually(recorder.Events).Should(Receive(ContainSubstring("FailedResize")))
+
+			instance.State = infrav1.InstanceStateRunning
+			resizing, err = reconciler.reconcileInPlaceResize(ms, ec2Svc, instance)
+			Expect(err).To(BeNil())
+			Expect(resizing).To(BeFalse())
+			Expect(ms.AWSMachine.Status.Resize).To(BeNil())
+			Expect(ms.AWSMachine.Status.IsConditionTrue(infrav1.InstanceResized)).To(BeFalse())
+		})
+
+		It("should not resize the instance again to a rejected instance type", func() {
+			ms.AWSMachine.Status.FailedResizeInstanceType = "m5.xlarge"
+
+			resizing, err := reconciler.reconcileInPlaceResize(ms, ec2Svc, instance)
+			Expect(err).To(BeNil())
+			Expect(resizing).To(BeFalse())
+			Expect(ms.AWSMachine.Status.Resize).To(BeNil())
+		})
+
+		It("should resize the instance once another instance type is requested", func() {
+			ms.AWSMachine.Status.FailedResizeInstanceType = "m5.2xlarge"
+			ec2Svc.EXPECT().StopInstance("myMachine").Return(nil)
+
+			resizing, err := reconciler.reconcileInPlaceResize(ms, ec2Svc, instance)
+			Expect(err).To(BeNil())
+			Expect(resizing).To(BeTrue())
+			Expect(ms.AWSMachine.Status.FailedResizeInstanceType).To(BeEmpty())
+			Expect(ms.AWSMachine.Status.Resize.InstanceType).To(Equal("m5.xlarge"))
+		})
+
+		It("should complete the resize once the instance is running again", func() {
+			instance.Type = "m5.xlarge"
+			ms.AWSMachine.Status.Resize = &infrav1.InstanceResize{
+				Phase:        infrav1.InstanceResizePhaseStarting,
+				InstanceType: "m5.xlarge",
+			}
+
+			resizing, err := reconciler.reconcileInPlaceResize(ms, ec2Svc, instance)
+			Expect(err).To(BeNil())
+			Expect(resizing).To(BeFalse())
+			Expect(ms.AWSMachine.Status.Resize).To(BeNil())
+			Eventually(recorder.Events).Should(Receive(ContainSubstring("SuccessfulResize")))
+		})
+	})
+
 	Context("deleting an AWSMachine", func() {
 
 		BeforeEach(func() {
@@ -606,6 +1067,93 @@ var _ = Describe("AWSMachineReconciler", func() {
 				Eventually(recorder.Events).Should(Receive(ContainSubstring("FailedTerminate")))
 			})
 
+			It("should disable termination protection before terminating the instance", func() {
+				ms.AWSMachine.Spec.DisableAPITermination = true
+				gomock.InOrder(
+					ec2Svc.EXPECT().DisableTerminationProtection(id).Return(nil),
+					ec2Svc.EXPECT().TerminateInstanceAndWait(id).Return(nil),
+				)
+
+				_, err := reconciler.reconcileDelete(ms, cs)
+				Expect(err).To(BeNil())
+				Expect(ms.AWSMachine.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
+				Eventually(recorder.Events).Should(Receive(ContainSubstring("SuccessfulTerminate")))
+			})
+
+			It("should keep the finalizer when termination protection can't be disabled", func() {
+				expected := errors.New("can't reach AWS to modify instance")
+				ms.AWSMachine.Spec.DisableAPITermination = true
+				ec2Svc.EXPECT().DisableTerminationProtection(id).Return(expected)
+
+				_, err := reconciler.reconcileDelete(ms, cs)
+				Expect(errors.Cause(err)).To(MatchError(expected))
+				Expect(ms.AWSMachine.Finalizers).To(ContainElement(infrav1.MachineFinalizer))
+				Eventually(recorder.Events).Should(Receive(ContainSubstring("FailedDisableTerminationProtection")))
+			})
+
+			It("should retain the instance with the Retain deletion policy", func() {
+				ms.AWSMachine.Spec.DeletionPolicy = infrav1.MachineDeletionPolicyRetain
+				ms.AWSMachine.Spec.DisableAPITermination = true
+				ms.AWSMachine.Spec.ElasticIP = &infrav1.ElasticIP{}
+				gomock.InOrder(
+					ec2Svc.EXPECT().DetachCoreSecurityGroupsFromInstance(gomock.Any(), id).Return(nil),
+					ec2Svc.EXPECT().DisownInstance(gomock.Any(), id).Return(nil),
+				)
+
+				_, err := reconciler.reconcileDelete(ms, cs)
+				Expect(err).To(BeNil())
+				Expect(ms.AWSMachine.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
+				Eventually(recorder.Events).Should(Receive(ContainSubstring("InstanceRetained")))
+			})
+
+			It("should stop and retain the instance with the StopAndRetain deletion policy", func() {
+				ms.AWSMachine.Spec.DeletionPolicy = infrav1.MachineDeletionPolicyStopAndRetain
+				gomock.InOrder(
+					ec2Svc.EXPECT().StopInstance(id).Return(nil),
+					ec2Svc.EXPECT().DetachCoreSecurityGroupsFromInstance(gomock.Any(), id).Return(nil),
+					ec2Svc.EXPECT().DisownInstance(gomock.Any(), id).Return(nil),
+				)
+
+				_, err := reconciler.reconcileDelete(ms, cs)
+				Expect(err).To(BeNil())
+				Expect(ms.AWSMachine.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
+				Eventually(recorder.Events).Should(Receive(ContainSubstring("InstanceRetained")))
+			})
+
+			It("should keep the finalizer when the instance can't be stopped", func() {
+				expected := errors.New("can't reach AWS to stop instance")
+				ms.AWSMachine.Spec.DeletionPolicy = infrav1.MachineDeletionPolicyStopAndRetain
+				ec2Svc.EXPECT().StopInstance(id).Return(expected)
+
+				_, err := reconciler.reconcileDelete(ms, cs)
+				Expect(errors.Cause(err)).To(MatchError(expected))
+				Expect(ms.AWSMachine.Finalizers).To(ContainElement(infrav1.MachineFinalizer))
+				Eventually(recorder.Events).Should(Receive(ContainSubstring("FailedStop")))
+			})
+
+			It("should keep the finalizer when the security groups can't be detached from the instance", func() {
+				expected := errors.New("can't reach AWS to modify network interfaces")
+				ms.AWSMachine.Spec.DeletionPolicy = infrav1.MachineDeletionPolicyRetain
+				ec2Svc.EXPECT().DetachCoreSecurityGroupsFromInstance(gomock.Any(), id).Return(expected)
+
+				_, err := reconciler.reconcileDelete(ms, cs)
+				Expect(errors.Cause(err)).To(MatchError(expected))
+				Expect(ms.AWSMachine.Finalizers).To(ContainElement(infrav1.MachineFinalizer))
+				Eventually(recorder.Events).Should(Receive(ContainSubstring("FailedRetain")))
+			})
+
+			It("should keep the finalizer when the instance can't be removed from the cluster", func() {
+				expected := errors.New("can't reach AWS to remove tags")
+				ms.AWSMachine.Spec.DeletionPolicy = infrav1.MachineDeletionPolicyRetain
+				ec2Svc.EXPECT().DetachCoreSecurityGroupsFromInstance(gomock.Any(), id).Return(nil)
+				ec2Svc.EXPECT().DisownInstance(gomock.Any(), id).Return(expected)
+
+				_, err := reconciler.reconcileDelete(ms, cs)
+				Expect(errors.Cause(err)).To(MatchError(expected))
+				Expect(ms.AWSMachine.Finalizers).To(ContainElement(infrav1.MachineFinalizer))
+				Eventually(recorder.Events).Should(Receive(ContainSubstring("FailedRetain")))
+			})
+
 			When("instance can be shut down", func() {
 				BeforeEach(func() {
 					ec2Svc.EXPECT().TerminateInstanceAndWait(gomock.Any()).Return(nil)
@@ -653,6 +1201,58 @@ var _ = Describe("AWSMachineReconciler", func() {
 					Expect(err).To(BeNil())
 					Expect(ms.AWSMachine.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
 				})
+
+				When("an Elastic IP is configured", func() {
+					It("should release the Elastic IP allocated for the machine", func() {
+						ms.AWSMachine.Spec.ElasticIP = &infrav1.ElasticIP{}
+						ec2Svc.EXPECT().ReleaseElasticIP(gomock.Any()).Return(nil)
+
+						_, err := reconciler.reconcileDelete(ms, cs)
+						Expect(err).To(BeNil())
+						Expect(ms.AWSMachine.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
+					})
+
+					It("should keep the finalizer when the Elastic IP can't be released", func() {
+						expected := errors.New("can't reach AWS to release Elastic IP")
+						ms.AWSMachine.Spec.ElasticIP = &infrav1.ElasticIP{}
+						ec2Svc.EXPECT().ReleaseElasticIP(gomock.Any()).Return(expected)
+
+						_, err := reconciler.reconcileDelete(ms, cs)
+						Expect(errors.Cause(err)).To(MatchError(expected))
+						Expect(ms.AWSMachine.Finalizers).To(ContainElement(infrav1.MachineFinalizer))
+					})
+
+					It("should not release an existing Elastic IP allocation", func() {
+						ms.AWSMachine.Spec.ElasticIP = &infrav1.ElasticIP{AllocationID: pointer.StringPtr("eipalloc-1")}
+
+						_, err := reconciler.reconcileDelete(ms, cs)
+						Expect(err).To(BeNil())
+						Expect(ms.AWSMachine.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
+					})
+				})
+
+				When("there are managed network interfaces", func() {
+					BeforeEach(func() {
+						ms.AWSMachine.Spec.ManagedNetworkInterfaces = []infrav1.ManagedNetworkInterface{{}}
+					})
+
+					It("should delete the managed network interfaces", func() {
+						ec2Svc.EXPECT().DeleteManagedNetworkInterfaces(gomock.Any()).Return(nil)
+
+						_, err := reconciler.reconcileDelete(ms, cs)
+						Expect(err).To(BeNil())
+						Expect(ms.AWSMachine.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
+					})
+
+					It("should keep the finalizer when the network interfaces can't be deleted", func() {
+						expected := errors.New("can't reach AWS to delete network interfaces")
+						ec2Svc.EXPECT().DeleteManagedNetworkInterfaces(gomock.Any()).Return(expected)
+
+						_, err := reconciler.reconcileDelete(ms, cs)
+						Expect(errors.Cause(err)).To(MatchError(expected))
+						Expect(ms.AWSMachine.Finalizers).To(ContainElement(infrav1.MachineFinalizer))
+					})
+				})
 			})
 		})
 	})
@@ -17,14 +17,20 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+	"reflect"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/klogr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 )
@@ -109,3 +115,181 @@ func TestAWSMachineReconciler_AWSClusterToAWSMachines(t *testing.T) {
 		t.Fatalf("Expected 2 but found %d requests", len(initObjects))
 	}
 }
+
+func TestAWSMachineReconciler_requestsForInstanceEvent(t *testing.T) {
+	scheme, err := setupScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newAWSMachine := func(name, providerID string) *infrav1.AWSMachine {
+		m := &infrav1.AWSMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}
+		if providerID != "" {
+			m.Spec.ProviderID = aws.String(providerID)
+		}
+		return m
+	}
+	pending := newAWSMachine("my-machine-3", "")
+	pending.Labels = map[string]string{clusterv1.ClusterLabelName: "my-cluster"}
+	initObjects := []runtime.Object{
+		newAWSMachine("my-machine-0", "aws:///us-east-1a/i-0"),
+		newAWSMachine("my-machine-1", "aws:///us-east-1a/i-1"),
+		newAWSMachine("my-machine-2", ""),
+		pending,
+	}
+
+	if indexed := indexAWSMachineInstanceID(initObjects[1]); !reflect.DeepEqual(indexed, []string{"i-1"}) {
+		t.Fatalf("Expected my-machine-1 to be indexed by i-1 but found %v", indexed)
+	}
+	if indexed := indexAWSMachineInstanceID(initObjects[2]); len(indexed) != 0 {
+		t.Fatalf("Expected my-machine-2 not to be indexed but found %v", indexed)
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &AWSMachineReconciler{
+		Client:   fake.NewFakeClientWithScheme(scheme, initObjects...),
+		Log:      klogr.New(),
+		Recorder: recorder,
+	}
+
+	requests := reconciler.requestsForInstanceEvent(instancestate.InstanceEvent{
+		Type:       instancestate.InstanceStateChangeEvent,
+		InstanceID: "i-1",
+		State:      "stopped",
+	})
+	if len(requests) != 1 || requests[0].Name != "my-machine-1" {
+		t.Fatalf("Expected a request for my-machine-1 but found %v", requests)
+	}
+	if len(recorder.Events) != 0 {
+		t.Fatalf("Expected no events but found %d", len(recorder.Events))
+	}
+
+	requests = reconciler.requestsForInstanceEvent(instancestate.InstanceEvent{
+		Type:       instancestate.SpotInstanceInterruptionEvent,
+		InstanceID: "i-0",
+		Action:     "terminate",
+	})
+	if len(requests) != 1 || requests[0].Name != "my-machine-0" {
+		t.Fatalf("Expected a request for my-machine-0 but found %v", requests)
+	}
+	if len(recorder.Events) != 1 {
+		t.Fatalf("Expected a spot interruption event but found %d events", len(recorder.Events))
+	}
+	interrupted := &infrav1.AWSMachine{}
+	if err := reconciler.Client.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "my-machine-0"}, interrupted); err != nil {
+		t.Fatal(err)
+	}
+	if len(interrupted.Status.ScheduledEvents) != 1 || interrupted.Status.ScheduledEvents[0].Code != infrav1.SpotInstanceInterruptionEventCode {
+		t.Fatalf("Expected the spot interruption notice to be recorded but found %v", interrupted.Status.ScheduledEvents)
+	}
+
+	requests = reconciler.requestsForInstanceEvent(instancestate.InstanceEvent{
+		Type:       instancestate.InstanceStateChangeEvent,
+		InstanceID: "i-2",
+	})
+	if len(requests) != 0 {
+		t.Fatalf("Expected no requests but found %v", requests)
+	}
+
+	requests = reconciler.requestsForInstanceEvent(instancestate.InstanceEvent{
+		Type:        instancestate.InstanceStateChangeEvent,
+		InstanceID:  "i-3",
+		State:       "running",
+		ClusterName: "my-cluster",
+		Name:        "my-machine-3",
+	})
+	if len(requests) != 1 || requests[0].Name != "my-machine-3" {
+		t.Fatalf("Expected a request for my-machine-3 but found %v", requests)
+	}
+
+	requests = reconciler.requestsForInstanceEvent(instancestate.InstanceEvent{
+		Type:        instancestate.InstanceStateChangeEvent,
+		InstanceID:  "i-4",
+		ClusterName: "other-cluster",
+		Name:        "my-machine-3",
+	})
+	if len(requests) != 0 {
+		t.Fatalf("Expected no requests but found %v", requests)
+	}
+}
+
+func TestInstanceDrift(t *testing.T) {
+	testCases := []struct {
+		name           string
+		spec           infrav1.AWSMachineSpec
+		instance       *infrav1.Instance
+		securityGroups []string
+		existing       map[string][]string
+		expected       []string
+	}{
+		{
+			name: "instance matches the spec",
+			spec: infrav1.AWSMachineSpec{
+				InstanceType: "t3.large",
+				AMI:          infrav1.AWSResourceReference{ID: aws.String("ami-1")},
+				Subnet:       &infrav1.AWSResourceReference{ID: aws.String("subnet-1")},
+				SSHKeyName:   aws.String("default"),
+			},
+			instance: &infrav1.Instance{
+				Type:       "t3.large",
+				ImageID:    "ami-1",
+				SubnetID:   "subnet-1",
+				SSHKeyName: aws.String("default"),
+			},
+			securityGroups: []string{"sg-2", "sg-1"},
+			existing:       map[string][]string{"eni-1": {"sg-1", "sg-2"}},
+		},
+		{
+			name: "fields not set in the spec are ignored",
+			spec: infrav1.AWSMachineSpec{},
+			instance: &infrav1.Instance{
+				Type:     "t3.large",
+				ImageID:  "ami-1",
+				SubnetID: "subnet-1",
+			},
+		},
+		{
+			name: "instance runs a fallback instance type",
+			spec: infrav1.AWSMachineSpec{
+				InstanceType:          "t3.large",
+				FallbackInstanceTypes: []string{"m5.large"},
+			},
+			instance: &infrav1.Instance{Type: "m5.large"},
+		},
+		{
+			name: "instance differs from the spec",
+			spec: infrav1.AWSMachineSpec{
+				InstanceType: "t3.large",
+				AMI:          infrav1.AWSResourceReference{ID: aws.String("ami-1")},
+				SSHKeyName:   aws.String(""),
+			},
+			instance: &infrav1.Instance{
+				Type:       "t3.medium",
+				ImageID:    "ami-2",
+				SSHKeyName: aws.String("default"),
+			},
+			securityGroups: []string{"sg-1"},
+			existing:       map[string][]string{"eni-1": {"sg-1", "sg-3"}},
+			expected: []string{
+				`instanceType: expected "t3.large", found "t3.medium"`,
+				`ami.id: expected "ami-1", found "ami-2"`,
+				`sshKeyName: expected "", found "default"`,
+				`securityGroups[eni-1]: expected "sg-1", found "sg-1,sg-3"`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			diff := instanceDrift(tc.spec, tc.instance, tc.securityGroups, tc.existing)
+			if !reflect.DeepEqual(diff, tc.expected) {
+				t.Fatalf("Expected drift %v but found %v", tc.expected, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+)
+
+var (
+	// awsMachineDrifted reports whether the EC2 instance of an AWSMachine has drifted from its spec.
+	awsMachineDrifted = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "capa_awsmachine_drifted",
+		Help: "Whether the EC2 instance of an AWSMachine differs from its spec (1) or not (0).",
+	}, []string{"namespace", "name"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(awsMachineDrifted)
+}
+
+// reconcileDrift compares the instance of the machine with its spec and the security groups it
+// should have, and reports the differences in the Drifted condition of the machine.
+// The security groups are compared with the ones last applied by the controller rather than with
+// the spec, so that a change to additionalSecurityGroups is not reported before being applied.
+func (r *AWSMachineReconciler) reconcileDrift(machineScope *scope.MachineScope, instance *infrav1.Instance, core []string, existing map[string][]string) error {
+	applied, ok, err := r.lastAppliedSecurityGroups(machineScope)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// Without a record of the applied security groups, changes can't be told apart from drift.
+		existing = nil
+	}
+
+	diff := instanceDrift(machineScope.AWSMachine.Spec, instance, append(append([]string{}, core...), applied...), existing)
+
+	if r.DriftMetrics {
+		value := 0.0
+		if len(diff) > 0 {
+			value = 1
+		}
+		awsMachineDrifted.WithLabelValues(machineScope.Namespace(), machineScope.Name()).Set(value)
+	}
+
+	if len(diff) == 0 {
+		machineScope.SetConditionFalse(infrav1.Drifted, infrav1.InstanceInSyncReason, "EC2 instance %q matches the machine spec", instance.ID)
+		return nil
+	}
+
+	message := fmt.Sprintf("EC2 instance %q differs from the machine spec: %s", instance.ID, strings.Join(diff, "; "))
+	if condition := machineScope.AWSMachine.Status.GetCondition(infrav1.Drifted); condition == nil || condition.Message != message {
+		machineScope.Info("EC2 instance drifted from the machine spec", "instance-id", instance.ID, "diff", diff)
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "InstanceDrifted", message)
+	}
+	machineScope.AWSMachine.Status.SetCondition(infrav1.AWSMachineProviderCondition{
+		Type:    infrav1.Drifted,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrav1.InstanceDriftedReason,
+		Message: message,
+	})
+	return nil
+}
+
+// deleteDriftMetric removes the drift metric of the machine.
+func (r *AWSMachineReconciler) deleteDriftMetric(machineScope *scope.MachineScope) {
+	if r.DriftMetrics {
+		awsMachineDrifted.DeleteLabelValues(machineScope.Namespace(), machineScope.Name())
+	}
+}
+
+// instanceDrift returns the differences between the instance and the fields of the machine spec
+// that were set, one entry per field. The security groups of each network interface of the
+// instance, other than the managed ones, are compared with the given ones.
+func instanceDrift(spec infrav1.AWSMachineSpec, instance *infrav1.Instance, securityGroups []string, existing map[string][]string) []string {
+	var diff []string
+
+	if spec.InstanceType != "" && instance.Type != spec.InstanceType && !containsString(spec.FallbackInstanceTypes, instance.Type) {
+		diff = append(diff, fieldDrift("instanceType", spec.InstanceType, instance.Type))
+	}
+
+	if spec.AMI.ID != nil && aws.StringValue(spec.AMI.ID) != instance.ImageID {
+		diff = append(diff, fieldDrift("ami.id", aws.StringValue(spec.AMI.ID), instance.ImageID))
+	}
+
+	if spec.Subnet != nil && spec.Subnet.ID != nil && aws.StringValue(spec.Subnet.ID) != instance.SubnetID {
+		diff = append(diff, fieldDrift("subnet.id", aws.StringValue(spec.Subnet.ID), instance.SubnetID))
+	}
+
+	if spec.SSHKeyName != nil && aws.StringValue(spec.SSHKeyName) != aws.StringValue(instance.SSHKeyName) {
+		diff = append(diff, fieldDrift("sshKeyName", aws.StringValue(spec.SSHKeyName), aws.StringValue(instance.SSHKeyName)))
+	}
+
+	desired := sortedUnique(securityGroups)
+	interfaces := make([]string, 0, len(existing))
+	for eni := range existing {
+		interfaces = append(interfaces, eni)
+	}
+	sort.Strings(interfaces)
+	for _, eni := range interfaces {
+		actual := sortedUnique(existing[eni])
+		if strings.Join(actual, ",") != strings.Join(desired, ",") {
+			diff = append(diff, fieldDrift(fmt.Sprintf("securityGroups[%s]", eni), strings.Join(desired, ","), strings.Join(actual, ",")))
+		}
+	}
+
+	return diff
+}
+
+func fieldDrift(field, desired, actual string) string {
+	return fmt.Sprintf("%s: expected %q, found %q", field, desired, actual)
+}
+
+func sortedUnique(list []string) []string {
+	set := make(map[string]struct{}, len(list))
+	res := make([]string, 0, len(list))
+	for _, s := range list {
+		if _, ok := set[s]; ok {
+			continue
+		}
+		set[s] = struct{}{}
+		res = append(res, s)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/controllers/noderefutil"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate"
+)
+
+const (
+	// awsMachineInstanceIDField is the field index of AWSMachines on the ID of the EC2 instance
+	// their provider ID refers to.
+	awsMachineInstanceIDField = "spec.providerID.instanceID"
+)
+
+// indexAWSMachineInstanceID implements client.IndexerFunc for awsMachineInstanceIDField.
+func indexAWSMachineInstanceID(o runtime.Object) []string {
+	m, ok := o.(*infrav1.AWSMachine)
+	if !ok {
+		return nil
+	}
+	if id := awsMachineInstanceID(m); id != "" {
+		return []string{id}
+	}
+	return nil
+}
+
+// enqueueInstanceEvents implements source.Func to enqueue the AWSMachines of the EC2 instances
+// in the events received from the instance state watcher, instead of waiting for the next resync.
+func (r *AWSMachineReconciler) enqueueInstanceEvents(_ handler.EventHandler, queue workqueue.RateLimitingInterface, _ ...predicate.Predicate) error {
+	go func() {
+		for event := range r.InstanceEvents {
+			for _, req := range r.requestsForInstanceEvent(event) {
+				queue.Add(req)
+			}
+		}
+	}()
+	return nil
+}
+
+// requestsForInstanceEvent returns the requests to reconcile the AWSMachines whose provider ID
+// refers to the EC2 instance of the event. When there are none, e.g. because the instance was just
+// created, the AWSMachine is matched by the cluster and name tags of the instance instead.
+func (r *AWSMachineReconciler) requestsForInstanceEvent(event instancestate.InstanceEvent) []ctrl.Request {
+	ctx := context.TODO()
+	log := r.Log.WithValues("instance-id", event.InstanceID)
+
+	machineList := &infrav1.AWSMachineList{}
+	if err := r.Client.List(ctx, machineList, client.MatchingFields{awsMachineInstanceIDField: event.InstanceID}); err != nil {
+		log.Error(err, "failed to list AWSMachines")
+		return nil
+	}
+	machines := filterAWSMachines(machineList.Items, func(m *infrav1.AWSMachine) bool {
+		return awsMachineInstanceID(m) == event.InstanceID
+	})
+
+	if len(machines) == 0 && event.ClusterName != "" && event.Name != "" {
+		if err := r.Client.List(ctx, machineList, client.MatchingLabels{clusterv1.ClusterLabelName: event.ClusterName}); err != nil {
+			log.Error(err, "failed to list AWSMachines", "cluster", event.ClusterName)
+			return nil
+		}
+		machines = filterAWSMachines(machineList.Items, func(m *infrav1.AWSMachine) bool {
+			return m.Name == event.Name && m.Spec.ProviderID == nil
+		})
+	}
+
+	result := make([]ctrl.Request, 0, len(machines))
+	for _, m := range machines {
+		log.V(2).Info("Received EC2 instance event", "event", event.Type, "state", event.State, "AWSMachine", m.Name, "Namespace", m.Namespace)
+		if event.Type == instancestate.SpotInstanceInterruptionEvent {
+			if err := r.recordSpotInterruption(ctx, m, event); err != nil {
+				log.Error(err, "failed to record spot interruption notice", "AWSMachine", m.Name, "Namespace", m.Namespace)
+			}
+			r.Recorder.Eventf(m, corev1.EventTypeWarning, "SpotInstanceInterruption", "EC2 spot instance %q is about to be interrupted, instance action is %s", event.InstanceID, event.Action)
+		}
+
+		result = append(result, ctrl.Request{NamespacedName: client.ObjectKey{Namespace: m.Namespace, Name: m.Name}})
+	}
+	return result
+}
+
+// awsMachineInstanceID returns the ID of the EC2 instance the provider ID of the AWSMachine refers to,
+// or an empty string if it has none. It is indexed as awsMachineInstanceIDField.
+func awsMachineInstanceID(m *infrav1.AWSMachine) string {
+	if m.Spec.ProviderID == nil {
+		return ""
+	}
+	providerID, err := noderefutil.NewProviderID(*m.Spec.ProviderID)
+	if err != nil {
+		return ""
+	}
+	return providerID.ID()
+}
+
+func filterAWSMachines(machines []infrav1.AWSMachine, match func(*infrav1.AWSMachine) bool) []*infrav1.AWSMachine {
+	var res []*infrav1.AWSMachine
+	for i := range machines {
+		if match(&machines[i]) {
+			res = append(res, &machines[i])
+		}
+	}
+	return res
+}
+
+// recordSpotInterruption persists the spot interruption notice of the event in the scheduled events
+// of the AWSMachine, so that it isn't lost if the controller restarts before reconciling the machine.
+func (r *AWSMachineReconciler) recordSpotInterruption(ctx context.Context, m *infrav1.AWSMachine, event instancestate.InstanceEvent) error {
+	if hasScheduledEvent(m.Status.ScheduledEvents, infrav1.SpotInstanceInterruptionEventCode) {
+		return nil
+	}
+
+	helper, err := patch.NewHelper(m, r.Client)
+	if err != nil {
+		return errors.Wrapf(err, "failed to init patch helper for AWSMachine %s/%s", m.Namespace, m.Name)
+	}
+
+	m.Status.ScheduledEvents = append(m.Status.ScheduledEvents, infrav1.InstanceScheduledEvent{
+		Code:        infrav1.SpotInstanceInterruptionEventCode,
+		Description: fmt.Sprintf("Spot instance interruption, instance action is %s", event.Action),
+	})
+
+	if err := helper.Patch(ctx, m); err != nil {
+		return errors.Wrapf(err, "failed to patch status of AWSMachine %s/%s", m.Namespace, m.Name)
+	}
+	return nil
+}
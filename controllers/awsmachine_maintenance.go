@@ -0,0 +1,129 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/cluster-api/util/patch"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
+)
+
+const (
+	// instanceScheduledEventsRequeueAfter is how often the scheduled events of an instance are checked
+	// while there are any.
+	instanceScheduledEventsRequeueAfter = time.Minute
+
+	// DefaultInstanceMaintenanceAnnotation is the default annotation set on the Machine of an
+	// AWSMachine whose instance has scheduled events. Its value is the comma separated list of the
+	// codes of the events.
+	DefaultInstanceMaintenanceAnnotation = "sigs.k8s.io/cluster-api-provider-aws-instance-maintenance"
+)
+
+// reconcileScheduledEvents records the events AWS has scheduled for the instance of the machine, and
+// the spot interruption notice received for it if any, in the machine status and conditions. The owning
+// Machine is annotated while there are any, so that it can be remediated before the instance goes away.
+func (r *AWSMachineReconciler) reconcileScheduledEvents(ctx context.Context, machineScope *scope.MachineScope, ec2svc services.EC2MachineInterface, instance *infrav1.Instance) error {
+	events, err := ec2svc.GetInstanceScheduledEvents(instance.ID)
+	if err != nil {
+		return err
+	}
+
+	// Spot interruption notices are not scheduled events known to EC2, keep the recorded one.
+	interrupted := false
+	for _, event := range machineScope.AWSMachine.Status.ScheduledEvents {
+		if event.Code == infrav1.SpotInstanceInterruptionEventCode {
+			interrupted = true
+			events = append(events, event)
+			break
+		}
+	}
+
+	codes := make([]string, 0, len(events))
+	for _, event := range events {
+		codes = append(codes, event.Code)
+
+		// Spot interruption notices are recorded when they are received.
+		if event.Code == infrav1.SpotInstanceInterruptionEventCode || hasScheduledEvent(machineScope.AWSMachine.Status.ScheduledEvents, event.Code) {
+			continue
+		}
+		machineScope.Info("EC2 instance event scheduled", "instance-id", instance.ID, "code", event.Code, "description", event.Description)
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "InstanceEventScheduled", "AWS scheduled %s for instance %q: %s", event.Code, instance.ID, event.Description)
+	}
+	machineScope.SetScheduledEvents(events)
+
+	switch {
+	case interrupted:
+		machineScope.SetConditionFalse(infrav1.InstanceAvailable, infrav1.SpotInstanceInterruptionReason, "EC2 spot instance %q is about to be interrupted", instance.ID)
+	case len(events) > 0:
+		machineScope.SetConditionFalse(infrav1.InstanceAvailable, infrav1.InstanceEventScheduledReason, "AWS scheduled %s for EC2 instance %q", strings.Join(codes, ", "), instance.ID)
+	default:
+		machineScope.SetConditionTrue(infrav1.InstanceAvailable)
+	}
+
+	return r.updateMaintenanceAnnotation(ctx, machineScope, strings.Join(codes, ","))
+}
+
+// updateMaintenanceAnnotation sets the maintenance annotation of the Machine to the given value,
+// or removes it if the value is empty.
+func (r *AWSMachineReconciler) updateMaintenanceAnnotation(ctx context.Context, machineScope *scope.MachineScope, value string) error {
+	key := r.MaintenanceAnnotation
+	if key == "" {
+		key = DefaultInstanceMaintenanceAnnotation
+	}
+
+	machine := machineScope.Machine
+	current, ok := machine.Annotations[key]
+	if (value == "" && !ok) || (value != "" && current == value) {
+		return nil
+	}
+
+	helper, err := patch.NewHelper(machine, r.Client)
+	if err != nil {
+		return errors.Wrapf(err, "failed to init patch helper for Machine %s/%s", machine.Namespace, machine.Name)
+	}
+
+	if value == "" {
+		delete(machine.Annotations, key)
+	} else {
+		if machine.Annotations == nil {
+			machine.Annotations = map[string]string{}
+		}
+		machine.Annotations[key] = value
+	}
+
+	if err := helper.Patch(ctx, machine); err != nil {
+		return errors.Wrapf(err, "failed to patch annotation %q of Machine %s/%s", key, machine.Namespace, machine.Name)
+	}
+	return nil
+}
+
+func hasScheduledEvent(events []infrav1.InstanceScheduledEvent, code string) bool {
+	for _, event := range events {
+		if event.Code == code {
+			return true
+		}
+	}
+	return false
+}
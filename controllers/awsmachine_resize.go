@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
+)
+
+const (
+	// instanceResizeRequeueAfter is how long to wait before checking again on an instance being resized.
+	instanceResizeRequeueAfter = 15 * time.Second
+)
+
+// reconcileInPlaceResize applies changes to the instance type and root volume size of the machine
+// by stopping the instance, modifying it and starting it again. The progress of the resize is tracked
+// in the machine status, so that it is resumed if the controller restarts.
+// Returns true while the resize is in progress.
+func (r *AWSMachineReconciler) reconcileInPlaceResize(machineScope *scope.MachineScope, ec2svc services.EC2MachineInterface, instance *infrav1.Instance) (bool, error) {
+	resize := machineScope.AWSMachine.Status.Resize
+	if resize == nil {
+		if !machineScope.AWSMachine.Spec.InPlaceResize || instance.State != infrav1.InstanceStateRunning {
+			return false, nil
+		}
+
+		var err error
+		resize, err = r.desiredResize(machineScope, ec2svc, instance)
+		if err != nil || resize == nil {
+			return false, err
+		}
+
+		machineScope.Info("Resizing EC2 instance", "instance-id", instance.ID, "instance-type", resize.InstanceType, "root-volume-size", resize.RootVolumeSize)
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "ResizeStarted", "Resizing instance %q", instance.ID)
+		machineScope.SetResize(resize)
+	}
+
+	if resize.Phase == infrav1.InstanceResizePhaseStopping {
+		switch instance.State {
+		case infrav1.InstanceStatePending, infrav1.InstanceStateRunning:
+			if err := ec2svc.StopInstance(instance.ID); err != nil {
+				r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedResize", "Failed to stop instance %q: %v", instance.ID, err)
+				return true, errors.Wrap(err, "failed to stop instance for resize")
+			}
+			return true, nil
+		case infrav1.InstanceStateStopped:
+			resize.Phase = infrav1.InstanceResizePhaseModifying
+		default:
+			return true, nil
+		}
+	}
+
+	if resize.Phase == infrav1.InstanceResizePhaseModifying {
+		if resize.InstanceType != "" && instance.Type != resize.InstanceType {
+			if err := ec2svc.ModifyInstanceType(instance.ID, resize.InstanceType); err != nil {
+				if !awserrors.IsInvalidRequest(errors.Cause(err)) {
+					r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedResize", "Failed to change type of instance %q to %q: %v", instance.ID, resize.InstanceType, err)
+					return true, errors.Wrap(err, "failed to modify instance type")
+				}
+
+				// The instance keeps its original type, start it again rather than leaving it stopped.
+				machineScope.Info("Instance type rejected, starting EC2 instance with its original type", "instance-id", instance.ID, "instance-type", resize.InstanceType)
+				r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedResize", "Failed to change type of instance %q to %q, keeping %q: %v", instance.ID, resize.InstanceType, instance.Type, err)
+				machineScope.SetConditionFalse(infrav1.InstanceResized, infrav1.InstanceResizeFailedReason, "Failed to change type of EC2 instance %q to %q: %v", instance.ID, resize.InstanceType, err)
+				machineScope.AWSMachine.Status.FailedResizeInstanceType = resize.InstanceType
+				resize.InstanceType = ""
+			}
+		}
+		if resize.RootVolumeSize > 0 {
+			if err := ec2svc.ModifyRootVolumeSize(instance.ID, resize.RootVolumeSize); err != nil {
+				r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedResize", "Failed to resize root volume of instance %q: %v", instance.ID, err)
+				return true, errors.Wrap(err, "failed to modify root volume size")
+			}
+		}
+		resize.Phase = infrav1.InstanceResizePhaseStarting
+	}
+
+	switch instance.State {
+	case infrav1.InstanceStateStopped:
+		if err := ec2svc.StartInstance(instance.ID); err != nil {
+			r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedResize", "Failed to start instance %q: %v", instance.ID, err)
+			return true, errors.Wrap(err, "failed to start instance after resize")
+		}
+		return true, nil
+	case infrav1.InstanceStateRunning:
+		// Nothing was resized when AWS rejected the instance type and the root volume was unchanged.
+		if resize.InstanceType != "" || resize.RootVolumeSize > 0 {
+			machineScope.Info("EC2 instance successfully resized", "instance-id", instance.ID)
+			r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "SuccessfulResize", "Resized instance %q", instance.ID)
+		}
+		if machineScope.AWSMachine.Status.FailedResizeInstanceType == "" {
+			machineScope.SetConditionTrue(infrav1.InstanceResized)
+		}
+		machineScope.SetResize(nil)
+		return false, nil
+	default:
+		return true, nil
+	}
+}
+
+// desiredResize returns the resize needed for the instance to match the machine spec, or nil if it already does.
+func (r *AWSMachineReconciler) desiredResize(machineScope *scope.MachineScope, ec2svc services.EC2MachineInterface, instance *infrav1.Instance) (*infrav1.InstanceResize, error) {
+	spec := machineScope.AWSMachine.Spec
+	resize := &infrav1.InstanceResize{
+		Phase: infrav1.InstanceResizePhaseStopping,
+	}
+
+	// An instance type rejected by AWS is only tried again once the spec asks for another one.
+	status := &machineScope.AWSMachine.Status
+	if status.FailedResizeInstanceType != "" && status.FailedResizeInstanceType != spec.InstanceType {
+		status.FailedResizeInstanceType = ""
+	}
+
+	if spec.InstanceType != "" && instance.Type != spec.InstanceType && !containsString(spec.FallbackInstanceTypes, instance.Type) &&
+		spec.InstanceType != status.FailedResizeInstanceType {
+		resize.InstanceType = spec.InstanceType
+	}
+
+	if spec.RootVolume != nil && spec.RootVolume.Size > 0 {
+		size, err := ec2svc.GetRootVolumeSize(instance.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get root volume size")
+		}
+		if size < spec.RootVolume.Size {
+			resize.RootVolumeSize = spec.RootVolume.Size
+		}
+	}
+
+	if resize.InstanceType == "" && resize.RootVolumeSize == 0 {
+		return nil, nil
+	}
+	return resize, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -19,7 +19,6 @@ package controllers
 import (
 	"sort"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	service "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
 )
@@ -38,18 +37,19 @@ const (
 // Returns bool, error
 // Bool indicates if changes were made or not, allowing the caller to decide
 // if the machine should be updated.
-func (r *AWSMachineReconciler) ensureSecurityGroups(ec2svc service.EC2MachineInterface, scope *scope.MachineScope, additional []infrav1.AWSResourceReference, existing map[string][]string) (bool, error) {
+func (r *AWSMachineReconciler) ensureSecurityGroups(ec2svc service.EC2MachineInterface, scope *scope.MachineScope, core []string, additional []string, existing map[string][]string) (bool, error) {
 	annotation, err := r.machineAnnotationJSON(scope.AWSMachine, SecurityGroupsLastAppliedAnnotation)
 	if err != nil {
 		return false, err
 	}
 
-	core, err := ec2svc.GetCoreSecurityGroups(scope)
-	if err != nil {
-		return false, err
-	}
 	changed, ids := r.securityGroupsChanged(annotation, core, additional, existing)
 	if !changed {
+		// Record the groups of instances that were launched with them, so that later changes
+		// can be told apart from drift.
+		if r.machineAnnotation(scope.AWSMachine, SecurityGroupsLastAppliedAnnotation) == "" {
+			return false, r.updateLastAppliedSecurityGroups(scope, additional)
+		}
 		return false, nil
 	}
 
@@ -57,24 +57,48 @@ func (r *AWSMachineReconciler) ensureSecurityGroups(ec2svc service.EC2MachineInt
 		return false, err
 	}
 
-	// Build and store annotation.
+	if err := r.updateLastAppliedSecurityGroups(scope, additional); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// updateLastAppliedSecurityGroups stores the additional security groups applied to the instance
+// in the SecurityGroupsLastAppliedAnnotation.
+func (r *AWSMachineReconciler) updateLastAppliedSecurityGroups(scope *scope.MachineScope, additional []string) error {
 	newAnnotation := make(map[string]interface{}, len(additional))
 	for _, id := range additional {
-		newAnnotation[*id.ID] = struct{}{}
+		newAnnotation[id] = struct{}{}
 	}
 
-	if err := r.updateMachineAnnotationJSON(scope.AWSMachine, SecurityGroupsLastAppliedAnnotation, newAnnotation); err != nil {
-		return false, err
+	return r.updateMachineAnnotationJSON(scope.AWSMachine, SecurityGroupsLastAppliedAnnotation, newAnnotation)
+}
+
+// lastAppliedSecurityGroups returns the additional security groups last applied to the instance,
+// and false if none were recorded yet.
+func (r *AWSMachineReconciler) lastAppliedSecurityGroups(scope *scope.MachineScope) ([]string, bool, error) {
+	if r.machineAnnotation(scope.AWSMachine, SecurityGroupsLastAppliedAnnotation) == "" {
+		return nil, false, nil
 	}
 
-	return true, nil
+	annotation, err := r.machineAnnotationJSON(scope.AWSMachine, SecurityGroupsLastAppliedAnnotation)
+	if err != nil {
+		return nil, false, err
+	}
+
+	ids := make([]string, 0, len(annotation))
+	for id := range annotation {
+		ids = append(ids, id)
+	}
+	return ids, true, nil
 }
 
 // securityGroupsChanged determines which security groups to delete and which to add.
-func (r *AWSMachineReconciler) securityGroupsChanged(annotation map[string]interface{}, core []string, additional []infrav1.AWSResourceReference, existing map[string][]string) (bool, []string) {
+func (r *AWSMachineReconciler) securityGroupsChanged(annotation map[string]interface{}, core []string, additional []string, existing map[string][]string) (bool, []string) {
 	state := map[string]bool{}
-	for _, s := range additional {
-		state[*s.ID] = true
+	for _, id := range additional {
+		state[id] = true
 	}
 
 	// Loop over `annotation`, checking the state for things that were deleted since last time.
@@ -0,0 +1,456 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/cluster-api/util"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/autoscaling"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha3"
+)
+
+const (
+	// asgDeletionRequeueAfter is how long to wait before checking again on an Auto Scaling Group being deleted.
+	asgDeletionRequeueAfter = 30 * time.Second
+
+	// asgInServiceRequeueAfter is how long to wait before checking again on the instances of an Auto Scaling Group
+	// that has fewer instances in service than desired.
+	asgInServiceRequeueAfter = 30 * time.Second
+)
+
+// AWSMachinePoolReconciler reconciles a AWSMachinePool object
+type AWSMachinePoolReconciler struct {
+	client.Client
+	Log               logr.Logger
+	Recorder          record.EventRecorder
+	asgServiceFactory func(*scope.ClusterScope) services.ASGInterface
+	ec2ServiceFactory func(*scope.ClusterScope) services.EC2MachineInterface
+}
+
+func (r *AWSMachinePoolReconciler) getASGService(scope *scope.ClusterScope) services.ASGInterface {
+	if r.asgServiceFactory != nil {
+		return r.asgServiceFactory(scope)
+	}
+
+	return autoscaling.NewService(scope)
+}
+
+func (r *AWSMachinePoolReconciler) getEC2Service(scope *scope.ClusterScope) services.EC2MachineInterface {
+	if r.ec2ServiceFactory != nil {
+		return r.ec2ServiceFactory(scope)
+	}
+
+	return ec2.NewService(scope)
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachinepools,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachinepools/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=exp.cluster.x-k8s.io,resources=machinepools;machinepools/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
+
+func (r *AWSMachinePoolReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx := context.TODO()
+	logger := r.Log.WithValues("namespace", req.Namespace, "awsMachinePool", req.Name)
+
+	// Fetch the AWSMachinePool instance.
+	awsMachinePool := &infrav1.AWSMachinePool{}
+	err := r.Get(ctx, req.NamespacedName, awsMachinePool)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Fetch the MachinePool.
+	machinePool, err := getOwnerMachinePool(ctx, r.Client, awsMachinePool.ObjectMeta)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if machinePool == nil {
+		logger.Info("MachinePool Controller has not yet set OwnerRef")
+		return ctrl.Result{}, nil
+	}
+
+	logger = logger.WithValues("machinePool", machinePool.Name)
+
+	// Fetch the Cluster.
+	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, machinePool.ObjectMeta)
+	if err != nil {
+		logger.Info("MachinePool is missing cluster label or cluster does not exist")
+		return ctrl.Result{}, nil
+	}
+
+	if isPaused(cluster, awsMachinePool) {
+		logger.Info("AWSMachinePool or linked Cluster is marked as paused. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
+	logger = logger.WithValues("cluster", cluster.Name)
+
+	awsCluster := &infrav1.AWSCluster{}
+
+	awsClusterName := client.ObjectKey{
+		Namespace: awsMachinePool.Namespace,
+		Name:      cluster.Spec.InfrastructureRef.Name,
+	}
+	if err := r.Client.Get(ctx, awsClusterName, awsCluster); err != nil {
+		logger.Info("AWSCluster is not available yet")
+		return ctrl.Result{}, nil
+	}
+
+	logger = logger.WithValues("awsCluster", awsCluster.Name)
+
+	// Create the cluster scope
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Client:     r.Client,
+		Logger:     logger,
+		Cluster:    cluster,
+		AWSCluster: awsCluster,
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Create the machine pool scope
+	machinePoolScope, err := scope.NewMachinePoolScope(scope.MachinePoolScopeParams{
+		Logger:         logger,
+		Client:         r.Client,
+		Cluster:        cluster,
+		MachinePool:    machinePool,
+		AWSCluster:     awsCluster,
+		AWSMachinePool: awsMachinePool,
+	})
+	if err != nil {
+		return ctrl.Result{}, errors.Errorf("failed to create scope: %+v", err)
+	}
+
+	// Always close the scope when exiting this function so we can persist any AWSMachinePool changes.
+	defer func() {
+		if err := machinePoolScope.Close(); err != nil && reterr == nil {
+			reterr = err
+		}
+	}()
+
+	// Handle deleted machine pools
+	if !awsMachinePool.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(machinePoolScope, clusterScope)
+	}
+
+	// Handle non-deleted machine pools
+	return r.reconcileNormal(ctx, machinePoolScope, clusterScope)
+}
+
+func (r *AWSMachinePoolReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&infrav1.AWSMachinePool{}).
+		Watches(
+			&source.Kind{Type: &expv1.MachinePool{}},
+			&handler.EnqueueRequestsFromMapFunc{
+				ToRequests: machinePoolToInfrastructureMapFunc(infrav1.GroupVersion.WithKind("AWSMachinePool")),
+			},
+		).
+		WithEventFilter(pausePredicates).
+		Complete(r)
+}
+
+func (r *AWSMachinePoolReconciler) reconcileNormal(_ context.Context, machinePoolScope *scope.MachinePoolScope, clusterScope *scope.ClusterScope) (ctrl.Result, error) {
+	machinePoolScope.Info("Reconciling AWSMachinePool")
+
+	// If the AWSMachinePool is in an error state, return early.
+	if machinePoolScope.HasFailed() {
+		machinePoolScope.Info("Error state detected, skipping reconciliation")
+		return ctrl.Result{}, nil
+	}
+
+	// If the AWSMachinePool doesn't have our finalizer, add it.
+	controllerutil.AddFinalizer(machinePoolScope.AWSMachinePool, infrav1.MachinePoolFinalizer)
+	// Register the finalizer immediately to avoid orphaning AWS resources on delete
+	if err := machinePoolScope.PatchObject(); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !machinePoolScope.Cluster.Status.InfrastructureReady {
+		machinePoolScope.Info("Cluster infrastructure is not ready yet")
+		return ctrl.Result{}, nil
+	}
+
+	// Make sure bootstrap data is available and populated.
+	if machinePoolScope.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName == nil {
+		machinePoolScope.Info("Bootstrap data secret reference is not yet available")
+		return ctrl.Result{}, nil
+	}
+
+	ec2svc := r.getEC2Service(clusterScope)
+	asgsvc := r.getASGService(clusterScope)
+
+	if err := r.reconcileLaunchTemplate(machinePoolScope, ec2svc); err != nil {
+		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedLaunchTemplateReconcile", "Failed to reconcile launch template: %v", err)
+		return ctrl.Result{}, errors.Wrap(err, "failed to reconcile launch template")
+	}
+
+	// Find existing ASG.
+	asg, err := asgsvc.GetASGByName(machinePoolScope)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if asg == nil {
+		// Create new ASG.
+		asg, err = asgsvc.CreateASG(machinePoolScope)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to create Auto Scaling Group")
+		}
+	} else {
+		if err := asgsvc.UpdateASG(machinePoolScope); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to update Auto Scaling Group")
+		}
+	}
+
+	// Make sure Spec.ProviderID is always set.
+	machinePoolScope.SetProviderID(asg.ID)
+
+	providerIDList := make([]string, 0, len(asg.Instances))
+	for _, instance := range asg.Instances {
+		providerIDList = append(providerIDList, instance.ProviderID)
+	}
+
+	machinePoolScope.SetProviderIDList(providerIDList)
+	machinePoolScope.SetInstances(asg.Instances)
+	machinePoolScope.SetReplicas(int32(len(asg.Instances)))
+
+	// The machine pool is only ready once the desired number of instances is in service, the readiness
+	// of each of them is reported in Status.Instances.
+	desired, inService := asgCapacity(machinePoolScope, asg)
+	if inService < desired {
+		machinePoolScope.Info("Waiting for Auto Scaling Group instances to be in service", "desired", desired, "in-service", inService)
+		machinePoolScope.SetNotReady()
+		machinePoolScope.SetConditionFalse(infrav1.ASGReady, infrav1.InstancesNotInServiceReason,
+			"%d of %d instances of Auto Scaling Group %q are in service", inService, desired, asg.Name)
+		return ctrl.Result{RequeueAfter: asgInServiceRequeueAfter}, nil
+	}
+
+	machinePoolScope.SetReady()
+	machinePoolScope.SetConditionTrue(infrav1.ASGReady)
+
+	return ctrl.Result{}, nil
+}
+
+// asgCapacity returns the desired number of instances of the machine pool, and the number of instances
+// of the Auto Scaling Group that are in service and healthy.
+func asgCapacity(machinePoolScope *scope.MachinePoolScope, asg *infrav1.AutoScalingGroup) (desired int32, inService int32) {
+	switch {
+	case machinePoolScope.DesiredCapacity() != nil:
+		desired = *machinePoolScope.DesiredCapacity()
+	case asg.DesiredCapacity != nil:
+		desired = *asg.DesiredCapacity
+	default:
+		desired = asg.MinSize
+	}
+
+	for _, instance := range asg.Instances {
+		if instance.Ready {
+			inService++
+		}
+	}
+
+	return desired, inService
+}
+
+// reconcileLaunchTemplate makes sure the launch template backing the machine pool exists,
+// and creates a new version of it whenever the desired configuration changes.
+func (r *AWSMachinePoolReconciler) reconcileLaunchTemplate(machinePoolScope *scope.MachinePoolScope, ec2svc services.EC2MachineInterface) error {
+	userData, err := machinePoolScope.GetRawBootstrapData()
+	if err != nil {
+		return err
+	}
+
+	imageID, err := ec2svc.DiscoverLaunchTemplateAMI(machinePoolScope)
+	if err != nil {
+		return err
+	}
+
+	launchTemplate, launchTemplateUserData, err := ec2svc.GetLaunchTemplate(machinePoolScope.ResourceName())
+	if err != nil {
+		return err
+	}
+
+	if launchTemplate == nil {
+		machinePoolScope.Info("No launch template found, creating one")
+		launchTemplateID, err := ec2svc.CreateLaunchTemplate(machinePoolScope, imageID, userData)
+		if err != nil {
+			return err
+		}
+
+		machinePoolScope.SetLaunchTemplateID(launchTemplateID)
+		return nil
+	}
+
+	// Make sure Status.LaunchTemplateID is always set, so that the launch template can be deleted
+	// along with the machine pool.
+	if machinePoolScope.AWSMachinePool.Status.LaunchTemplateID == "" {
+		launchTemplateID, err := ec2svc.GetLaunchTemplateID(machinePoolScope.ResourceName())
+		if err != nil {
+			return err
+		}
+		machinePoolScope.SetLaunchTemplateID(launchTemplateID)
+	}
+
+	needsUpdate, err := ec2svc.LaunchTemplateNeedsUpdate(machinePoolScope, launchTemplate, launchTemplateUserData, imageID, userData)
+	if err != nil {
+		return err
+	}
+
+	if needsUpdate {
+		machinePoolScope.Info("Launch template changed, creating a new version")
+		if err := ec2svc.CreateLaunchTemplateVersion(machinePoolScope, imageID, userData); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *AWSMachinePoolReconciler) reconcileDelete(machinePoolScope *scope.MachinePoolScope, clusterScope *scope.ClusterScope) (ctrl.Result, error) {
+	machinePoolScope.Info("Handling deleted AWSMachinePool")
+
+	ec2svc := r.getEC2Service(clusterScope)
+	asgsvc := r.getASGService(clusterScope)
+
+	asg, err := asgsvc.GetASGByName(machinePoolScope)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if asg != nil {
+		machinePoolScope.V(3).Info("Auto Scaling Group found matching deleted AWSMachinePool", "name", asg.Name)
+
+		// Deleting an Auto Scaling Group terminates all of its instances first, which can take a while.
+		// Requeue until the group is gone, so that the launch template is only deleted once it is no longer in use.
+		if asg.Status == "" {
+			machinePoolScope.Info("Deleting Auto Scaling Group", "name", asg.Name)
+			if err := asgsvc.DeleteASG(asg.Name); err != nil {
+				r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedDelete", "Failed to delete Auto Scaling Group %q: %v", asg.Name, err)
+				return ctrl.Result{}, errors.Wrap(err, "failed to delete Auto Scaling Group")
+			}
+			r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "SuccessfulDelete", "Deleting Auto Scaling Group %q", asg.Name)
+		} else {
+			machinePoolScope.Info("Auto Scaling Group is being deleted", "name", asg.Name, "status", asg.Status)
+		}
+
+		return ctrl.Result{RequeueAfter: asgDeletionRequeueAfter}, nil
+	}
+
+	launchTemplateID := machinePoolScope.AWSMachinePool.Status.LaunchTemplateID
+	if launchTemplateID == "" {
+		launchTemplateID, err = ec2svc.GetLaunchTemplateID(machinePoolScope.ResourceName())
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if launchTemplateID != "" {
+		machinePoolScope.Info("Deleting launch template", "id", launchTemplateID)
+		if err := ec2svc.DeleteLaunchTemplate(launchTemplateID); err != nil {
+			r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedDelete", "Failed to delete launch template %q: %v", launchTemplateID, err)
+			return ctrl.Result{}, errors.Wrap(err, "failed to delete launch template")
+		}
+		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "SuccessfulDelete", "Deleted launch template %q", launchTemplateID)
+	}
+
+	// Auto Scaling Group and launch template are deleted so remove the finalizer.
+	controllerutil.RemoveFinalizer(machinePoolScope.AWSMachinePool, infrav1.MachinePoolFinalizer)
+
+	return ctrl.Result{}, nil
+}
+
+// getOwnerMachinePool returns the MachinePool object owning the current resource.
+func getOwnerMachinePool(ctx context.Context, c client.Client, obj metav1.ObjectMeta) (*expv1.MachinePool, error) {
+	for _, ref := range obj.OwnerReferences {
+		if ref.Kind != "MachinePool" {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if gv.Group == expv1.GroupVersion.Group {
+			return getMachinePoolByName(ctx, c, obj.Namespace, ref.Name)
+		}
+	}
+	return nil, nil
+}
+
+// getMachinePoolByName finds and return a MachinePool object using the specified params.
+func getMachinePoolByName(ctx context.Context, c client.Client, namespace, name string) (*expv1.MachinePool, error) {
+	m := &expv1.MachinePool{}
+	key := client.ObjectKey{Name: name, Namespace: namespace}
+	if err := c.Get(ctx, key, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// machinePoolToInfrastructureMapFunc returns a handler.ToRequestsFunc that watches for
+// MachinePool events and returns reconciliation requests for an infrastructure provider object.
+func machinePoolToInfrastructureMapFunc(gvk schema.GroupVersionKind) handler.ToRequestsFunc {
+	return func(o handler.MapObject) []ctrl.Request {
+		m, ok := o.Object.(*expv1.MachinePool)
+		if !ok {
+			return nil
+		}
+
+		gk := gvk.GroupKind()
+		ref := m.Spec.Template.Spec.InfrastructureRef
+		// Return early if the GroupKind doesn't match what we expect.
+		infraGK := ref.GroupVersionKind().GroupKind()
+		if gk != infraGK {
+			return nil
+		}
+
+		return []ctrl.Request{
+			{
+				NamespacedName: client.ObjectKey{
+					Namespace: m.Namespace,
+					Name:      ref.Name,
+				},
+			},
+		}
+	}
+}
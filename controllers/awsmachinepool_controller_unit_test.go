@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+func newMachinePool(clusterName, poolName string) *expv1.MachinePool {
+	return &expv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      poolName,
+			Namespace: "default",
+		},
+		Spec: expv1.MachinePoolSpec{
+			ClusterName: clusterName,
+		},
+	}
+}
+
+func TestMachinePoolToInfrastructureMapFunc(t *testing.T) {
+	mapFunc := machinePoolToInfrastructureMapFunc(infrav1.GroupVersion.WithKind("AWSMachinePool"))
+
+	pool := newMachinePool("my-cluster", "my-pool")
+	pool.Spec.Template.Spec.InfrastructureRef = v1.ObjectReference{
+		Kind:       "AWSMachinePool",
+		Name:       "aws-my-pool",
+		APIVersion: infrav1.GroupVersion.String(),
+	}
+
+	requests := mapFunc(handler.MapObject{Object: pool})
+	if len(requests) != 1 {
+		t.Fatalf("Expected 1 but found %d requests", len(requests))
+	}
+	if requests[0].Name != "aws-my-pool" || requests[0].Namespace != "default" {
+		t.Fatalf("Expected request for default/aws-my-pool but got %v", requests[0].NamespacedName)
+	}
+
+	pool.Spec.Template.Spec.InfrastructureRef.Kind = "AWSMachine"
+	if requests := mapFunc(handler.MapObject{Object: pool}); len(requests) != 0 {
+		t.Fatalf("Expected no requests for a different infrastructure kind but found %d", len(requests))
+	}
+}
+
+func TestGetOwnerMachinePool(t *testing.T) {
+	scheme, err := setupScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := expv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	client := fake.NewFakeClientWithScheme(scheme, []runtime.Object{newMachinePool("my-cluster", "my-pool")}...)
+
+	pool, err := getOwnerMachinePool(context.TODO(), client, metav1.ObjectMeta{
+		Namespace: "default",
+		OwnerReferences: []metav1.OwnerReference{
+			{
+				Name:       "my-pool",
+				Kind:       "MachinePool",
+				APIVersion: expv1.GroupVersion.String(),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("did not expect error: %v", err)
+	}
+	if pool == nil || pool.Name != "my-pool" {
+		t.Fatalf("Expected to find MachinePool my-pool but got %v", pool)
+	}
+
+	pool, err = getOwnerMachinePool(context.TODO(), client, metav1.ObjectMeta{Namespace: "default"})
+	if err != nil {
+		t.Fatalf("did not expect error: %v", err)
+	}
+	if pool != nil {
+		t.Fatalf("Did not expect a MachinePool without owner reference but got %v", pool)
+	}
+}
+
+func TestASGCapacity(t *testing.T) {
+	testCases := []struct {
+		name              string
+		replicas          *int32
+		asg               *infrav1.AutoScalingGroup
+		expectedDesired   int32
+		expectedInService int32
+	}{
+		{
+			name:     "uses the replicas of the MachinePool",
+			replicas: aws.Int32(3),
+			asg: &infrav1.AutoScalingGroup{
+				MinSize:         1,
+				DesiredCapacity: aws.Int32(2),
+				Instances: []infrav1.AWSMachinePoolInstanceStatus{
+					{InstanceID: "i-1", Ready: true},
+					{InstanceID: "i-2", Ready: false},
+					{InstanceID: "i-3", Ready: true},
+				},
+			},
+			expectedDesired:   3,
+			expectedInService: 2,
+		},
+		{
+			name: "falls back to the desired capacity of the Auto Scaling Group",
+			asg: &infrav1.AutoScalingGroup{
+				MinSize:         1,
+				DesiredCapacity: aws.Int32(2),
+			},
+			expectedDesired: 2,
+		},
+		{
+			name: "falls back to the minimum size of the Auto Scaling Group",
+			asg: &infrav1.AutoScalingGroup{
+				MinSize: 1,
+				Instances: []infrav1.AWSMachinePoolInstanceStatus{
+					{InstanceID: "i-1", Ready: true},
+				},
+			},
+			expectedDesired:   1,
+			expectedInService: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pool := newMachinePool("my-cluster", "my-pool")
+			pool.Spec.Replicas = tc.replicas
+
+			machinePoolScope := &scope.MachinePoolScope{MachinePool: pool}
+
+			desired, inService := asgCapacity(machinePoolScope, tc.asg)
+			if desired != tc.expectedDesired || inService != tc.expectedInService {
+				t.Fatalf("Expected %d desired and %d in service instances but got %d and %d",
+					tc.expectedDesired, tc.expectedInService, desired, inService)
+			}
+		})
+	}
+}
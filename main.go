@@ -31,8 +31,10 @@ import (
 	infrav1alpha2 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha2"
 	infrav1alpha3 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
 	"sigs.k8s.io/cluster-api-provider-aws/controllers"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha3"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -49,6 +51,7 @@ func init() {
 	_ = infrav1alpha2.AddToScheme(scheme)
 	_ = infrav1alpha3.AddToScheme(scheme)
 	_ = clusterv1.AddToScheme(scheme)
+	_ = expv1.AddToScheme(scheme)
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -56,16 +59,21 @@ func main() {
 	klog.InitFlags(nil)
 
 	var (
-		metricsAddr             string
-		enableLeaderElection    bool
-		leaderElectionNamespace string
-		watchNamespace          string
-		profilerAddress         string
-		awsClusterConcurrency   int
-		awsMachineConcurrency   int
-		syncPeriod              time.Duration
-		webhookPort             int
-		healthAddr              string
+		metricsAddr               string
+		enableLeaderElection      bool
+		leaderElectionNamespace   string
+		watchNamespace            string
+		profilerAddress           string
+		awsClusterConcurrency     int
+		awsMachineConcurrency     int
+		awsMachinePoolConcurrency int
+		enableMachinePools        bool
+		syncPeriod                time.Duration
+		webhookPort               int
+		healthAddr                string
+		instanceStateQueueURL     string
+		maintenanceAnnotation     string
+		enableDriftMetrics        bool
 	)
 
 	flag.StringVar(
@@ -115,6 +123,18 @@ func main() {
 		"Number of AWSMachines to process simultaneously",
 	)
 
+	flag.IntVar(&awsMachinePoolConcurrency,
+		"awsmachinepool-concurrency",
+		5,
+		"Number of AWSMachinePools to process simultaneously",
+	)
+
+	flag.BoolVar(&enableMachinePools,
+		"enable-machine-pools",
+		false,
+		"Enable the experimental AWSMachinePool controller. Requires the Cluster API MachinePool feature to be enabled.",
+	)
+
 	flag.DurationVar(&syncPeriod,
 		"sync-period",
 		10*time.Minute,
@@ -133,6 +153,24 @@ func main() {
 		"The address the health endpoint binds to.",
 	)
 
+	flag.StringVar(&instanceStateQueueURL,
+		"instance-state-queue-url",
+		"",
+		"URL of an SQS queue receiving EC2 instance state-change and spot interruption notifications from EventBridge. When set, AWSMachines are reconciled as soon as their instance changes state.",
+	)
+
+	flag.StringVar(&maintenanceAnnotation,
+		"instance-maintenance-annotation",
+		controllers.DefaultInstanceMaintenanceAnnotation,
+		"Annotation set on the Machines whose EC2 instance has scheduled maintenance events or received a spot interruption notice.",
+	)
+
+	flag.BoolVar(&enableDriftMetrics,
+		"enable-drift-metrics",
+		false,
+		"Enable the capa_awsmachine_drifted metric, reporting whether the EC2 instance of each AWSMachine differs from its spec.",
+	)
+
 	flag.Parse()
 
 	if watchNamespace != "" {
@@ -174,10 +212,27 @@ func main() {
 	record.InitFromRecorder(mgr.GetEventRecorderFor("aws-controller"))
 
 	if webhookPort == 0 {
+		var instanceEvents <-chan instancestate.InstanceEvent
+		if instanceStateQueueURL != "" {
+			watcher, err := instancestate.NewWatcher(instanceStateQueueURL, ctrl.Log.WithName("instancestate"))
+			if err != nil {
+				setupLog.Error(err, "unable to create instance state watcher")
+				os.Exit(1)
+			}
+			if err := mgr.Add(watcher); err != nil {
+				setupLog.Error(err, "unable to add instance state watcher")
+				os.Exit(1)
+			}
+			instanceEvents = watcher.Events()
+		}
+
 		if err = (&controllers.AWSMachineReconciler{
-			Client:   mgr.GetClient(),
-			Log:      ctrl.Log.WithName("controllers").WithName("AWSMachine"),
-			Recorder: mgr.GetEventRecorderFor("awsmachine-controller"),
+			Client:                mgr.GetClient(),
+			Log:                   ctrl.Log.WithName("controllers").WithName("AWSMachine"),
+			Recorder:              mgr.GetEventRecorderFor("awsmachine-controller"),
+			InstanceEvents:        instanceEvents,
+			MaintenanceAnnotation: maintenanceAnnotation,
+			DriftMetrics:          enableDriftMetrics,
 		}).SetupWithManager(mgr, controller.Options{MaxConcurrentReconciles: awsMachineConcurrency}); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "AWSMachine")
 			os.Exit(1)
@@ -190,6 +245,16 @@ func main() {
 			setupLog.Error(err, "unable to create controller", "controller", "AWSCluster")
 			os.Exit(1)
 		}
+		if enableMachinePools {
+			if err = (&controllers.AWSMachinePoolReconciler{
+				Client:   mgr.GetClient(),
+				Log:      ctrl.Log.WithName("controllers").WithName("AWSMachinePool"),
+				Recorder: mgr.GetEventRecorderFor("awsmachinepool-controller"),
+			}).SetupWithManager(mgr, controller.Options{MaxConcurrentReconciles: awsMachinePoolConcurrency}); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "AWSMachinePool")
+				os.Exit(1)
+			}
+		}
 	} else {
 		if err = (&infrav1alpha3.AWSMachineTemplate{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "AWSMachineTemplate")
@@ -215,6 +280,10 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "AWSClusterList")
 			os.Exit(1)
 		}
+		if err = (&infrav1alpha3.AWSMachinePool{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "AWSMachinePool")
+			os.Exit(1)
+		}
 	}
 	// +kubebuilder:scaffold:builder
 
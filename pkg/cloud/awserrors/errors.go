@@ -23,23 +23,39 @@ import (
 )
 
 const (
-	AuthFailure             = "AuthFailure"
-	InUseIPAddress          = "InvalidIPAddress.InUse"
-	GroupNotFound           = "InvalidGroup.NotFound"
-	PermissionNotFound      = "InvalidPermission.NotFound"
-	VPCNotFound             = "InvalidVpcID.NotFound"
-	SubnetNotFound          = "InvalidSubnetID.NotFound"
-	InternetGatewayNotFound = "InvalidInternetGatewayID.NotFound"
-	NATGatewayNotFound      = "InvalidNatGatewayID.NotFound"
-	GatewayNotFound         = "InvalidGatewayID.NotFound"
-	EIPNotFound             = "InvalidElasticIpID.NotFound"
-	RouteTableNotFound      = "InvalidRouteTableID.NotFound"
-	LoadBalancerNotFound    = "LoadBalancerNotFound"
-	ResourceNotFound        = "InvalidResourceID.NotFound"
-	InvalidSubnet           = "InvalidSubnet"
-	AssociationIDNotFound   = "InvalidAssociationID.NotFound"
-	InvalidInstanceID       = "InvalidInstanceID.NotFound"
-	ResourceExists          = "ResourceExistsException"
+	AuthFailure                = "AuthFailure"
+	InUseIPAddress             = "InvalidIPAddress.InUse"
+	GroupNotFound              = "InvalidGroup.NotFound"
+	PermissionNotFound         = "InvalidPermission.NotFound"
+	VPCNotFound                = "InvalidVpcID.NotFound"
+	SubnetNotFound             = "InvalidSubnetID.NotFound"
+	InternetGatewayNotFound    = "InvalidInternetGatewayID.NotFound"
+	NATGatewayNotFound         = "InvalidNatGatewayID.NotFound"
+	GatewayNotFound            = "InvalidGatewayID.NotFound"
+	EIPNotFound                = "InvalidElasticIpID.NotFound"
+	RouteTableNotFound         = "InvalidRouteTableID.NotFound"
+	LoadBalancerNotFound       = "LoadBalancerNotFound"
+	ResourceNotFound           = "InvalidResourceID.NotFound"
+	InvalidSubnet              = "InvalidSubnet"
+	AssociationIDNotFound      = "InvalidAssociationID.NotFound"
+	InvalidInstanceID          = "InvalidInstanceID.NotFound"
+	LaunchTemplateNameNotFound = "InvalidLaunchTemplateName.NotFoundException"
+	LaunchTemplateIDNotFound   = "InvalidLaunchTemplateId.NotFound"
+	PlacementGroupNotFound     = "InvalidPlacementGroup.Unknown"
+	ResourceExists             = "ResourceExistsException"
+	NetworkInterfaceNotFound   = "InvalidNetworkInterfaceID.NotFound"
+	NetworkInterfaceInUse      = "InvalidNetworkInterface.InUse"
+	VPCEndpointNotFound        = "InvalidVpcEndpointId.NotFound"
+
+	InsufficientCapacity         = "InsufficientCapacity"
+	InsufficientHostCapacity     = "InsufficientHostCapacity"
+	InsufficientInstanceCapacity = "InsufficientInstanceCapacity"
+
+	InvalidParameterValue         = "InvalidParameterValue"
+	InvalidParameterCombination   = "InvalidParameterCombination"
+	InvalidInstanceAttributeValue = "InvalidInstanceAttributeValue"
+	Unsupported                   = "Unsupported"
+	UnsupportedOperation          = "UnsupportedOperation"
 )
 
 var _ error = &EC2Error{}
@@ -95,6 +111,30 @@ func IsResourceExists(err error) bool {
 	return false
 }
 
+// IsInsufficientCapacity returns true if the error is caused by AWS not having
+// enough capacity to fulfill the request.
+func IsInsufficientCapacity(err error) bool {
+	if code, ok := Code(err); ok {
+		switch code {
+		case InsufficientCapacity, InsufficientHostCapacity, InsufficientInstanceCapacity:
+			return true
+		}
+	}
+	return false
+}
+
+// IsInvalidRequest returns true if the error is caused by AWS rejecting the parameters
+// of the request, so that retrying the same request won't succeed.
+func IsInvalidRequest(err error) bool {
+	if code, ok := Code(err); ok {
+		switch code {
+		case InvalidParameterValue, InvalidParameterCombination, InvalidInstanceAttributeValue, Unsupported, UnsupportedOperation:
+			return true
+		}
+	}
+	return false
+}
+
 // NewFailedDependency returns a new error which indicates that a dependency failure status
 func NewFailedDependency(err error) error {
 	return &EC2Error{
@@ -135,6 +175,10 @@ func IsInvalidNotFoundError(err error) bool {
 			return true
 		case InvalidInstanceID:
 			return true
+		case LaunchTemplateNameNotFound:
+			return true
+		case LaunchTemplateIDNotFound:
+			return true
 		}
 	}
 
@@ -29,6 +29,7 @@ const (
 	filterNameVpcID         = "vpc-id"
 	filterNameState         = "state"
 	filterNameVpcAttachment = "attachment.vpc-id"
+	filterAvailabilityZone  = "availability-zone"
 )
 
 var (
@@ -104,6 +105,14 @@ func (ec2Filters) VPCAttachment(vpcID string) *ec2.Filter {
 	}
 }
 
+// AvailabilityZone returns a filter based on the availability zone of the resource.
+func (ec2Filters) AvailabilityZone(zone string) *ec2.Filter {
+	return &ec2.Filter{
+		Name:   aws.String(filterAvailabilityZone),
+		Values: aws.StringSlice([]string{zone}),
+	}
+}
+
 // Available returns a filter based on the state being available.
 func (ec2Filters) Available() *ec2.Filter {
 	return &ec2.Filter{
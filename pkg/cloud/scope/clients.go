@@ -17,16 +17,22 @@ limitations under the License.
 package scope
 
 import (
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/elb/elbiface"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi/resourcegroupstaggingapiiface"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 )
 
 // AWSClients contains all the aws clients used by the scopes.
 type AWSClients struct {
+	ASG             autoscalingiface.AutoScalingAPI
 	EC2             ec2iface.EC2API
 	ELB             elbiface.ELBAPI
+	S3              s3iface.S3API
 	SecretsManager  secretsmanageriface.SecretsManagerAPI
+	SSM             ssmiface.SSMAPI
 	ResourceTagging resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI
 }
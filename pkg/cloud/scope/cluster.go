@@ -22,10 +22,13 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -78,6 +81,13 @@ func NewClusterScope(params ClusterScopeParams) (*ClusterScope, error) {
 		params.AWSClients.EC2 = ec2Client
 	}
 
+	if params.AWSClients.ASG == nil {
+		asgClient := autoscaling.New(session)
+		asgClient.Handlers.Build.PushFrontNamed(userAgentHandler)
+		asgClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(params.AWSCluster))
+		params.AWSClients.ASG = asgClient
+	}
+
 	if params.AWSClients.ELB == nil {
 		elbClient := elb.New(session)
 		elbClient.Handlers.Build.PushFrontNamed(userAgentHandler)
@@ -98,6 +108,20 @@ func NewClusterScope(params ClusterScopeParams) (*ClusterScope, error) {
 		params.AWSClients.SecretsManager = sClient
 	}
 
+	if params.AWSClients.S3 == nil {
+		s3Client := s3.New(session)
+		s3Client.Handlers.Build.PushFrontNamed(userAgentHandler)
+		s3Client.Handlers.Complete.PushBack(recordAWSPermissionsIssue(params.AWSCluster))
+		params.AWSClients.S3 = s3Client
+	}
+
+	if params.AWSClients.SSM == nil {
+		ssmClient := ssm.New(session)
+		ssmClient.Handlers.Build.PushFrontNamed(userAgentHandler)
+		ssmClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(params.AWSCluster))
+		params.AWSClients.SSM = ssmClient
+	}
+
 	helper, err := patch.NewHelper(params.AWSCluster, params.Client)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to init patch helper")
@@ -182,6 +206,20 @@ func (s *ClusterScope) ControlPlaneLoadBalancerScheme() infrav1.ClassicELBScheme
 	return infrav1.ClassicELBSchemeInternetFacing
 }
 
+// AccessMode returns how the cluster machines are accessed, defaulting to SSH.
+func (s *ClusterScope) AccessMode() infrav1.ClusterAccessMode {
+	if s.AWSCluster.Spec.AccessMode != "" {
+		return s.AWSCluster.Spec.AccessMode
+	}
+	return infrav1.ClusterAccessModeSSH
+}
+
+// VPCEndpointsEnabled returns whether the VPC interface endpoints of Session Manager are managed for the cluster.
+func (s *ClusterScope) VPCEndpointsEnabled() bool {
+	return s.AccessMode() == infrav1.ClusterAccessModeSessionManager &&
+		s.AWSCluster.Spec.SessionManager != nil && s.AWSCluster.Spec.SessionManager.CreateVPCEndpoints
+}
+
 // ControlPlaneConfigMapName returns the name of the ConfigMap used to
 // coordinate the bootstrapping of control plane nodes.
 func (s *ClusterScope) ControlPlaneConfigMapName() string {
@@ -195,6 +233,29 @@ func (s *ClusterScope) ListOptionsLabelSelector() client.ListOption {
 	})
 }
 
+// MachineSSHKeyNames returns the SSH key names set in the specs of the AWSMachines and AWSMachinePools
+// of the cluster. A nil key name means the machine uses the SSH key of the cluster.
+func (s *ClusterScope) MachineSSHKeyNames() ([]*string, error) {
+	machines := &infrav1.AWSMachineList{}
+	if err := s.client.List(context.TODO(), machines, client.InNamespace(s.Namespace()), s.ListOptionsLabelSelector()); err != nil {
+		return nil, errors.Wrap(err, "failed to list AWSMachines")
+	}
+
+	pools := &infrav1.AWSMachinePoolList{}
+	if err := s.client.List(context.TODO(), pools, client.InNamespace(s.Namespace()), s.ListOptionsLabelSelector()); err != nil {
+		return nil, errors.Wrap(err, "failed to list AWSMachinePools")
+	}
+
+	keys := make([]*string, 0, len(machines.Items)+len(pools.Items))
+	for _, m := range machines.Items {
+		keys = append(keys, m.Spec.SSHKeyName)
+	}
+	for _, p := range pools.Items {
+		keys = append(keys, p.Spec.AWSLaunchTemplate.SSHKeyName)
+	}
+	return keys, nil
+}
+
 // PatchObject persists the cluster configuration and status.
 func (s *ClusterScope) PatchObject() error {
 	return s.patchHelper.Patch(context.TODO(), s.AWSCluster)
@@ -19,6 +19,7 @@ package scope
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -152,6 +153,59 @@ func (m *MachineScope) SetInstanceState(v infrav1.InstanceState) {
 	m.AWSMachine.Status.InstanceState = &v
 }
 
+// SetInstanceLifecycle sets the AWSMachine instance lifecycle.
+func (m *MachineScope) SetInstanceLifecycle(v infrav1.InstanceLifecycle) {
+	m.AWSMachine.Status.InstanceLifecycle = &v
+}
+
+// SetInstanceType sets the AWSMachine launched instance type.
+func (m *MachineScope) SetInstanceType(v string) {
+	m.AWSMachine.Status.InstanceType = v
+}
+
+// SetArchitecture sets the AWSMachine instance architecture.
+func (m *MachineScope) SetArchitecture(v string) {
+	m.AWSMachine.Status.Architecture = v
+}
+
+// SetCapacityReservationID sets the AWSMachine instance Capacity Reservation ID.
+func (m *MachineScope) SetCapacityReservationID(v *string) {
+	m.AWSMachine.Status.CapacityReservationID = v
+}
+
+// SetResize sets the AWSMachine in-place resize in progress.
+func (m *MachineScope) SetResize(v *infrav1.InstanceResize) {
+	m.AWSMachine.Status.Resize = v
+}
+
+// SetScheduledEvents sets the events AWS has scheduled for the AWSMachine instance.
+func (m *MachineScope) SetScheduledEvents(v []infrav1.InstanceScheduledEvent) {
+	m.AWSMachine.Status.ScheduledEvents = v
+}
+
+// SetConditionTrue marks the AWSMachine condition of the given type as true.
+func (m *MachineScope) SetConditionTrue(conditionType infrav1.AWSMachineProviderConditionType) {
+	m.AWSMachine.Status.SetCondition(infrav1.AWSMachineProviderCondition{
+		Type:   conditionType,
+		Status: corev1.ConditionTrue,
+	})
+}
+
+// SetConditionFalse marks the AWSMachine condition of the given type as false, with a reason and message.
+func (m *MachineScope) SetConditionFalse(conditionType infrav1.AWSMachineProviderConditionType, reason string, messageFormat string, messageArgs ...interface{}) {
+	m.AWSMachine.Status.SetCondition(infrav1.AWSMachineProviderCondition{
+		Type:    conditionType,
+		Status:  corev1.ConditionFalse,
+		Reason:  reason,
+		Message: fmt.Sprintf(messageFormat, messageArgs...),
+	})
+}
+
+// SetVolumeAttachments sets the AWSMachine volume attachments.
+func (m *MachineScope) SetVolumeAttachments(v []infrav1.VolumeAttachment) {
+	m.AWSMachine.Status.VolumeAttachments = v
+}
+
 // SetReady sets the AWSMachine Ready Status
 func (m *MachineScope) SetReady() {
 	m.AWSMachine.Status.Ready = true
@@ -186,9 +240,30 @@ func (m *MachineScope) UseSecretsManager() bool {
 	return !m.AWSMachine.Spec.CloudInit.InsecureSkipSecretsManager
 }
 
+// SecureSecretsBackend returns the backend storing the userdata
+// until the machine joins the cluster.
+func (m *MachineScope) SecureSecretsBackend() infrav1.SecretBackend {
+	if m.AWSMachine.Spec.CloudInit.SecureSecretsBackend == "" {
+		return infrav1.SecretBackendSecretsManager
+	}
+	return m.AWSMachine.Spec.CloudInit.SecureSecretsBackend
+}
+
+// BootstrapFormat returns the format of the bootstrap data of the machine.
+func (m *MachineScope) BootstrapFormat() infrav1.BootstrapFormat {
+	if m.AWSMachine.Spec.BootstrapFormat == "" {
+		return infrav1.BootstrapFormatCloudConfig
+	}
+	return m.AWSMachine.Spec.BootstrapFormat
+}
+
 // UserDataIsCompressed returns the computed value of whether or not
 // userdata should be compressed using gzip.
+// Ignition configs are never compressed.
 func (m *MachineScope) UserDataIsUncompressed() bool {
+	if m.BootstrapFormat() == infrav1.BootstrapFormatIgnition {
+		return true
+	}
 	return m.AWSMachine.Spec.UncompressedUserData != nil && *m.AWSMachine.Spec.UncompressedUserData
 }
 
@@ -201,3 +201,20 @@ func TestSetSecretARN(t *testing.T) {
 		t.Fatalf("prefix does not equal %s: %s", prefix, val)
 	}
 }
+
+func TestUserDataIsUncompressedWithIgnition(t *testing.T) {
+	scope, err := setupMachineScope()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if scope.UserDataIsUncompressed() {
+		t.Fatalf("UserDataIsUncompressed should be false by default")
+	}
+
+	scope.AWSMachine.Spec.BootstrapFormat = infrav1.BootstrapFormatIgnition
+	scope.AWSMachine.Spec.UncompressedUserData = pointer.BoolPtr(false)
+	if !scope.UserDataIsUncompressed() {
+		t.Fatalf("UserDataIsUncompressed should be true with ignition")
+	}
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/klogr"
+	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MachinePoolScopeParams defines the input parameters used to create a new MachinePoolScope.
+type MachinePoolScopeParams struct {
+	Client         client.Client
+	Logger         logr.Logger
+	Cluster        *clusterv1.Cluster
+	MachinePool    *expv1.MachinePool
+	AWSCluster     *infrav1.AWSCluster
+	AWSMachinePool *infrav1.AWSMachinePool
+}
+
+// NewMachinePoolScope creates a new MachinePoolScope from the supplied parameters.
+// This is meant to be called for each reconcile iteration.
+func NewMachinePoolScope(params MachinePoolScopeParams) (*MachinePoolScope, error) {
+	if params.Client == nil {
+		return nil, errors.New("client is required when creating a MachinePoolScope")
+	}
+	if params.MachinePool == nil {
+		return nil, errors.New("machine pool is required when creating a MachinePoolScope")
+	}
+	if params.Cluster == nil {
+		return nil, errors.New("cluster is required when creating a MachinePoolScope")
+	}
+	if params.AWSMachinePool == nil {
+		return nil, errors.New("aws machine pool is required when creating a MachinePoolScope")
+	}
+	if params.AWSCluster == nil {
+		return nil, errors.New("aws cluster is required when creating a MachinePoolScope")
+	}
+
+	if params.Logger == nil {
+		params.Logger = klogr.New()
+	}
+
+	helper, err := patch.NewHelper(params.AWSMachinePool, params.Client)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to init patch helper")
+	}
+	return &MachinePoolScope{
+		Logger:      params.Logger,
+		client:      params.Client,
+		patchHelper: helper,
+
+		Cluster:        params.Cluster,
+		MachinePool:    params.MachinePool,
+		AWSCluster:     params.AWSCluster,
+		AWSMachinePool: params.AWSMachinePool,
+	}, nil
+}
+
+// MachinePoolScope defines a scope defined around a machine pool and its cluster.
+type MachinePoolScope struct {
+	logr.Logger
+	client      client.Client
+	patchHelper *patch.Helper
+
+	Cluster        *clusterv1.Cluster
+	MachinePool    *expv1.MachinePool
+	AWSCluster     *infrav1.AWSCluster
+	AWSMachinePool *infrav1.AWSMachinePool
+}
+
+// Name returns the AWSMachinePool name.
+func (m *MachinePoolScope) Name() string {
+	return m.AWSMachinePool.Name
+}
+
+// Namespace returns the namespace name.
+func (m *MachinePoolScope) Namespace() string {
+	return m.AWSMachinePool.Namespace
+}
+
+// ResourceName returns the name of the Auto Scaling Group and launch template backing the AWSMachinePool.
+// It is prefixed with the cluster name and namespace, since AWSMachinePools in different namespaces or
+// clusters of the same account and region can share a name.
+func (m *MachinePoolScope) ResourceName() string {
+	return fmt.Sprintf("%s-%s-%s", m.Cluster.Name, m.Namespace(), m.Name())
+}
+
+// Role returns the machine role. Machine pools only ever run worker nodes.
+func (m *MachinePoolScope) Role() string {
+	return "node"
+}
+
+// SetProviderID sets the AWSMachinePool providerID in spec.
+func (m *MachinePoolScope) SetProviderID(v string) {
+	m.AWSMachinePool.Spec.ProviderID = v
+}
+
+// SetProviderIDList sets the AWSMachinePool providerIDList in spec.
+func (m *MachinePoolScope) SetProviderIDList(v []string) {
+	m.AWSMachinePool.Spec.ProviderIDList = v
+}
+
+// SetLaunchTemplateID sets the ID of the launch template backing the AWSMachinePool.
+func (m *MachinePoolScope) SetLaunchTemplateID(v string) {
+	m.AWSMachinePool.Status.LaunchTemplateID = v
+}
+
+// SetInstances sets the status of the instances in the Auto Scaling Group.
+func (m *MachinePoolScope) SetInstances(v []infrav1.AWSMachinePoolInstanceStatus) {
+	m.AWSMachinePool.Status.Instances = v
+}
+
+// SetReplicas sets the AWSMachinePool replicas status.
+func (m *MachinePoolScope) SetReplicas(v int32) {
+	m.AWSMachinePool.Status.Replicas = v
+}
+
+// SetReady sets the AWSMachinePool Ready Status
+func (m *MachinePoolScope) SetReady() {
+	m.AWSMachinePool.Status.Ready = true
+}
+
+// SetNotReady sets the AWSMachinePool Ready Status to false
+func (m *MachinePoolScope) SetNotReady() {
+	m.AWSMachinePool.Status.Ready = false
+}
+
+// SetConditionTrue marks the AWSMachinePool condition of the given type as true.
+func (m *MachinePoolScope) SetConditionTrue(conditionType infrav1.AWSMachineProviderConditionType) {
+	m.AWSMachinePool.Status.SetCondition(infrav1.AWSMachineProviderCondition{
+		Type:   conditionType,
+		Status: corev1.ConditionTrue,
+	})
+}
+
+// SetConditionFalse marks the AWSMachinePool condition of the given type as false, with a reason and message.
+func (m *MachinePoolScope) SetConditionFalse(conditionType infrav1.AWSMachineProviderConditionType, reason string, messageFormat string, messageArgs ...interface{}) {
+	m.AWSMachinePool.Status.SetCondition(infrav1.AWSMachineProviderCondition{
+		Type:    conditionType,
+		Status:  corev1.ConditionFalse,
+		Reason:  reason,
+		Message: fmt.Sprintf(messageFormat, messageArgs...),
+	})
+}
+
+// SetFailureMessage sets the AWSMachinePool status failure message.
+func (m *MachinePoolScope) SetFailureMessage(v error) {
+	m.AWSMachinePool.Status.FailureMessage = pointer.StringPtr(v.Error())
+}
+
+// SetFailureReason sets the AWSMachinePool status failure reason.
+func (m *MachinePoolScope) SetFailureReason(v capierrors.MachineStatusError) {
+	m.AWSMachinePool.Status.FailureReason = &v
+}
+
+// HasFailed returns true if a terminal failure was recorded on the AWSMachinePool.
+func (m *MachinePoolScope) HasFailed() bool {
+	return m.AWSMachinePool.Status.FailureReason != nil || m.AWSMachinePool.Status.FailureMessage != nil
+}
+
+// DesiredCapacity returns the number of replicas requested on the MachinePool, if any.
+func (m *MachinePoolScope) DesiredCapacity() *int32 {
+	return m.MachinePool.Spec.Replicas
+}
+
+// GetRawBootstrapData returns the bootstrap data from the secret in the MachinePool's bootstrap.dataSecretName.
+func (m *MachinePoolScope) GetRawBootstrapData() ([]byte, error) {
+	if m.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName == nil {
+		return nil, errors.New("error retrieving bootstrap data: linked MachinePool's bootstrap.dataSecretName is nil")
+	}
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: m.Namespace(), Name: *m.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName}
+	if err := m.client.Get(context.TODO(), key, secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve bootstrap data secret for AWSMachinePool %s/%s", m.Namespace(), m.Name())
+	}
+
+	value, ok := secret.Data["value"]
+	if !ok {
+		return nil, errors.New("error retrieving bootstrap data: secret value key is missing")
+	}
+
+	return value, nil
+}
+
+// AdditionalTags merges AdditionalTags from the scope's AWSCluster and AWSMachinePool. If the same key is present in both,
+// the value from AWSMachinePool takes precedence. The returned Tags will never be nil.
+func (m *MachinePoolScope) AdditionalTags() infrav1.Tags {
+	tags := make(infrav1.Tags)
+
+	// Start with the cluster-wide tags...
+	tags.Merge(m.AWSCluster.Spec.AdditionalTags)
+	// ... and merge in the MachinePool's
+	tags.Merge(m.AWSMachinePool.Spec.AdditionalTags)
+
+	return tags
+}
+
+// PatchObject persists the machine pool spec and status.
+func (m *MachinePoolScope) PatchObject() error {
+	return m.patchHelper.Patch(context.TODO(), m.AWSMachinePool)
+}
+
+// Close the MachinePoolScope by updating the machine pool spec, machine pool status.
+func (m *MachinePoolScope) Close() error {
+	return m.PatchObject()
+}
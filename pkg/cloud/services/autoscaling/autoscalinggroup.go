@@ -0,0 +1,310 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaling
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+const (
+	// latestLaunchTemplateVersion makes the Auto Scaling Group always launch
+	// instances from the most recent version of the launch template.
+	latestLaunchTemplateVersion = "$Latest"
+
+	lifecycleStateInService = "InService"
+	healthStatusHealthy     = "Healthy"
+)
+
+// ASGIfExists returns the existing Auto Scaling Group or nothing if it doesn't exist.
+func (s *Service) ASGIfExists(name *string) (*infrav1.AutoScalingGroup, error) {
+	if name == nil {
+		s.scope.Info("Auto Scaling Group does not have a name")
+		return nil, nil
+	}
+
+	s.scope.V(2).Info("Looking for Auto Scaling Group by name", "name", *name)
+
+	input := &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []*string{name},
+	}
+
+	out, err := s.scope.ASG.DescribeAutoScalingGroups(input)
+	if err != nil {
+		record.Eventf(s.scope.AWSCluster, "FailedDescribeAutoScalingGroups", "Failed to describe Auto Scaling Group %q: %v", *name, err)
+		return nil, errors.Wrapf(err, "failed to describe Auto Scaling Group: %q", *name)
+	}
+
+	if len(out.AutoScalingGroups) == 0 {
+		return nil, nil
+	}
+
+	return s.SDKToAutoScalingGroup(out.AutoScalingGroups[0])
+}
+
+// GetASGByName returns the Auto Scaling Group backing the machine pool or nothing if it doesn't exist.
+// An Auto Scaling Group with the same name that isn't owned by the cluster is never adopted, nor
+// updated or deleted, so an error is returned for it.
+func (s *Service) GetASGByName(scope *scope.MachinePoolScope) (*infrav1.AutoScalingGroup, error) {
+	asg, err := s.ASGIfExists(aws.String(scope.ResourceName()))
+	if err != nil {
+		return nil, err
+	}
+
+	if asg != nil && !asg.Tags.HasOwned(s.scope.Name()) {
+		record.Warnf(scope.AWSMachinePool, "FailedAdopt", "Auto Scaling Group %q is not owned by cluster %q", asg.Name, s.scope.Name())
+		return nil, errors.Errorf("Auto Scaling Group %q is not owned by cluster %q, refusing to adopt it", asg.Name, s.scope.Name())
+	}
+
+	return asg, nil
+}
+
+// CreateASG creates the Auto Scaling Group backing the machine pool.
+func (s *Service) CreateASG(scope *scope.MachinePoolScope) (*infrav1.AutoScalingGroup, error) {
+	s.scope.V(2).Info("Creating an Auto Scaling Group for a machine pool")
+
+	subnets, err := s.SubnetIDs(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &infrav1.AutoScalingGroup{
+		Name:               scope.ResourceName(),
+		MinSize:            scope.AWSMachinePool.Spec.MinSize,
+		MaxSize:            scope.AWSMachinePool.Spec.MaxSize,
+		DesiredCapacity:    scope.DesiredCapacity(),
+		Subnets:            subnets,
+		LaunchTemplateName: scope.ResourceName(),
+		Tags:               s.buildTags(scope),
+	}
+
+	out, err := s.runPool(input)
+	if err != nil {
+		record.Warnf(scope.AWSMachinePool, "FailedCreate", "Failed to create Auto Scaling Group: %v", err)
+		return nil, err
+	}
+
+	record.Eventf(scope.AWSMachinePool, "SuccessfulCreate", "Created new Auto Scaling Group %q", out.Name)
+	return out, nil
+}
+
+// UpdateASG updates the size, subnets and tags of the Auto Scaling Group backing the machine pool.
+func (s *Service) UpdateASG(scope *scope.MachinePoolScope) error {
+	subnets, err := s.SubnetIDs(scope)
+	if err != nil {
+		return err
+	}
+
+	input := &autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String(scope.ResourceName()),
+		MinSize:              aws.Int64(int64(scope.AWSMachinePool.Spec.MinSize)),
+		MaxSize:              aws.Int64(int64(scope.AWSMachinePool.Spec.MaxSize)),
+		VPCZoneIdentifier:    aws.String(strings.Join(subnets, ",")),
+		LaunchTemplate: &autoscaling.LaunchTemplateSpecification{
+			LaunchTemplateName: aws.String(scope.ResourceName()),
+			Version:            aws.String(latestLaunchTemplateVersion),
+		},
+	}
+
+	if scope.DesiredCapacity() != nil {
+		input.DesiredCapacity = aws.Int64(int64(*scope.DesiredCapacity()))
+	}
+
+	if _, err := s.scope.ASG.UpdateAutoScalingGroup(input); err != nil {
+		record.Warnf(scope.AWSMachinePool, "FailedUpdate", "Failed to update Auto Scaling Group %q: %v", scope.ResourceName(), err)
+		return errors.Wrapf(err, "failed to update Auto Scaling Group %q", scope.ResourceName())
+	}
+
+	if _, err := s.scope.ASG.CreateOrUpdateTags(&autoscaling.CreateOrUpdateTagsInput{
+		Tags: mapToTags(scope.ResourceName(), s.buildTags(scope)),
+	}); err != nil {
+		return errors.Wrapf(err, "failed to update tags of Auto Scaling Group %q", scope.ResourceName())
+	}
+
+	return nil
+}
+
+// DeleteASG deletes the Auto Scaling Group with the given name, along with its instances.
+func (s *Service) DeleteASG(name string) error {
+	s.scope.V(2).Info("Deleting Auto Scaling Group", "name", name)
+
+	input := &autoscaling.DeleteAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String(name),
+		ForceDelete:          aws.Bool(true),
+	}
+
+	if _, err := s.scope.ASG.DeleteAutoScalingGroup(input); err != nil {
+		return errors.Wrapf(err, "failed to delete Auto Scaling Group %q", name)
+	}
+
+	s.scope.V(2).Info("Deleted Auto Scaling Group", "name", name)
+	return nil
+}
+
+// SubnetIDs returns the subnets the machine pool's instances should be launched in.
+// If no subnets are specified on the AWSMachinePool, the cluster's private subnets are used.
+func (s *Service) SubnetIDs(scope *scope.MachinePoolScope) ([]string, error) {
+	subnetIDs := make([]string, 0, len(scope.AWSMachinePool.Spec.Subnets))
+	for _, subnet := range scope.AWSMachinePool.Spec.Subnets {
+		if subnet.ID == nil {
+			return nil, errors.Errorf("subnet references of AWSMachinePool %q must specify an ID", scope.Name())
+		}
+		subnetIDs = append(subnetIDs, *subnet.ID)
+	}
+
+	if len(subnetIDs) == 0 {
+		for _, subnet := range s.scope.Subnets().FilterPrivate() {
+			subnetIDs = append(subnetIDs, subnet.ID)
+		}
+	}
+
+	if len(subnetIDs) == 0 {
+		return nil, awserrors.NewFailedDependency(
+			errors.Errorf("failed to create Auto Scaling Group %q, no subnets available", scope.ResourceName()),
+		)
+	}
+
+	return subnetIDs, nil
+}
+
+// SDKToAutoScalingGroup converts an AWS Auto Scaling SDK group to the CAPA Auto Scaling Group type.
+func (s *Service) SDKToAutoScalingGroup(v *autoscaling.Group) (*infrav1.AutoScalingGroup, error) {
+	i := &infrav1.AutoScalingGroup{
+		ID:      aws.StringValue(v.AutoScalingGroupARN),
+		Name:    aws.StringValue(v.AutoScalingGroupName),
+		MinSize: int32(aws.Int64Value(v.MinSize)),
+		MaxSize: int32(aws.Int64Value(v.MaxSize)),
+		Status:  aws.StringValue(v.Status),
+	}
+
+	if v.DesiredCapacity != nil {
+		i.DesiredCapacity = aws.Int32(int32(*v.DesiredCapacity))
+	}
+
+	if v.VPCZoneIdentifier != nil && *v.VPCZoneIdentifier != "" {
+		i.Subnets = strings.Split(*v.VPCZoneIdentifier, ",")
+	}
+
+	if v.LaunchTemplate != nil {
+		i.LaunchTemplateName = aws.StringValue(v.LaunchTemplate.LaunchTemplateName)
+	}
+
+	if len(v.Tags) > 0 {
+		i.Tags = make(infrav1.Tags, len(v.Tags))
+		for _, tag := range v.Tags {
+			i.Tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+	}
+
+	for _, instance := range v.Instances {
+		i.Instances = append(i.Instances, infrav1.AWSMachinePoolInstanceStatus{
+			InstanceID:       aws.StringValue(instance.InstanceId),
+			ProviderID:       fmt.Sprintf("aws:///%s/%s", aws.StringValue(instance.AvailabilityZone), aws.StringValue(instance.InstanceId)),
+			AvailabilityZone: aws.StringValue(instance.AvailabilityZone),
+			LifecycleState:   aws.StringValue(instance.LifecycleState),
+			Ready: aws.StringValue(instance.LifecycleState) == lifecycleStateInService &&
+				aws.StringValue(instance.HealthStatus) == healthStatusHealthy,
+		})
+	}
+
+	// Keep the instances in a stable order, so that the status doesn't flap between reconciliations.
+	sort.Slice(i.Instances, func(a, b int) bool {
+		return i.Instances[a].InstanceID < i.Instances[b].InstanceID
+	})
+
+	return i, nil
+}
+
+func (s *Service) runPool(i *infrav1.AutoScalingGroup) (*infrav1.AutoScalingGroup, error) {
+	input := &autoscaling.CreateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String(i.Name),
+		MinSize:              aws.Int64(int64(i.MinSize)),
+		MaxSize:              aws.Int64(int64(i.MaxSize)),
+		VPCZoneIdentifier:    aws.String(strings.Join(i.Subnets, ",")),
+		LaunchTemplate: &autoscaling.LaunchTemplateSpecification{
+			LaunchTemplateName: aws.String(i.LaunchTemplateName),
+			Version:            aws.String(latestLaunchTemplateVersion),
+		},
+	}
+
+	if i.DesiredCapacity != nil {
+		input.DesiredCapacity = aws.Int64(int64(*i.DesiredCapacity))
+	}
+
+	if len(i.Tags) > 0 {
+		input.Tags = mapToTags(i.Name, i.Tags)
+	}
+
+	if _, err := s.scope.ASG.CreateAutoScalingGroup(input); err != nil {
+		return nil, errors.Wrapf(err, "failed to create Auto Scaling Group %q", i.Name)
+	}
+
+	out, err := s.ASGIfExists(aws.String(i.Name))
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, errors.Errorf("no Auto Scaling Group returned after creating %q", i.Name)
+	}
+
+	return out, nil
+}
+
+func (s *Service) buildTags(scope *scope.MachinePoolScope) infrav1.Tags {
+	// Make sure to use the MachinePoolScope here to get the merger of AWSCluster and AWSMachinePool tags
+	additionalTags := scope.AdditionalTags()
+	// Set the cloud provider tag
+	additionalTags[infrav1.ClusterAWSCloudProviderTagKey(s.scope.Name())] = string(infrav1.ResourceLifecycleOwned)
+
+	return infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(scope.Name()),
+		Role:        aws.String(scope.Role()),
+		Additional:  additionalTags,
+	})
+}
+
+// mapToTags converts a map of tags to Auto Scaling Group tags. The tags are not propagated to the
+// instances, since the launch template takes care of tagging them.
+func mapToTags(name string, src infrav1.Tags) []*autoscaling.Tag {
+	tags := make([]*autoscaling.Tag, 0, len(src))
+
+	for k, v := range src {
+		tags = append(tags, &autoscaling.Tag{
+			Key:               aws.String(k),
+			Value:             aws.String(v),
+			PropagateAtLaunch: aws.Bool(false),
+			ResourceId:        aws.String(name),
+			ResourceType:      aws.String("auto-scaling-group"),
+		})
+	}
+
+	// Sort so that unit tests can expect a stable order.
+	sort.Slice(tags, func(i, j int) bool { return *tags[i].Key < *tags[j].Key })
+
+	return tags
+}
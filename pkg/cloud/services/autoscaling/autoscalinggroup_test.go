@@ -0,0 +1,327 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaling
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/autoscaling/mock_autoscalingiface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestASGIfExists(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name   string
+		expect func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder)
+		check  func(asg *infrav1.AutoScalingGroup, err error)
+	}{
+		{
+			name: "does not exist",
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.DescribeAutoScalingGroups(gomock.Eq(&autoscaling.DescribeAutoScalingGroupsInput{
+					AutoScalingGroupNames: []*string{aws.String("pool")},
+				})).
+					Return(&autoscaling.DescribeAutoScalingGroupsOutput{}, nil)
+			},
+			check: func(asg *infrav1.AutoScalingGroup, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+
+				if asg != nil {
+					t.Fatalf("Did not expect anything but got something: %+v", asg)
+				}
+			},
+		},
+		{
+			name: "exists",
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.DescribeAutoScalingGroups(gomock.Eq(&autoscaling.DescribeAutoScalingGroupsInput{
+					AutoScalingGroupNames: []*string{aws.String("pool")},
+				})).
+					Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+						AutoScalingGroups: []*autoscaling.Group{
+							{
+								AutoScalingGroupARN:  aws.String("arn:aws:autoscaling:us-east-1:123456789012:autoScalingGroup:uuid:autoScalingGroupName/pool"),
+								AutoScalingGroupName: aws.String("pool"),
+								DesiredCapacity:      aws.Int64(2),
+								MinSize:              aws.Int64(1),
+								MaxSize:              aws.Int64(3),
+								VPCZoneIdentifier:    aws.String("subnet-1,subnet-2"),
+								Instances: []*autoscaling.Instance{
+									{
+										InstanceId:       aws.String("i-2"),
+										AvailabilityZone: aws.String("us-east-1b"),
+										LifecycleState:   aws.String("Pending"),
+										HealthStatus:     aws.String("Healthy"),
+									},
+									{
+										InstanceId:       aws.String("i-1"),
+										AvailabilityZone: aws.String("us-east-1a"),
+										LifecycleState:   aws.String("InService"),
+										HealthStatus:     aws.String("Healthy"),
+									},
+								},
+							},
+						},
+					}, nil)
+			},
+			check: func(asg *infrav1.AutoScalingGroup, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+
+				if asg == nil {
+					t.Fatalf("expected Auto Scaling Group but got nothing")
+				}
+
+				if len(asg.Subnets) != 2 {
+					t.Fatalf("expected 2 subnets but got: %v", asg.Subnets)
+				}
+
+				if len(asg.Instances) != 2 {
+					t.Fatalf("expected 2 instances but got: %v", len(asg.Instances))
+				}
+
+				if asg.Instances[0].ProviderID != "aws:///us-east-1a/i-1" {
+					t.Fatalf("expected provider ID aws:///us-east-1a/i-1 but got: %v", asg.Instances[0].ProviderID)
+				}
+
+				if !asg.Instances[0].Ready || asg.Instances[1].Ready {
+					t.Fatalf("expected only the in service instance to be ready, got: %+v", asg.Instances)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				AWSClients: scope.AWSClients{
+					ASG: asgMock,
+				},
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(asgMock.EXPECT())
+
+			s := NewService(scope)
+			asg, err := s.ASGIfExists(aws.String("pool"))
+			tc.check(asg, err)
+		})
+	}
+}
+
+func TestGetASGByName(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name      string
+		tags      []*autoscaling.TagDescription
+		expectErr bool
+	}{
+		{
+			name: "owned by the cluster",
+			tags: []*autoscaling.TagDescription{
+				{
+					Key:   aws.String(infrav1.ClusterTagKey("test-cluster")),
+					Value: aws.String(string(infrav1.ResourceLifecycleOwned)),
+				},
+			},
+		},
+		{
+			name: "owned by another cluster",
+			tags: []*autoscaling.TagDescription{
+				{
+					Key:   aws.String(infrav1.ClusterTagKey("other-cluster")),
+					Value: aws.String(string(infrav1.ResourceLifecycleOwned)),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name:      "not tagged",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+
+			cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"}}
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				AWSClients: scope.AWSClients{
+					ASG: asgMock,
+				},
+				Cluster:    cluster,
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			machinePoolScope, err := scope.NewMachinePoolScope(scope.MachinePoolScopeParams{
+				Client:      fake.NewFakeClientWithScheme(runtime.NewScheme()),
+				Cluster:     cluster,
+				MachinePool: &expv1.MachinePool{},
+				AWSCluster:  clusterScope.AWSCluster,
+				AWSMachinePool: &infrav1.AWSMachinePool{
+					ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default"},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			asgMock.EXPECT().DescribeAutoScalingGroups(gomock.Eq(&autoscaling.DescribeAutoScalingGroupsInput{
+				AutoScalingGroupNames: []*string{aws.String("test-cluster-default-pool")},
+			})).
+				Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+					AutoScalingGroups: []*autoscaling.Group{
+						{
+							AutoScalingGroupName: aws.String("test-cluster-default-pool"),
+							Tags:                 tc.tags,
+						},
+					},
+				}, nil)
+
+			s := NewService(clusterScope)
+			asg, err := s.GetASGByName(machinePoolScope)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+			if asg == nil || asg.Name != "test-cluster-default-pool" {
+				t.Fatalf("expected Auto Scaling Group test-cluster-default-pool but got: %+v", asg)
+			}
+		})
+	}
+}
+
+func TestSubnetIDs(t *testing.T) {
+	testCases := []struct {
+		name      string
+		subnets   []infrav1.AWSResourceReference
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name:     "defaults to the private subnets of the cluster",
+			expected: []string{"subnet-private-1", "subnet-private-2"},
+		},
+		{
+			name: "uses the subnets from the spec",
+			subnets: []infrav1.AWSResourceReference{
+				{ID: aws.String("subnet-custom")},
+			},
+			expected: []string{"subnet-custom"},
+		},
+		{
+			name: "subnet references without an ID are rejected",
+			subnets: []infrav1.AWSResourceReference{
+				{Filters: []infrav1.Filter{{Name: "tag:Name", Values: []string{"custom"}}}},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				AWSClients: scope.AWSClients{
+					ASG: mock_autoscalingiface.NewMockAutoScalingAPI(gomock.NewController(t)),
+				},
+				Cluster: &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							Subnets: infrav1.Subnets{
+								{ID: "subnet-private-1", IsPublic: false},
+								{ID: "subnet-public-1", IsPublic: true},
+								{ID: "subnet-private-2", IsPublic: false},
+							},
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			machinePoolScope, err := scope.NewMachinePoolScope(scope.MachinePoolScopeParams{
+				Client:      fake.NewFakeClientWithScheme(runtime.NewScheme()),
+				Cluster:     &clusterv1.Cluster{},
+				MachinePool: &expv1.MachinePool{},
+				AWSCluster:  clusterScope.AWSCluster,
+				AWSMachinePool: &infrav1.AWSMachinePool{
+					Spec: infrav1.AWSMachinePoolSpec{
+						Subnets: tc.subnets,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			s := NewService(clusterScope)
+			subnets, err := s.SubnetIDs(machinePoolScope)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+
+			if len(subnets) != len(tc.expected) {
+				t.Fatalf("expected subnets %v, got %v", tc.expected, subnets)
+			}
+			for i := range subnets {
+				if subnets[i] != tc.expected[i] {
+					t.Fatalf("expected subnets %v, got %v", tc.expected, subnets)
+				}
+			}
+		})
+	}
+}
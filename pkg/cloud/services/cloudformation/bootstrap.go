@@ -150,49 +150,69 @@ func controllersPolicy(accountID, partition string) *iam.PolicyDocument {
 				Resource: iam.Resources{"*"},
 				Action: iam.Actions{
 					"ec2:AllocateAddress",
+					"ec2:AssociateAddress",
 					"ec2:AssociateRouteTable",
 					"ec2:AttachInternetGateway",
 					"ec2:AuthorizeSecurityGroupIngress",
+					"ec2:CancelSpotInstanceRequests",
 					"ec2:CreateInternetGateway",
+					"ec2:CreateLaunchTemplate",
+					"ec2:CreateLaunchTemplateVersion",
 					"ec2:CreateNatGateway",
+					"ec2:CreateNetworkInterface",
+					"ec2:CreatePlacementGroup",
 					"ec2:CreateRoute",
 					"ec2:CreateRouteTable",
 					"ec2:CreateSecurityGroup",
 					"ec2:CreateSubnet",
 					"ec2:CreateTags",
 					"ec2:CreateVpc",
+					"ec2:CreateVpcEndpoint",
 					"ec2:ModifyVpcAttribute",
 					"ec2:DeleteInternetGateway",
+					"ec2:DeleteLaunchTemplate",
 					"ec2:DeleteNatGateway",
+					"ec2:DeleteNetworkInterface",
+					"ec2:DeletePlacementGroup",
 					"ec2:DeleteRouteTable",
 					"ec2:DeleteSecurityGroup",
 					"ec2:DeleteSubnet",
 					"ec2:DeleteTags",
 					"ec2:DeleteVpc",
+					"ec2:DeleteVpcEndpoints",
 					"ec2:DescribeAccountAttributes",
 					"ec2:DescribeAddresses",
 					"ec2:DescribeAvailabilityZones",
 					"ec2:DescribeInstances",
+					"ec2:DescribeInstanceStatus",
+					"ec2:DescribeInstanceTypes",
 					"ec2:DescribeInternetGateways",
 					"ec2:DescribeImages",
+					"ec2:DescribeLaunchTemplateVersions",
 					"ec2:DescribeNatGateways",
 					"ec2:DescribeNetworkInterfaces",
 					"ec2:DescribeNetworkInterfaceAttribute",
+					"ec2:DescribePlacementGroups",
 					"ec2:DescribeRouteTables",
 					"ec2:DescribeSecurityGroups",
 					"ec2:DescribeSubnets",
 					"ec2:DescribeVpcs",
 					"ec2:DescribeVpcAttribute",
+					"ec2:DescribeVpcEndpoints",
 					"ec2:DescribeVolumes",
 					"ec2:DetachInternetGateway",
 					"ec2:DisassociateRouteTable",
 					"ec2:DisassociateAddress",
 					"ec2:ModifyInstanceAttribute",
+					"ec2:ModifyInstanceMetadataOptions",
 					"ec2:ModifyNetworkInterfaceAttribute",
 					"ec2:ModifySubnetAttribute",
+					"ec2:ModifyVolume",
 					"ec2:ReleaseAddress",
 					"ec2:RevokeSecurityGroupIngress",
 					"ec2:RunInstances",
+					"ec2:StartInstances",
+					"ec2:StopInstances",
 					"ec2:TerminateInstances",
 					"tag:GetResources",
 					"elasticloadbalancing:AddTags",
@@ -205,6 +225,26 @@ func controllersPolicy(accountID, partition string) *iam.PolicyDocument {
 					"elasticloadbalancing:ModifyLoadBalancerAttributes",
 					"elasticloadbalancing:RegisterInstancesWithLoadBalancer",
 					"elasticloadbalancing:RemoveTags",
+					"autoscaling:CreateAutoScalingGroup",
+					"autoscaling:CreateOrUpdateTags",
+					"autoscaling:DeleteAutoScalingGroup",
+					"autoscaling:DescribeAutoScalingGroups",
+					"autoscaling:UpdateAutoScalingGroup",
+					"ssm:GetParameter",
+				},
+			},
+			{
+				Effect: iam.EffectAllow,
+				Resource: iam.Resources{fmt.Sprintf(
+					"arn:%s:iam::%s:role/aws-service-role/autoscaling.amazonaws.com/AWSServiceRoleForAutoScaling",
+					partition,
+					accountID,
+				)},
+				Action: iam.Actions{
+					"iam:CreateServiceLinkedRole",
+				},
+				Condition: iam.Conditions{
+					"StringLike": map[string]string{"iam:AWSServiceName": "autoscaling.amazonaws.com"},
 				},
 			},
 			{
@@ -221,6 +261,20 @@ func controllersPolicy(accountID, partition string) *iam.PolicyDocument {
 					"StringLike": map[string]string{"iam:AWSServiceName": "elasticloadbalancing.amazonaws.com"},
 				},
 			},
+			{
+				Effect: iam.EffectAllow,
+				Resource: iam.Resources{fmt.Sprintf(
+					"arn:%s:iam::%s:role/aws-service-role/spot.amazonaws.com/AWSServiceRoleForEC2Spot",
+					partition,
+					accountID,
+				)},
+				Action: iam.Actions{
+					"iam:CreateServiceLinkedRole",
+				},
+				Condition: iam.Conditions{
+					"StringLike": map[string]string{"iam:AWSServiceName": "spot.amazonaws.com"},
+				},
+			},
 			{
 				Effect: iam.EffectAllow,
 				Resource: iam.Resources{fmt.Sprintf(
@@ -245,6 +299,30 @@ func controllersPolicy(accountID, partition string) *iam.PolicyDocument {
 					"secretsmanager:TagResource",
 				},
 			},
+			{
+				Effect: iam.EffectAllow,
+				Resource: iam.Resources{fmt.Sprintf(
+					"arn:%s:s3:::*/aws.cluster.x-k8s.io/*",
+					partition,
+				)},
+				Action: iam.Actions{
+					"s3:PutObject",
+					"s3:DeleteObject",
+				},
+			},
+			bootstrapObjectKMSPolicy(partition, "kms:GenerateDataKey"),
+			{
+				Effect: iam.EffectAllow,
+				Resource: iam.Resources{fmt.Sprintf(
+					"arn:%s:sqs:*:%s:*",
+					partition,
+					accountID,
+				)},
+				Action: iam.Actions{
+					"sqs:DeleteMessage",
+					"sqs:ReceiveMessage",
+				},
+			},
 		},
 	}
 }
@@ -263,16 +341,56 @@ func bootstrapSecretPolicy(partition string) iam.StatementEntry {
 	}
 }
 
+func bootstrapObjectPolicy(partition string) iam.StatementEntry {
+	return iam.StatementEntry{
+		Effect: iam.EffectAllow,
+		Resource: iam.Resources{fmt.Sprintf(
+			"arn:%s:s3:::*/aws.cluster.x-k8s.io/*",
+			partition,
+		)},
+		Action: iam.Actions{
+			"s3:DeleteObject",
+			"s3:GetObject",
+		},
+	}
+}
+
+// bootstrapObjectKMSPolicy allows the given action on customer managed KMS keys, only through S3, so that
+// bootstrap data objects can be encrypted with them.
+func bootstrapObjectKMSPolicy(partition string, action string) iam.StatementEntry {
+	return iam.StatementEntry{
+		Effect: iam.EffectAllow,
+		Resource: iam.Resources{fmt.Sprintf(
+			"arn:%s:kms:*:*:key/*",
+			partition,
+		)},
+		Action: iam.Actions{
+			action,
+		},
+		Condition: iam.Conditions{
+			"StringLike": map[string]string{"kms:ViaService": "s3.*.amazonaws.com"},
+		},
+	}
+}
+
 func sessionManagerPolicy() iam.StatementEntry {
 	return iam.StatementEntry{
 		Effect:   iam.EffectAllow,
 		Resource: iam.Resources{"*"},
 		Action: iam.Actions{
 			"ssm:UpdateInstanceInformation",
+			"ssm:ListAssociations",
+			"ssm:ListInstanceAssociations",
 			"ssmmessages:CreateControlChannel",
 			"ssmmessages:CreateDataChannel",
 			"ssmmessages:OpenControlChannel",
 			"ssmmessages:OpenDataChannel",
+			"ec2messages:AcknowledgeMessage",
+			"ec2messages:DeleteMessage",
+			"ec2messages:FailMessage",
+			"ec2messages:GetEndpoint",
+			"ec2messages:GetMessages",
+			"ec2messages:SendReply",
 			"s3:GetEncryptionConfiguration",
 		},
 	}
@@ -283,6 +401,8 @@ func nodePolicy(partition string) *iam.PolicyDocument {
 	policyDocument.Statement = append(
 		policyDocument.Statement,
 		bootstrapSecretPolicy(partition),
+		bootstrapObjectPolicy(partition),
+		bootstrapObjectKMSPolicy(partition, "kms:Decrypt"),
 		sessionManagerPolicy(),
 	)
 	return policyDocument
@@ -17,14 +17,17 @@ limitations under the License.
 package ec2
 
 import (
-	"fmt"
+	"bytes"
 	"sort"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 )
 
 const (
@@ -36,43 +39,127 @@ const (
 	// when looking up machine AMIs
 	defaultMachineAMILookupBaseOS = "ubuntu-18.04"
 
-	// amiNameFormat is defined in the build/ directory of this project.
+	// defaultAMINameFormat is defined in the build/ directory of this project.
 	// The pattern is:
 	// 1. the string value `capa-ami-`
 	// 2. the baseOS of the AMI, for example: ubuntu-18.04, centos-7, amazon-2
 	// 3. the kubernetes version as defined by the packages produced by kubernetes/release, for example: 1.13.0-00, 1.12.5-01
 	// 4. the timestamp that the AMI was built
-	amiNameFormat = "capa-ami-%s-%s-??-??????????"
+	defaultAMINameFormat = "capa-ami-{{.BaseOS}}-{{.K8sVersion}}-??-??????????"
 
 	// Amazon's AMI timestamp format
 	createDateTimestampFormat = "2006-01-02T15:04:05.000Z"
+
+	// defaultArchitecture is the architecture used to look up AMIs when the
+	// instance type does not restrict it.
+	defaultArchitecture = ec2.ArchitectureTypeX8664
 )
 
-func amiName(baseOS, kubernetesVersion string) string {
-	return fmt.Sprintf(amiNameFormat, baseOS, strings.TrimPrefix(kubernetesVersion, "v"))
+// AMILookup holds the parameters used to resolve the AMI an instance is launched from.
+type AMILookup struct {
+	// OwnerID is the AWS account that owns the AMI.
+	OwnerID string
+
+	// BaseOS is the base operating system of the AMI.
+	BaseOS string
+
+	// Architecture is the processor architecture of the AMI.
+	Architecture string
+
+	// KubernetesVersion is the Kubernetes version the AMI is built for.
+	KubernetesVersion string
+
+	// NameFormat is the template used to build the AMI name.
+	NameFormat string
+
+	// SSMParameter is the template used to build the name of the SSM
+	// parameter holding the AMI ID.
+	SSMParameter string
+}
+
+// AMIResolver resolves the ID of the AMI an instance is launched from.
+type AMIResolver interface {
+	ResolveAMI(lookup *AMILookup) (string, error)
+}
+
+// amiTemplateData holds the variables available to AMI name and SSM parameter templates.
+type amiTemplateData struct {
+	BaseOS     string
+	K8sVersion string
+}
+
+// formatAMILookup evaluates an AMI name or SSM parameter template.
+func formatAMILookup(format, baseOS, kubernetesVersion string) (string, error) {
+	tmpl, err := template.New("ami").Parse(format)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse AMI lookup format %q", format)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, amiTemplateData{
+		BaseOS:     baseOS,
+		K8sVersion: strings.TrimPrefix(kubernetesVersion, "v"),
+	}); err != nil {
+		return "", errors.Wrapf(err, "failed to evaluate AMI lookup format %q", format)
+	}
+
+	return buf.String(), nil
+}
+
+// lookupAMI resolves an AMI ID, filling in defaults for the parameters that are not set.
+func (s *Service) lookupAMI(lookup *AMILookup) (string, error) {
+	if lookup.OwnerID == "" {
+		lookup.OwnerID = defaultMachineAMIOwnerID
+	}
+	if lookup.BaseOS == "" {
+		lookup.BaseOS = defaultMachineAMILookupBaseOS
+	}
+	if lookup.Architecture == "" {
+		lookup.Architecture = defaultArchitecture
+	}
+	if lookup.NameFormat == "" {
+		lookup.NameFormat = defaultAMINameFormat
+	}
+
+	return s.amiResolver(lookup).ResolveAMI(lookup)
 }
 
-// defaultAMILookup returns the default AMI based on region
-func (s *Service) defaultAMILookup(ownerID, baseOS, kubernetesVersion string) (string, error) {
-	if ownerID == "" {
-		ownerID = defaultMachineAMIOwnerID
+// amiResolver returns the resolver for an AMI lookup.
+func (s *Service) amiResolver(lookup *AMILookup) AMIResolver {
+	if s.amiResolverFactory != nil {
+		return s.amiResolverFactory(lookup)
 	}
-	if baseOS == "" {
-		baseOS = defaultMachineAMILookupBaseOS
+	if lookup.SSMParameter != "" {
+		return &ssmAMIResolver{scope: s.scope}
 	}
+	return &nameAMIResolver{scope: s.scope}
+}
+
+// nameAMIResolver resolves the latest AMI whose name matches the lookup name format.
+type nameAMIResolver struct {
+	scope *scope.ClusterScope
+}
+
+// ResolveAMI implements AMIResolver.
+func (r *nameAMIResolver) ResolveAMI(lookup *AMILookup) (string, error) {
+	name, err := formatAMILookup(lookup.NameFormat, lookup.BaseOS, lookup.KubernetesVersion)
+	if err != nil {
+		return "", err
+	}
+
 	describeImageInput := &ec2.DescribeImagesInput{
 		Filters: []*ec2.Filter{
 			{
 				Name:   aws.String("owner-id"),
-				Values: []*string{aws.String(ownerID)},
+				Values: []*string{aws.String(lookup.OwnerID)},
 			},
 			{
 				Name:   aws.String("name"),
-				Values: []*string{aws.String(amiName(baseOS, kubernetesVersion))},
+				Values: []*string{aws.String(name)},
 			},
 			{
 				Name:   aws.String("architecture"),
-				Values: []*string{aws.String("x86_64")},
+				Values: []*string{aws.String(lookup.Architecture)},
 			},
 			{
 				Name:   aws.String("state"),
@@ -85,22 +172,110 @@ func (s *Service) defaultAMILookup(ownerID, baseOS, kubernetesVersion string) (s
 		},
 	}
 
-	out, err := s.scope.EC2.DescribeImages(describeImageInput)
+	out, err := r.scope.EC2.DescribeImages(describeImageInput)
 	if err != nil {
-		return "", errors.Wrapf(err, "failed to find ami: %q", amiName(baseOS, kubernetesVersion))
+		return "", errors.Wrapf(err, "failed to find ami: %q", name)
 	}
 	if len(out.Images) == 0 {
-		return "", errors.Errorf("found no AMIs with the name: %q", amiName(baseOS, kubernetesVersion))
+		return "", errors.Errorf("found no %s AMIs with the name: %q", lookup.Architecture, name)
 	}
 	latestImage, err := getLatestImage(out.Images)
 	if err != nil {
-		s.scope.Error(err, "failed getting latest image from AMI list")
+		r.scope.Error(err, "failed getting latest image from AMI list")
 		return "", err
 	}
-	s.scope.V(2).Info("Found and using an existing AMI", "ami-id", aws.StringValue(latestImage.ImageId))
+	r.scope.V(2).Info("Found and using an existing AMI", "ami-id", aws.StringValue(latestImage.ImageId))
 	return aws.StringValue(latestImage.ImageId), nil
 }
 
+// ssmAMIResolver resolves the AMI ID stored in an SSM Parameter Store parameter.
+type ssmAMIResolver struct {
+	scope *scope.ClusterScope
+}
+
+// ResolveAMI implements AMIResolver.
+func (r *ssmAMIResolver) ResolveAMI(lookup *AMILookup) (string, error) {
+	name, err := formatAMILookup(lookup.SSMParameter, lookup.BaseOS, lookup.KubernetesVersion)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := r.scope.SSM.GetParameter(&ssm.GetParameterInput{
+		Name: aws.String(name),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get AMI ID from SSM parameter %q", name)
+	}
+
+	if out.Parameter == nil || aws.StringValue(out.Parameter.Value) == "" {
+		return "", errors.Errorf("SSM parameter %q does not hold an AMI ID", name)
+	}
+
+	id := strings.TrimSpace(aws.StringValue(out.Parameter.Value))
+	r.scope.V(2).Info("Found and using an AMI from SSM Parameter Store", "ami-id", id, "parameter", name)
+	return id, nil
+}
+
+// instanceTypeArchitectures returns the processor architectures supported by an instance type.
+func (s *Service) instanceTypeArchitectures(instanceType string) ([]string, error) {
+	out, err := s.scope.EC2.DescribeInstanceTypes(&ec2.DescribeInstanceTypesInput{
+		InstanceTypes: aws.StringSlice([]string{instanceType}),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe instance type %q", instanceType)
+	}
+
+	if len(out.InstanceTypes) == 0 || out.InstanceTypes[0].ProcessorInfo == nil {
+		return nil, errors.Errorf("no processor information found for instance type %q", instanceType)
+	}
+
+	return aws.StringValueSlice(out.InstanceTypes[0].ProcessorInfo.SupportedArchitectures), nil
+}
+
+// imageArchitecture returns the processor architecture of an AMI.
+func (s *Service) imageArchitecture(imageID string) (string, error) {
+	out, err := s.scope.EC2.DescribeImages(&ec2.DescribeImagesInput{
+		ImageIds: aws.StringSlice([]string{imageID}),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe image %q", imageID)
+	}
+
+	if len(out.Images) == 0 {
+		return "", errors.Errorf("no images returned when looking up ID %q", imageID)
+	}
+
+	return aws.StringValue(out.Images[0].Architecture), nil
+}
+
+// preferredArchitecture returns the architecture to look up AMIs for, given the
+// architectures supported by an instance type.
+func preferredArchitecture(architectures []string) string {
+	for _, arch := range architectures {
+		if arch == defaultArchitecture {
+			return arch
+		}
+	}
+
+	for _, arch := range architectures {
+		if arch == ec2.ArchitectureTypeArm64 {
+			return arch
+		}
+	}
+
+	return defaultArchitecture
+}
+
+// supportsArchitecture returns true if the architecture is in the list of supported architectures.
+func supportsArchitecture(architectures []string, architecture string) bool {
+	for _, arch := range architectures {
+		if arch == architecture {
+			return true
+		}
+	}
+	return false
+}
+
 type images []*ec2.Image
 
 // Len is the number of elements in the collection.
@@ -133,42 +308,3 @@ func getLatestImage(imgs []*ec2.Image) (*ec2.Image, error) {
 	sort.Sort(images(imgs))
 	return imgs[len(imgs)-1], nil
 }
-
-func (s *Service) defaultBastionAMILookup(region string) string {
-	switch region {
-	case "ap-northeast-1":
-		return "ami-d39a02b5"
-	case "ap-northeast-2":
-		return "ami-67973709"
-	case "ap-south-1":
-		return "ami-5d055232"
-	case "ap-southeast-1":
-		return "ami-325d2e4e"
-	case "ap-southeast-2":
-		return "ami-37df2255"
-	case "ca-central-1":
-		return "ami-f0870294"
-	case "eu-central-1":
-		return "ami-af79ebc0"
-	case "eu-west-1":
-		return "ami-4d46d534"
-	case "eu-west-2":
-		return "ami-d7aab2b3"
-	case "eu-west-3":
-		return "ami-5e0eb923"
-	case "sa-east-1":
-		return "ami-1157157d"
-	case "us-east-1":
-		return "ami-41e0b93b"
-	case "us-east-2":
-		return "ami-2581aa40"
-	case "us-west-1":
-		return "ami-79aeae19"
-	case "us-west-2":
-		return "ami-1ee65166"
-	case "eu-north-1":
-		return "ami-7dd85203"
-	default:
-		return "unknown region"
-	}
-}
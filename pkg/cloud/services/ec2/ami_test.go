@@ -23,9 +23,11 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/golang/mock/gomock"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ssmiface"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 )
 
@@ -79,7 +81,7 @@ func TestAMIs(t *testing.T) {
 			tc.expect(ec2Mock.EXPECT())
 
 			s := NewService(scope)
-			id, err := s.defaultAMILookup("", "base os-baseos version", "1.11.1")
+			id, err := s.lookupAMI(&AMILookup{BaseOS: "base os-baseos version", KubernetesVersion: "1.11.1"})
 			if err != nil {
 				t.Fatalf("did not expect error calling a mock: %v", err)
 			}
@@ -140,10 +142,166 @@ func TestAMIsWithInvalidCreationDate(t *testing.T) {
 			tc.expect(ec2Mock.EXPECT())
 
 			s := NewService(scope)
-			_, err = s.defaultAMILookup("", "base os-baseos version", "1.11.1")
+			_, err = s.lookupAMI(&AMILookup{BaseOS: "base os-baseos version", KubernetesVersion: "1.11.1"})
 			if err == nil {
 				t.Fatalf("expected an error but did not get one")
 			}
 		})
 	}
 }
+
+func TestAMIResolvers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name      string
+		lookup    *AMILookup
+		expectEC2 func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectSSM func(m *mock_ssmiface.MockSSMAPIMockRecorder)
+		expectID  string
+	}{
+		{
+			name: "custom name format",
+			lookup: &AMILookup{
+				OwnerID:           "test-org-123",
+				BaseOS:            "centos-7",
+				KubernetesVersion: "v1.17.3",
+				NameFormat:        "hardened-{{.BaseOS}}-k8s-{{.K8sVersion}}-*",
+			},
+			expectEC2: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
+					Filters: []*ec2.Filter{
+						{
+							Name:   aws.String("owner-id"),
+							Values: []*string{aws.String("test-org-123")},
+						},
+						{
+							Name:   aws.String("name"),
+							Values: []*string{aws.String("hardened-centos-7-k8s-1.17.3-*")},
+						},
+						{
+							Name:   aws.String("architecture"),
+							Values: []*string{aws.String("x86_64")},
+						},
+						{
+							Name:   aws.String("state"),
+							Values: []*string{aws.String("available")},
+						},
+						{
+							Name:   aws.String("virtualization-type"),
+							Values: []*string{aws.String("hvm")},
+						},
+					},
+				})).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								ImageId:      aws.String("ami-hardened"),
+								CreationDate: aws.String("2019-02-08T17:02:31.000Z"),
+							},
+						},
+					}, nil)
+			},
+			expectSSM: func(m *mock_ssmiface.MockSSMAPIMockRecorder) {},
+			expectID:  "ami-hardened",
+		},
+		{
+			name: "SSM parameter",
+			lookup: &AMILookup{
+				KubernetesVersion: "v1.17.3",
+				NameFormat:        "ignored-{{.K8sVersion}}",
+				SSMParameter:      "/images/{{.BaseOS}}/{{.K8sVersion}}/image_id",
+			},
+			expectEC2: func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+			expectSSM: func(m *mock_ssmiface.MockSSMAPIMockRecorder) {
+				m.GetParameter(gomock.Eq(&ssm.GetParameterInput{
+					Name: aws.String("/images/ubuntu-18.04/1.17.3/image_id"),
+				})).
+					Return(&ssm.GetParameterOutput{
+						Parameter: &ssm.Parameter{
+							Value: aws.String("ami-from-ssm"),
+						},
+					}, nil)
+			},
+			expectID: "ami-from-ssm",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+			ssmMock := mock_ssmiface.NewMockSSMAPI(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{},
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+					SSM: ssmMock,
+				},
+			})
+			if err != nil {
+				t.Fatalf("did not expect err: %v", err)
+			}
+
+			tc.expectEC2(ec2Mock.EXPECT())
+			tc.expectSSM(ssmMock.EXPECT())
+
+			s := NewService(scope)
+			id, err := s.lookupAMI(tc.lookup)
+			if err != nil {
+				t.Fatalf("did not expect error calling a mock: %v", err)
+			}
+			if id != tc.expectID {
+				t.Fatalf("returned %q expected %q", id, tc.expectID)
+			}
+		})
+	}
+}
+
+func TestLookupAMIDefaults(t *testing.T) {
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster:    &clusterv1.Cluster{},
+		AWSCluster: &infrav1.AWSCluster{},
+	})
+	if err != nil {
+		t.Fatalf("did not expect err: %v", err)
+	}
+
+	var resolved *AMILookup
+	s := NewService(scope)
+	s.amiResolverFactory = func(*AMILookup) AMIResolver {
+		return fakeAMIResolver(func(lookup *AMILookup) (string, error) {
+			resolved = lookup
+			return "ami-1", nil
+		})
+	}
+
+	id, err := s.lookupAMI(&AMILookup{KubernetesVersion: "v1.17.3"})
+	if err != nil {
+		t.Fatalf("did not expect err: %v", err)
+	}
+	if id != "ami-1" {
+		t.Fatalf("returned %q expected 'ami-1'", id)
+	}
+
+	expected := &AMILookup{
+		OwnerID:           defaultMachineAMIOwnerID,
+		BaseOS:            defaultMachineAMILookupBaseOS,
+		Architecture:      defaultArchitecture,
+		KubernetesVersion: "v1.17.3",
+		NameFormat:        defaultAMINameFormat,
+	}
+	if *resolved != *expected {
+		t.Fatalf("expected lookup %+v, got %+v", expected, resolved)
+	}
+}
+
+// fakeAMIResolver resolves AMIs with a function instead of calling AWS.
+type fakeAMIResolver func(lookup *AMILookup) (string, error)
+
+// ResolveAMI implements AMIResolver.
+func (f fakeAMIResolver) ResolveAMI(lookup *AMILookup) (string, error) {
+	return f(lookup)
+}
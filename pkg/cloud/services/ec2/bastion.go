@@ -19,21 +19,37 @@ package ec2
 import (
 	"encoding/base64"
 	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
-	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/userdata"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
 )
 
 const (
 	defaultSSHKeyName = "default"
+
+	// defaultBastionInstanceType is the instance type of the bastion host when none is configured.
+	defaultBastionInstanceType = "t2.micro"
+
+	// defaultBastionAMISSMParameterFormat is the public SSM parameter holding the ID of the latest
+	// Ubuntu 18.04 AMI published by Canonical in the region for an architecture, which the bastion
+	// user data expects.
+	defaultBastionAMISSMParameterFormat = "/aws/service/canonical/ubuntu/server/18.04/stable/current/%s/hvm/ebs-gp2/ami-id"
 )
 
+// ubuntuArchitectures maps EC2 architectures to the ones used in the names of Ubuntu images.
+var ubuntuArchitectures = map[string]string{
+	ec2.ArchitectureTypeX8664: "amd64",
+	ec2.ArchitectureTypeArm64: "arm64",
+}
+
 // ReconcileBastion ensures a bastion is created for the cluster
 func (s *Service) ReconcileBastion() error {
 	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
@@ -41,7 +57,8 @@ func (s *Service) ReconcileBastion() error {
 		return nil
 	}
 
-	if !s.scope.AWSCluster.Spec.Bastion.Enabled {
+	// There is no bastion host when machines are accessed through Session Manager.
+	if !s.scope.AWSCluster.Spec.Bastion.Enabled || s.scope.AccessMode() == infrav1.ClusterAccessModeSessionManager {
 		if s.scope.AWSCluster.Status.Bastion != nil {
 			return s.DeleteBastion()
 		}
@@ -58,12 +75,37 @@ func (s *Service) ReconcileBastion() error {
 		return errors.New("failed to reconcile bastion host, no public subnets are available")
 	}
 
-	spec := s.getDefaultBastion()
+	desired := s.getDefaultBastion()
+
+	// Describe bastion instances, if any.
+	instances, err := s.describeBastionInstances()
+	if err != nil {
+		return err
+	}
+
+	// Keep the first bastion matching the desired spec, and replace the others.
+	var instance *infrav1.Instance
+	var outdated []*infrav1.Instance
+	for _, i := range instances {
+		if diff := s.bastionDiff(i, desired); instance == nil && len(diff) == 0 {
+			instance = i
+		} else {
+			if len(diff) > 0 {
+				s.scope.Info("Bastion host differs from the desired spec", "instance-id", i.ID, "fields", diff)
+			}
+			outdated = append(outdated, i)
+		}
+	}
+
+	// Create the new bastion before terminating the outdated ones, so that access is not lost.
+	if instance == nil {
+		desired.ImageID, err = s.bastionAMI(desired.Type)
+		if err != nil {
+			record.Warnf(s.scope.AWSCluster, "FailedCreateBastion", "Failed to create bastion instance: %v", err)
+			return err
+		}
 
-	// Describe bastion instance, if any.
-	instance, err := s.describeBastionInstance()
-	if awserrors.IsNotFound(err) {
-		instance, err = s.runInstance("bastion", spec)
+		instance, err = s.runInstance("bastion", desired)
 		if err != nil {
 			record.Warnf(s.scope.AWSCluster, "FailedCreateBastion", "Failed to create bastion instance: %v", err)
 			return err
@@ -71,44 +113,90 @@ func (s *Service) ReconcileBastion() error {
 
 		record.Eventf(s.scope.AWSCluster, "SuccessfulCreateBastion", "Created bastion instance %q", instance.ID)
 		s.scope.V(2).Info("Created new bastion host", "instance", instance)
+	}
+
+	// Record the bastion in use before terminating the outdated ones, so that the status stays
+	// accurate if that fails.
+	s.scope.AWSCluster.Status.Bastion = instance.DeepCopy()
+
+	// Only terminate the outdated bastions once the one in use can be reached.
+	if len(outdated) > 0 && instance.State != infrav1.InstanceStateRunning {
+		s.scope.V(2).Info("Waiting for bastion host to be running before terminating outdated ones", "instance-id", instance.ID)
+		if err := s.scope.EC2.WaitUntilInstanceRunning(&ec2.DescribeInstancesInput{
+			InstanceIds: aws.StringSlice([]string{instance.ID}),
+		}); err != nil {
+			return errors.Wrapf(err, "failed to wait for bastion instance %q to be running", instance.ID)
+		}
+	}
+
+	for _, i := range outdated {
+		if err := s.TerminateInstance(i.ID); err != nil {
+			record.Warnf(s.scope.AWSCluster, "FailedTerminateBastion", "Failed to terminate bastion instance %q: %v", i.ID, err)
+			return errors.Wrap(err, "unable to delete outdated bastion instance")
+		}
+		record.Eventf(s.scope.AWSCluster, "SuccessfulTerminateBastion", "Terminated bastion instance %q, replaced by %q", i.ID, instance.ID)
+	}
 
-	} else if err != nil {
+	// Make sure tags are up to date, as they are only set on the bastion when it is created.
+	if err := tags.Ensure(instance.Tags, &tags.ApplyParams{
+		EC2Client:   s.scope.EC2,
+		BuildParams: s.getBastionTagParams(instance.ID),
+	}); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedTagBastion", "Failed to update tags of bastion instance %q: %v", instance.ID, err)
 		return err
 	}
+	if instance.Tags == nil {
+		instance.Tags = map[string]string{}
+	}
+	for k, v := range desired.Tags {
+		instance.Tags[k] = v
+	}
 
-	// TODO(vincepri): check for possible changes between the default spec and the instance.
+	updated, err := s.UpdateInstanceMetadataOptions(instance, s.scope.AWSCluster.Spec.Bastion.InstanceMetadataOptions)
+	if err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedUpdateBastionMetadataOptions", "Failed to update metadata options of bastion instance %q: %v", instance.ID, err)
+		return err
+	}
+	if updated {
+		record.Eventf(s.scope.AWSCluster, "SuccessfulUpdateBastionMetadataOptions", "Updated metadata options of bastion instance %q", instance.ID)
+	}
 
 	s.scope.AWSCluster.Status.Bastion = instance.DeepCopy()
 	s.scope.V(2).Info("Reconcile bastion completed successfully")
 	return nil
 }
 
-// DeleteBastion deletes the Bastion instance
+// DeleteBastion deletes the Bastion instances
 func (s *Service) DeleteBastion() error {
 	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
 		s.scope.V(4).Info("Skipping bastion deletion in unmanaged mode")
 		return nil
 	}
 
-	instance, err := s.describeBastionInstance()
+	instances, err := s.describeBastionInstances()
 	if err != nil {
-		if awserrors.IsNotFound(err) {
-			s.scope.V(4).Info("bastion instance does not exist")
-			return nil
-		}
 		return errors.Wrap(err, "unable to describe bastion instance")
 	}
+	if len(instances) == 0 {
+		s.scope.V(4).Info("bastion instance does not exist")
+		s.scope.AWSCluster.Status.Bastion = nil
+		return nil
+	}
 
-	if err := s.TerminateInstanceAndWait(instance.ID); err != nil {
-		record.Warnf(s.scope.AWSCluster, "FailedTerminateBastion", "Failed to terminate bastion instance %q: %v", instance.ID, err)
-		return errors.Wrap(err, "unable to delete bastion instance")
+	for _, instance := range instances {
+		if err := s.TerminateInstanceAndWait(instance.ID); err != nil {
+			record.Warnf(s.scope.AWSCluster, "FailedTerminateBastion", "Failed to terminate bastion instance %q: %v", instance.ID, err)
+			return errors.Wrap(err, "unable to delete bastion instance")
+		}
+		record.Eventf(s.scope.AWSCluster, "SuccessfulTerminateBastion", "Terminated bastion instance %q", instance.ID)
 	}
-	record.Eventf(s.scope.AWSCluster, "SuccessfulTerminateBastion", "Terminated bastion instance %q", instance.ID)
+	s.scope.AWSCluster.Status.Bastion = nil
 
 	return nil
 }
 
-func (s *Service) describeBastionInstance() (*infrav1.Instance, error) {
+// describeBastionInstances returns the pending and running bastion instances of the cluster.
+func (s *Service) describeBastionInstances() ([]*infrav1.Instance, error) {
 	input := &ec2.DescribeInstancesInput{
 		Filters: []*ec2.Filter{
 			filter.EC2.ProviderRole(infrav1.BastionRoleTagValue),
@@ -122,46 +210,126 @@ func (s *Service) describeBastionInstance() (*infrav1.Instance, error) {
 		return nil, errors.Wrap(err, "failed to describe bastion host")
 	}
 
-	// TODO: properly handle multiple bastions found rather than just returning
-	// the first non-terminated.
+	var instances []*infrav1.Instance
 	for _, res := range out.Reservations {
 		for _, instance := range res.Instances {
-			if aws.StringValue(instance.State.Name) != ec2.InstanceStateNameTerminated {
-				return s.SDKToInstance(instance)
+			if aws.StringValue(instance.State.Name) == ec2.InstanceStateNameTerminated {
+				continue
 			}
+			i, err := s.SDKToInstance(instance)
+			if err != nil {
+				return nil, err
+			}
+			instances = append(instances, i)
 		}
 	}
 
-	return nil, awserrors.NewNotFound(errors.New("bastion host not found"))
+	return instances, nil
 }
 
+// bastionDiff returns the fields of a bastion instance that differ from the desired one. The AMI
+// is only compared when it is set in the desired instance, as a looked up AMI changes over time,
+// and the subnet only has to be one of the public subnets of the cluster.
+func (s *Service) bastionDiff(instance *infrav1.Instance, desired *infrav1.Instance) []string {
+	var diff []string
+
+	if instance.Type != desired.Type {
+		diff = append(diff, "instanceType")
+	}
+	if desired.ImageID != "" && instance.ImageID != desired.ImageID {
+		diff = append(diff, "ami")
+	}
+	if aws.StringValue(instance.SSHKeyName) != aws.StringValue(desired.SSHKeyName) {
+		diff = append(diff, "sshKeyName")
+	}
+	if s.scope.Subnets().FilterPublic().FindByID(instance.SubnetID) == nil {
+		diff = append(diff, "subnet")
+	}
+	if !sets.NewString(instance.SecurityGroupIDs...).Equal(sets.NewString(desired.SecurityGroupIDs...)) {
+		diff = append(diff, "securityGroups")
+	}
+
+	return diff
+}
+
+// getDefaultBastion returns the desired bastion instance. Its image ID is only set when the
+// AMI is configured, see bastionAMI.
 func (s *Service) getDefaultBastion() *infrav1.Instance {
-	name := fmt.Sprintf("%s-bastion", s.scope.Name())
 	userData, _ := userdata.NewBastion(&userdata.BastionInput{})
+	bastion := s.scope.AWSCluster.Spec.Bastion
 
-	// If SSHKeyName WAS NOT provided, use the defaultSSHKeyName
-	keyName := s.scope.AWSCluster.Spec.SSHKeyName
-	if keyName == nil {
-		keyName = aws.String(defaultSSHKeyName)
+	instanceType := bastion.InstanceType
+	if instanceType == "" {
+		instanceType = defaultBastionInstanceType
 	}
 
 	i := &infrav1.Instance{
-		Type:       "t2.micro",
-		SubnetID:   s.scope.Subnets().FilterPublic()[0].ID,
-		ImageID:    s.defaultBastionAMILookup(s.scope.AWSCluster.Spec.Region),
-		SSHKeyName: keyName,
-		UserData:   aws.String(base64.StdEncoding.EncodeToString([]byte(userData))),
+		Type:                    instanceType,
+		SubnetID:                s.scope.Subnets().FilterPublic()[0].ID,
+		ImageID:                 aws.StringValue(bastion.AMI.ID),
+		SSHKeyName:              s.sshKeyName(nil),
+		UserData:                aws.String(base64.StdEncoding.EncodeToString([]byte(userData))),
+		InstanceMetadataOptions: bastion.InstanceMetadataOptions,
 		SecurityGroupIDs: []string{
 			s.scope.Network().SecurityGroups[infrav1.SecurityGroupBastion].ID,
 		},
-		Tags: infrav1.Build(infrav1.BuildParams{
-			ClusterName: s.scope.Name(),
-			Lifecycle:   infrav1.ResourceLifecycleOwned,
-			Name:        aws.String(name),
-			Role:        aws.String(infrav1.BastionRoleTagValue),
-			Additional:  s.scope.AdditionalTags(),
-		}),
+		Tags: infrav1.Build(s.getBastionTagParams("")),
 	}
 
 	return i
 }
+
+// getBastionTagParams returns the tags of the bastion host, including the additional tags
+// of the cluster and of the bastion.
+func (s *Service) getBastionTagParams(id string) infrav1.BuildParams {
+	additionalTags := s.scope.AdditionalTags()
+	additionalTags.Merge(s.scope.AWSCluster.Spec.Bastion.AdditionalTags)
+
+	return infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		ResourceID:  id,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(fmt.Sprintf("%s-bastion", s.scope.Name())),
+		Role:        aws.String(infrav1.BastionRoleTagValue),
+		Additional:  additionalTags,
+	}
+}
+
+// bastionAMI returns the ID of the AMI the bastion host is launched from, looking it up
+// in SSM Parameter Store unless one is set. The AMI must run on the instance type.
+func (s *Service) bastionAMI(instanceType string) (string, error) {
+	architectures, err := s.instanceTypeArchitectures(instanceType)
+	if err != nil {
+		return "", err
+	}
+
+	bastion := s.scope.AWSCluster.Spec.Bastion
+	id := aws.StringValue(bastion.AMI.ID)
+	if id == "" {
+		parameter := bastion.ImageLookupSSMParameter
+		if parameter == "" {
+			arch := preferredArchitecture(architectures)
+			ubuntuArch, ok := ubuntuArchitectures[arch]
+			if !ok {
+				return "", errors.Errorf("no default bastion AMI for architecture %q of instance type %q", arch, instanceType)
+			}
+			parameter = fmt.Sprintf(defaultBastionAMISSMParameterFormat, ubuntuArch)
+		}
+
+		id, err = s.lookupAMI(&AMILookup{SSMParameter: parameter})
+		if err != nil {
+			return "", errors.Wrap(err, "failed to look up bastion AMI")
+		}
+	}
+
+	imageArchitecture, err := s.imageArchitecture(id)
+	if err != nil {
+		return "", err
+	}
+	if !supportsArchitecture(architectures, imageArchitecture) {
+		return "", errors.Errorf("bastion AMI %q has architecture %q, which is not supported by instance type %q (supported: %s)",
+			id, imageArchitecture, instanceType, strings.Join(architectures, ", "))
+	}
+
+	return id, nil
+}
@@ -0,0 +1,446 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ssmiface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+func TestBastionAMI(t *testing.T) {
+	describeInstanceType := func(m *mock_ec2iface.MockEC2APIMockRecorder, instanceType, arch string) {
+		m.DescribeInstanceTypes(gomock.Eq(&ec2.DescribeInstanceTypesInput{
+			InstanceTypes: aws.StringSlice([]string{instanceType}),
+		})).
+			Return(&ec2.DescribeInstanceTypesOutput{
+				InstanceTypes: []*ec2.InstanceTypeInfo{
+					{
+						ProcessorInfo: &ec2.ProcessorInfo{
+							SupportedArchitectures: aws.StringSlice([]string{arch}),
+						},
+					},
+				},
+			}, nil)
+	}
+	describeImage := func(m *mock_ec2iface.MockEC2APIMockRecorder, id, arch string) {
+		m.DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
+			ImageIds: aws.StringSlice([]string{id}),
+		})).
+			Return(&ec2.DescribeImagesOutput{
+				Images: []*ec2.Image{{ImageId: aws.String(id), Architecture: aws.String(arch)}},
+			}, nil)
+	}
+
+	testCases := []struct {
+		name         string
+		bastion      infrav1.Bastion
+		instanceType string
+		expectEC2    func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectSSM    func(m *mock_ssmiface.MockSSMAPIMockRecorder)
+		expectID     string
+		expectErr    bool
+	}{
+		{
+			name:         "uses the configured AMI",
+			bastion:      infrav1.Bastion{AMI: infrav1.AWSResourceReference{ID: aws.String("ami-bastion")}},
+			instanceType: "t2.micro",
+			expectEC2: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeInstanceType(m, "t2.micro", "x86_64")
+				describeImage(m, "ami-bastion", "x86_64")
+			},
+			expectSSM: func(m *mock_ssmiface.MockSSMAPIMockRecorder) {},
+			expectID:  "ami-bastion",
+		},
+		{
+			name:         "looks up the default SSM parameter",
+			instanceType: "t2.micro",
+			expectEC2: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeInstanceType(m, "t2.micro", "x86_64")
+				describeImage(m, "ami-ubuntu", "x86_64")
+			},
+			expectSSM: func(m *mock_ssmiface.MockSSMAPIMockRecorder) {
+				m.GetParameter(gomock.Eq(&ssm.GetParameterInput{
+					Name: aws.String("/aws/service/canonical/ubuntu/server/18.04/stable/current/amd64/hvm/ebs-gp2/ami-id"),
+				})).
+					Return(&ssm.GetParameterOutput{
+						Parameter: &ssm.Parameter{Value: aws.String("ami-ubuntu")},
+					}, nil)
+			},
+			expectID: "ami-ubuntu",
+		},
+		{
+			name:         "looks up the default SSM parameter of an arm64 instance type",
+			instanceType: "t4g.micro",
+			expectEC2: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeInstanceType(m, "t4g.micro", "arm64")
+				describeImage(m, "ami-ubuntu-arm64", "arm64")
+			},
+			expectSSM: func(m *mock_ssmiface.MockSSMAPIMockRecorder) {
+				m.GetParameter(gomock.Eq(&ssm.GetParameterInput{
+					Name: aws.String("/aws/service/canonical/ubuntu/server/18.04/stable/current/arm64/hvm/ebs-gp2/ami-id"),
+				})).
+					Return(&ssm.GetParameterOutput{
+						Parameter: &ssm.Parameter{Value: aws.String("ami-ubuntu-arm64")},
+					}, nil)
+			},
+			expectID: "ami-ubuntu-arm64",
+		},
+		{
+			name:         "looks up the configured SSM parameter",
+			bastion:      infrav1.Bastion{ImageLookupSSMParameter: "/bastion/ami"},
+			instanceType: "t2.micro",
+			expectEC2: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeInstanceType(m, "t2.micro", "x86_64")
+				describeImage(m, "ami-custom", "x86_64")
+			},
+			expectSSM: func(m *mock_ssmiface.MockSSMAPIMockRecorder) {
+				m.GetParameter(gomock.Eq(&ssm.GetParameterInput{
+					Name: aws.String("/bastion/ami"),
+				})).
+					Return(&ssm.GetParameterOutput{
+						Parameter: &ssm.Parameter{Value: aws.String("ami-custom")},
+					}, nil)
+			},
+			expectID: "ami-custom",
+		},
+		{
+			name:         "rejects an AMI that does not match the instance type architecture",
+			bastion:      infrav1.Bastion{AMI: infrav1.AWSResourceReference{ID: aws.String("ami-bastion")}},
+			instanceType: "t4g.micro",
+			expectEC2: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeInstanceType(m, "t4g.micro", "arm64")
+				describeImage(m, "ami-bastion", "x86_64")
+			},
+			expectSSM: func(m *mock_ssmiface.MockSSMAPIMockRecorder) {},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+			ssmMock := mock_ssmiface.NewMockSSMAPI(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{Bastion: tc.bastion},
+				},
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+					SSM: ssmMock,
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expectEC2(ec2Mock.EXPECT())
+			tc.expectSSM(ssmMock.EXPECT())
+
+			s := NewService(scope)
+			id, err := s.bastionAMI(tc.instanceType)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			if id != tc.expectID {
+				t.Fatalf("returned %q expected %q", id, tc.expectID)
+			}
+		})
+	}
+}
+
+func TestReconcileBastion(t *testing.T) {
+	bastionInstance := func(id, instanceType string) *ec2.Instance {
+		return &ec2.Instance{
+			InstanceId:     aws.String(id),
+			InstanceType:   aws.String(instanceType),
+			ImageId:        aws.String("ami-ubuntu"),
+			KeyName:        aws.String(defaultSSHKeyName),
+			SubnetId:       aws.String("subnet-public"),
+			State:          &ec2.InstanceState{Name: aws.String(ec2.InstanceStateNameRunning)},
+			SecurityGroups: []*ec2.GroupIdentifier{{GroupId: aws.String("sg-bastion")}},
+			Tags: []*ec2.Tag{
+				{Key: aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"), Value: aws.String("owned")},
+				{Key: aws.String("sigs.k8s.io/cluster-api-provider-aws/role"), Value: aws.String("bastion")},
+				{Key: aws.String("Name"), Value: aws.String("test-cluster-bastion")},
+			},
+		}
+	}
+	pendingBastionInstance := func(id string) *ec2.Instance {
+		i := bastionInstance(id, defaultBastionInstanceType)
+		i.State = &ec2.InstanceState{Name: aws.String(ec2.InstanceStateNamePending)}
+		return i
+	}
+	describeBastions := func(m *mock_ec2iface.MockEC2APIMockRecorder, instances ...*ec2.Instance) {
+		m.DescribeInstances(gomock.AssignableToTypeOf(&ec2.DescribeInstancesInput{})).
+			Return(&ec2.DescribeInstancesOutput{
+				Reservations: []*ec2.Reservation{{Instances: instances}},
+			}, nil)
+	}
+
+	testCases := []struct {
+		name      string
+		bastion   infrav1.Bastion
+		expect    func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectSSM func(m *mock_ssmiface.MockSSMAPIMockRecorder)
+		expectErr bool
+		expectID  string
+	}{
+		{
+			name: "bastion matches the desired spec",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeBastions(m, bastionInstance("i-1", defaultBastionInstanceType))
+			},
+			expectSSM: func(m *mock_ssmiface.MockSSMAPIMockRecorder) {},
+			expectID:  "i-1",
+		},
+		{
+			name:    "additional tags are applied to the existing bastion",
+			bastion: infrav1.Bastion{AdditionalTags: infrav1.Tags{"team": "platform"}},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeBastions(m, bastionInstance("i-1", defaultBastionInstanceType))
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Do(func(input *ec2.CreateTagsInput) {
+						if got := aws.StringValueSlice(input.Resources); len(got) != 1 || got[0] != "i-1" {
+							t.Fatalf("expected the tags to be applied to the bastion instance, got %v", got)
+						}
+						for _, tag := range input.Tags {
+							if aws.StringValue(tag.Key) == "team" && aws.StringValue(tag.Value) == "platform" {
+								return
+							}
+						}
+						t.Fatalf("expected the additional tags to be applied, got %v", input.Tags)
+					}).
+					Return(&ec2.CreateTagsOutput{}, nil)
+			},
+			expectSSM: func(m *mock_ssmiface.MockSSMAPIMockRecorder) {},
+			expectID:  "i-1",
+		},
+		{
+			name: "extra bastions are terminated",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeBastions(m,
+					bastionInstance("i-1", defaultBastionInstanceType),
+					bastionInstance("i-2", defaultBastionInstanceType),
+				)
+				m.TerminateInstances(gomock.Eq(&ec2.TerminateInstancesInput{
+					InstanceIds: aws.StringSlice([]string{"i-2"}),
+				})).
+					Return(&ec2.TerminateInstancesOutput{}, nil)
+			},
+			expectSSM: func(m *mock_ssmiface.MockSSMAPIMockRecorder) {},
+			expectID:  "i-1",
+		},
+		{
+			name: "bastion differing from the desired spec is replaced",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeBastions(m, bastionInstance("i-1", "t3.large"))
+				m.DescribeInstanceTypes(gomock.AssignableToTypeOf(&ec2.DescribeInstanceTypesInput{})).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
+							},
+						},
+					}, nil)
+				m.DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
+					ImageIds: aws.StringSlice([]string{"ami-ubuntu"}),
+				})).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{{ImageId: aws.String("ami-ubuntu"), Architecture: aws.String("x86_64")}},
+					}, nil)
+				m.RunInstances(gomock.AssignableToTypeOf(&ec2.RunInstancesInput{})).
+					Return(&ec2.Reservation{
+						Instances: []*ec2.Instance{pendingBastionInstance("i-2")},
+					}, nil)
+				gomock.InOrder(
+					m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+						Return(nil),
+					m.WaitUntilInstanceRunning(gomock.Eq(&ec2.DescribeInstancesInput{
+						InstanceIds: aws.StringSlice([]string{"i-2"}),
+					})).
+						Return(nil),
+					m.TerminateInstances(gomock.Eq(&ec2.TerminateInstancesInput{
+						InstanceIds: aws.StringSlice([]string{"i-1"}),
+					})).
+						Return(&ec2.TerminateInstancesOutput{}, nil),
+				)
+			},
+			expectSSM: func(m *mock_ssmiface.MockSSMAPIMockRecorder) {
+				m.GetParameter(gomock.AssignableToTypeOf(&ssm.GetParameterInput{})).
+					Return(&ssm.GetParameterOutput{
+						Parameter: &ssm.Parameter{Value: aws.String("ami-ubuntu")},
+					}, nil)
+			},
+			expectID: "i-2",
+		},
+		{
+			name: "outdated bastion is kept until the replacement is running",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeBastions(m,
+					bastionInstance("i-1", "t3.large"),
+					pendingBastionInstance("i-2"),
+				)
+				m.WaitUntilInstanceRunning(gomock.Eq(&ec2.DescribeInstancesInput{
+					InstanceIds: aws.StringSlice([]string{"i-2"}),
+				})).
+					Return(errors.New("exceeded wait attempts"))
+			},
+			expectSSM: func(m *mock_ssmiface.MockSSMAPIMockRecorder) {},
+			expectErr: true,
+			expectID:  "i-2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+			ssmMock := mock_ssmiface.NewMockSSMAPI(mockCtrl)
+
+			bastion := tc.bastion
+			bastion.Enabled = true
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						Bastion: bastion,
+						NetworkSpec: infrav1.NetworkSpec{
+							Subnets: infrav1.Subnets{
+								{ID: "subnet-private"},
+								{ID: "subnet-public", IsPublic: true},
+							},
+						},
+					},
+					Status: infrav1.AWSClusterStatus{
+						Network: infrav1.Network{
+							SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+								infrav1.SecurityGroupBastion: {ID: "sg-bastion"},
+							},
+						},
+					},
+				},
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+					SSM: ssmMock,
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+			tc.expectSSM(ssmMock.EXPECT())
+
+			s := NewService(scope)
+			err = s.ReconcileBastion()
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error but got none")
+				}
+			} else if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			if scope.AWSCluster.Status.Bastion == nil || scope.AWSCluster.Status.Bastion.ID != tc.expectID {
+				t.Fatalf("expected bastion %q in the cluster status, got %v", tc.expectID, scope.AWSCluster.Status.Bastion)
+			}
+		})
+	}
+}
+
+func TestReconcileBastionSessionManager(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			Spec: infrav1.AWSClusterSpec{
+				Bastion:    infrav1.Bastion{Enabled: true},
+				AccessMode: infrav1.ClusterAccessModeSessionManager,
+			},
+			Status: infrav1.AWSClusterStatus{
+				Bastion: &infrav1.Instance{ID: "i-1"},
+			},
+		},
+		AWSClients: scope.AWSClients{
+			EC2: ec2Mock,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	m := ec2Mock.EXPECT()
+	m.DescribeInstances(gomock.AssignableToTypeOf(&ec2.DescribeInstancesInput{})).
+		Return(&ec2.DescribeInstancesOutput{
+			Reservations: []*ec2.Reservation{{
+				Instances: []*ec2.Instance{{
+					InstanceId: aws.String("i-1"),
+					State:      &ec2.InstanceState{Name: aws.String(ec2.InstanceStateNameRunning)},
+				}},
+			}},
+		}, nil)
+	m.TerminateInstances(gomock.Eq(&ec2.TerminateInstancesInput{
+		InstanceIds: aws.StringSlice([]string{"i-1"}),
+	})).
+		Return(&ec2.TerminateInstancesOutput{}, nil)
+	m.WaitUntilInstanceTerminated(gomock.Any()).
+		Return(nil)
+
+	s := NewService(scope)
+	if err := s.ReconcileBastion(); err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+	if scope.AWSCluster.Status.Bastion != nil {
+		t.Fatalf("expected the bastion to be removed from the cluster status, got %v", scope.AWSCluster.Status.Bastion)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
@@ -47,12 +48,16 @@ func (s *Service) getOrAllocateAddress(role string) (string, error) {
 }
 
 func (s *Service) allocateAddress(role string) (string, error) {
+	return s.allocateNamedAddress(role, fmt.Sprintf("%s-eip-%s", s.scope.Name(), role), s.scope.AdditionalTags())
+}
+
+func (s *Service) allocateNamedAddress(role, name string, additionalTags infrav1.Tags) (string, error) {
 	out, err := s.scope.EC2.AllocateAddress(&ec2.AllocateAddressInput{
 		Domain: aws.String("vpc"),
 	})
 
 	if err != nil {
-		record.Warnf(s.scope.AWSCluster, "FailedAllocateEIP", "Failed to allocate Elastic IP for %q: %v", role, err)
+		record.Warnf(s.scope.AWSCluster, "FailedAllocateEIP", "Failed to allocate Elastic IP for %q: %v", name, err)
 		return "", errors.Wrap(err, "failed to allocate Elastic IP")
 	}
 
@@ -63,9 +68,9 @@ func (s *Service) allocateAddress(role string) (string, error) {
 				ClusterName: s.scope.Name(),
 				ResourceID:  *out.AllocationId,
 				Lifecycle:   infrav1.ResourceLifecycleOwned,
-				Name:        aws.String(fmt.Sprintf("%s-eip-%s", s.scope.Name(), role)),
+				Name:        aws.String(name),
 				Role:        aws.String(role),
-				Additional:  s.scope.AdditionalTags(),
+				Additional:  additionalTags,
 			},
 		}); err != nil {
 			return false, err
@@ -119,36 +124,161 @@ func (s *Service) releaseAddresses() error {
 	}
 
 	for i := range out.Addresses {
-		ip := out.Addresses[i]
-		if ip.AssociationId != nil {
-			_, err := s.scope.EC2.DisassociateAddress(&ec2.DisassociateAddressInput{
-				AssociationId: ip.AssociationId,
-			})
-			if err != nil {
-				record.Warnf(s.scope.AWSCluster, "FailedDisassociateEIP", "Failed to disassociate Elastic IP %q: %v", *ip.AllocationId, err)
-				return errors.Errorf("failed to disassociate Elastic IP %q with allocation ID %q: Still associated with association ID %q", *ip.PublicIp, *ip.AllocationId, *ip.AssociationId)
-			}
+		if err := s.releaseAddress(out.Addresses[i]); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
-			_, err := s.scope.EC2.ReleaseAddress(&ec2.ReleaseAddressInput{AllocationId: ip.AllocationId})
-			if err != nil {
-				if ip.AssociationId != nil {
-					if s.disassociateAddress(ip) != nil {
-						return false, err
-					}
+func (s *Service) releaseAddress(ip *ec2.Address) error {
+	if ip.AssociationId != nil {
+		_, err := s.scope.EC2.DisassociateAddress(&ec2.DisassociateAddressInput{
+			AssociationId: ip.AssociationId,
+		})
+		if err != nil {
+			record.Warnf(s.scope.AWSCluster, "FailedDisassociateEIP", "Failed to disassociate Elastic IP %q: %v", *ip.AllocationId, err)
+			return errors.Errorf("failed to disassociate Elastic IP %q with allocation ID %q: Still associated with association ID %q", *ip.PublicIp, *ip.AllocationId, *ip.AssociationId)
+		}
+	}
+
+	err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		_, err := s.scope.EC2.ReleaseAddress(&ec2.ReleaseAddressInput{AllocationId: ip.AllocationId})
+		if err != nil {
+			if ip.AssociationId != nil {
+				if s.disassociateAddress(ip) != nil {
+					return false, err
 				}
-				return false, err
 			}
+			return false, err
+		}
 
-			return true, nil
-		}, awserrors.AuthFailure, awserrors.InUseIPAddress)
-		if err != nil {
-			record.Warnf(s.scope.AWSCluster, "FailedReleaseEIP", "Failed to disassociate Elastic IP %q: %v", *ip.AllocationId, err)
-			return errors.Wrapf(err, "failed to release ElasticIP %q", *ip.AllocationId)
+		return true, nil
+	}, awserrors.AuthFailure, awserrors.InUseIPAddress)
+	if err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedReleaseEIP", "Failed to disassociate Elastic IP %q: %v", *ip.AllocationId, err)
+		return errors.Wrapf(err, "failed to release ElasticIP %q", *ip.AllocationId)
+	}
+
+	s.scope.Info("released ElasticIP", "eip", *ip.PublicIp, "allocation-id", *ip.AllocationId)
+	return nil
+}
+
+// ReconcileElasticIP associates the Elastic IP address configured for the machine with its instance.
+// If the machine doesn't reference an existing allocation, an address is allocated for it.
+func (s *Service) ReconcileElasticIP(scope *scope.MachineScope, instance *infrav1.Instance) error {
+	ip, err := s.getOrAllocateMachineAddress(scope)
+	if err != nil {
+		return err
+	}
+
+	if ip.AssociationId != nil {
+		if aws.StringValue(ip.InstanceId) == instance.ID {
+			return nil
+		}
+		record.Warnf(scope.AWSMachine, "FailedAssociateEIP", "Elastic IP %q is already associated with instance %q", aws.StringValue(ip.AllocationId), aws.StringValue(ip.InstanceId))
+		return errors.Errorf("Elastic IP %q is already associated with instance %q", aws.StringValue(ip.AllocationId), aws.StringValue(ip.InstanceId))
+	}
+
+	// Associating with the instance fails when it has more than one network interface,
+	// so the address is associated with its primary network interface instead.
+	eni, err := s.getPrimaryNetworkInterfaceID(instance.ID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.scope.EC2.AssociateAddress(&ec2.AssociateAddressInput{
+		AllocationId:       ip.AllocationId,
+		NetworkInterfaceId: aws.String(eni),
+	}); err != nil {
+		record.Warnf(scope.AWSMachine, "FailedAssociateEIP", "Failed to associate Elastic IP %q with instance %q: %v", aws.StringValue(ip.AllocationId), instance.ID, err)
+		return errors.Wrapf(err, "failed to associate Elastic IP %q with instance %q", aws.StringValue(ip.AllocationId), instance.ID)
+	}
+
+	record.Eventf(scope.AWSMachine, "SuccessfulAssociateEIP", "Associated Elastic IP %q with instance %q", aws.StringValue(ip.AllocationId), instance.ID)
+	s.scope.Info("Associated ElasticIP", "allocation-id", aws.StringValue(ip.AllocationId), "instance-id", instance.ID)
+	return nil
+}
+
+// getPrimaryNetworkInterfaceID returns the ID of the network interface of the instance at device index 0.
+func (s *Service) getPrimaryNetworkInterfaceID(instanceID string) (string, error) {
+	enis, err := s.getInstanceENIs(instanceID)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe network interfaces of instance %q", instanceID)
+	}
+
+	for _, eni := range enis {
+		if eni.Attachment != nil && aws.Int64Value(eni.Attachment.DeviceIndex) == 0 {
+			return aws.StringValue(eni.NetworkInterfaceId), nil
 		}
+	}
 
-		s.scope.Info("released ElasticIP", "eip", *ip.PublicIp, "allocation-id", *ip.AllocationId)
+	return "", errors.Errorf("primary network interface of instance %q not found", instanceID)
+}
+
+// ReleaseElasticIP releases the Elastic IP address allocated for the machine, if any.
+func (s *Service) ReleaseElasticIP(scope *scope.MachineScope) error {
+	ip, err := s.describeMachineAddress(scope)
+	if err != nil {
+		return err
 	}
+	if ip == nil {
+		return nil
+	}
+
+	if err := s.releaseAddress(ip); err != nil {
+		return err
+	}
+
+	record.Eventf(scope.AWSMachine, "SuccessfulReleaseEIP", "Released Elastic IP %q", aws.StringValue(ip.AllocationId))
 	return nil
 }
+
+func (s *Service) getOrAllocateMachineAddress(scope *scope.MachineScope) (*ec2.Address, error) {
+	if id := scope.AWSMachine.Spec.ElasticIP.AllocationID; id != nil {
+		out, err := s.scope.EC2.DescribeAddresses(&ec2.DescribeAddressesInput{
+			AllocationIds: []*string{id},
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to describe Elastic IP %q", *id)
+		}
+		if len(out.Addresses) == 0 {
+			return nil, errors.Errorf("Elastic IP %q not found", *id)
+		}
+		return out.Addresses[0], nil
+	}
+
+	ip, err := s.describeMachineAddress(scope)
+	if err != nil {
+		return nil, err
+	}
+	if ip != nil {
+		return ip, nil
+	}
+
+	id, err := s.allocateNamedAddress(scope.Role(), scope.Name(), scope.AdditionalTags())
+	if err != nil {
+		return nil, err
+	}
+	record.Eventf(scope.AWSMachine, "SuccessfulAllocateEIP", "Allocated Elastic IP %q", id)
+
+	return &ec2.Address{AllocationId: aws.String(id)}, nil
+}
+
+// describeMachineAddress returns the Elastic IP address allocated for the machine, or nil if there is none.
+func (s *Service) describeMachineAddress(scope *scope.MachineScope) (*ec2.Address, error) {
+	out, err := s.scope.EC2.DescribeAddresses(&ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.ClusterOwned(s.scope.Name()),
+			filter.EC2.Name(scope.Name()),
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe Elastic IP for machine %q", scope.Name())
+	}
+
+	if len(out.Addresses) == 0 {
+		return nil, nil
+	}
+	return out.Addresses[0], nil
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileElasticIP(t *testing.T) {
+	describeENIs := func(m *mock_ec2iface.MockEC2APIMockRecorder, enis ...*ec2.NetworkInterface) {
+		m.DescribeNetworkInterfaces(gomock.Eq(&ec2.DescribeNetworkInterfacesInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("attachment.instance-id"),
+					Values: aws.StringSlice([]string{"i-1"}),
+				},
+			},
+		})).
+			Return(&ec2.DescribeNetworkInterfacesOutput{NetworkInterfaces: enis}, nil)
+	}
+	eni := func(id string, deviceIndex int64) *ec2.NetworkInterface {
+		return &ec2.NetworkInterface{
+			NetworkInterfaceId: aws.String(id),
+			Attachment:         &ec2.NetworkInterfaceAttachment{DeviceIndex: aws.Int64(deviceIndex)},
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		elasticIP *infrav1.ElasticIP
+		expect    func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectErr bool
+	}{
+		{
+			name:      "allocates and associates a new address",
+			elasticIP: &infrav1.ElasticIP{},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeAddresses(gomock.AssignableToTypeOf(&ec2.DescribeAddressesInput{})).
+					Return(&ec2.DescribeAddressesOutput{}, nil)
+				m.AllocateAddress(gomock.Eq(&ec2.AllocateAddressInput{Domain: aws.String("vpc")})).
+					Return(&ec2.AllocateAddressOutput{AllocationId: aws.String("eipalloc-1")}, nil)
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, nil)
+				describeENIs(m, eni("eni-1", 0))
+				m.AssociateAddress(gomock.Eq(&ec2.AssociateAddressInput{
+					AllocationId:       aws.String("eipalloc-1"),
+					NetworkInterfaceId: aws.String("eni-1"),
+				})).
+					Return(&ec2.AssociateAddressOutput{}, nil)
+			},
+		},
+		{
+			name:      "address allocated for the machine is already associated",
+			elasticIP: &infrav1.ElasticIP{},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeAddresses(gomock.AssignableToTypeOf(&ec2.DescribeAddressesInput{})).
+					Return(&ec2.DescribeAddressesOutput{
+						Addresses: []*ec2.Address{
+							{
+								AllocationId:  aws.String("eipalloc-1"),
+								AssociationId: aws.String("eipassoc-1"),
+								InstanceId:    aws.String("i-1"),
+							},
+						},
+					}, nil)
+			},
+		},
+		{
+			name:      "associates an existing allocation",
+			elasticIP: &infrav1.ElasticIP{AllocationID: aws.String("eipalloc-2")},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeAddresses(gomock.Eq(&ec2.DescribeAddressesInput{
+					AllocationIds: aws.StringSlice([]string{"eipalloc-2"}),
+				})).
+					Return(&ec2.DescribeAddressesOutput{
+						Addresses: []*ec2.Address{
+							{AllocationId: aws.String("eipalloc-2")},
+						},
+					}, nil)
+				describeENIs(m, eni("eni-1", 0))
+				m.AssociateAddress(gomock.Eq(&ec2.AssociateAddressInput{
+					AllocationId:       aws.String("eipalloc-2"),
+					NetworkInterfaceId: aws.String("eni-1"),
+				})).
+					Return(&ec2.AssociateAddressOutput{}, nil)
+			},
+		},
+		{
+			name:      "associates with the primary network interface of an instance with several",
+			elasticIP: &infrav1.ElasticIP{AllocationID: aws.String("eipalloc-2")},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeAddresses(gomock.AssignableToTypeOf(&ec2.DescribeAddressesInput{})).
+					Return(&ec2.DescribeAddressesOutput{
+						Addresses: []*ec2.Address{
+							{AllocationId: aws.String("eipalloc-2")},
+						},
+					}, nil)
+				describeENIs(m, eni("eni-2", 1), eni("eni-1", 0))
+				m.AssociateAddress(gomock.Eq(&ec2.AssociateAddressInput{
+					AllocationId:       aws.String("eipalloc-2"),
+					NetworkInterfaceId: aws.String("eni-1"),
+				})).
+					Return(&ec2.AssociateAddressOutput{}, nil)
+			},
+		},
+		{
+			name:      "primary network interface not found",
+			elasticIP: &infrav1.ElasticIP{AllocationID: aws.String("eipalloc-2")},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeAddresses(gomock.AssignableToTypeOf(&ec2.DescribeAddressesInput{})).
+					Return(&ec2.DescribeAddressesOutput{
+						Addresses: []*ec2.Address{
+							{AllocationId: aws.String("eipalloc-2")},
+						},
+					}, nil)
+				describeENIs(m, eni("eni-2", 1))
+			},
+			expectErr: true,
+		},
+		{
+			name:      "existing allocation is associated with another instance",
+			elasticIP: &infrav1.ElasticIP{AllocationID: aws.String("eipalloc-2")},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeAddresses(gomock.AssignableToTypeOf(&ec2.DescribeAddressesInput{})).
+					Return(&ec2.DescribeAddressesOutput{
+						Addresses: []*ec2.Address{
+							{
+								AllocationId:  aws.String("eipalloc-2"),
+								AssociationId: aws.String("eipassoc-2"),
+								InstanceId:    aws.String("i-2"),
+							},
+						},
+					}, nil)
+			},
+			expectErr: true,
+		},
+		{
+			name:      "existing allocation does not exist",
+			elasticIP: &infrav1.ElasticIP{AllocationID: aws.String("eipalloc-2")},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeAddresses(gomock.AssignableToTypeOf(&ec2.DescribeAddressesInput{})).
+					Return(&ec2.DescribeAddressesOutput{}, nil)
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+			clusterScope, machineScope := newMachineTestScopes(t, ec2Mock, infrav1.AWSMachineSpec{ElasticIP: tc.elasticIP})
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(clusterScope)
+			err := s.ReconcileElasticIP(machineScope, &infrav1.Instance{ID: "i-1"})
+			if tc.expectErr && err == nil {
+				t.Fatal("expected an error")
+			}
+			if !tc.expectErr && err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestReleaseElasticIP(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+	clusterScope, machineScope := newMachineTestScopes(t, ec2Mock, infrav1.AWSMachineSpec{ElasticIP: &infrav1.ElasticIP{}})
+
+	m := ec2Mock.EXPECT()
+	m.DescribeAddresses(gomock.AssignableToTypeOf(&ec2.DescribeAddressesInput{})).
+		Return(&ec2.DescribeAddressesOutput{
+			Addresses: []*ec2.Address{
+				{
+					AllocationId:  aws.String("eipalloc-1"),
+					AssociationId: aws.String("eipassoc-1"),
+					PublicIp:      aws.String("1.2.3.4"),
+				},
+			},
+		}, nil)
+	m.DisassociateAddress(gomock.Eq(&ec2.DisassociateAddressInput{AssociationId: aws.String("eipassoc-1")})).
+		Return(&ec2.DisassociateAddressOutput{}, nil)
+	m.ReleaseAddress(gomock.Eq(&ec2.ReleaseAddressInput{AllocationId: aws.String("eipalloc-1")})).
+		Return(&ec2.ReleaseAddressOutput{}, nil)
+
+	s := NewService(clusterScope)
+	if err := s.ReleaseElasticIP(machineScope); err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+}
+
+// newMachineTestScopes returns the cluster and machine scopes of a machine with the given spec.
+func newMachineTestScopes(t *testing.T, ec2Mock *mock_ec2iface.MockEC2API, spec infrav1.AWSMachineSpec) (*scope.ClusterScope, *scope.MachineScope) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-machine"},
+	}
+	awsCluster := &infrav1.AWSCluster{}
+	awsMachine := &infrav1.AWSMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-machine"},
+		Spec:       spec,
+	}
+
+	client := fake.NewFakeClient(cluster, machine)
+
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Client: client,
+		AWSClients: scope.AWSClients{
+			EC2: ec2Mock,
+		},
+		Cluster:    cluster,
+		AWSCluster: awsCluster,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client: client,
+		AWSClients: scope.AWSClients{
+			EC2: ec2Mock,
+		},
+		Cluster:    cluster,
+		Machine:    machine,
+		AWSMachine: awsMachine,
+		AWSCluster: awsCluster,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	return clusterScope, machineScope
+}
@@ -23,11 +23,13 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
@@ -39,6 +41,12 @@ import (
 	capierrors "sigs.k8s.io/cluster-api/errors"
 )
 
+const (
+	// subnetNearlyExhaustedIPCount is the number of available IP addresses under which
+	// the subnets an instance is launched in are considered nearly exhausted.
+	subnetNearlyExhaustedIPCount = 16
+)
+
 // GetRunningInstanceByTags returns the existing instance or nothing if it doesn't exist.
 func (s *Service) GetRunningInstanceByTags(scope *scope.MachineScope) (*infrav1.Instance, error) {
 	s.scope.V(2).Info("Looking for existing machine instance by tags")
@@ -105,10 +113,17 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 	s.scope.V(2).Info("Creating an instance for a machine")
 
 	input := &infrav1.Instance{
-		Type:              scope.AWSMachine.Spec.InstanceType,
-		IAMProfile:        scope.AWSMachine.Spec.IAMInstanceProfile,
-		RootVolume:        scope.AWSMachine.Spec.RootVolume,
-		NetworkInterfaces: scope.AWSMachine.Spec.NetworkInterfaces,
+		Type:                    scope.AWSMachine.Spec.InstanceType,
+		IAMProfile:              scope.AWSMachine.Spec.IAMInstanceProfile,
+		RootVolume:              scope.AWSMachine.Spec.RootVolume,
+		AdditionalVolumes:       scope.AWSMachine.Spec.AdditionalVolumes,
+		Placement:               scope.AWSMachine.Spec.Placement,
+		InstanceMetadataOptions: scope.AWSMachine.Spec.InstanceMetadataOptions,
+		NetworkInterfaces:       scope.AWSMachine.Spec.NetworkInterfaces,
+		PrivateIP:               scope.AWSMachine.Spec.PrivateIPAddress,
+		SpotMarketOptions:       scope.AWSMachine.Spec.SpotMarketOptions,
+		DisableAPITermination:   scope.AWSMachine.Spec.DisableAPITermination,
+		CapacityReservation:     scope.AWSMachine.Spec.CapacityReservation,
 	}
 
 	// Make sure to use the MachineScope here to get the merger of AWSCluster and AWSMachine tags
@@ -124,10 +139,17 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 		Additional:  additionalTags,
 	})
 
-	var err error
+	// Resolve the processor architectures supported by the instance type, so that
+	// a matching AMI is used.
+	architectures, err := s.instanceTypeArchitectures(input.Type)
+	if err != nil {
+		return nil, err
+	}
+
 	// Pick image from the machine configuration, or use a default one.
 	if scope.AWSMachine.Spec.AMI.ID != nil {
 		input.ImageID = *scope.AWSMachine.Spec.AMI.ID
+
 	} else {
 		if scope.Machine.Spec.Version == nil {
 			err := errors.New("Either AWSMachine's spec.ami.id or Machine's spec.version must be defined")
@@ -146,12 +168,45 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 			imageLookupBaseOS = scope.AWSCluster.Spec.ImageLookupBaseOS
 		}
 
-		input.ImageID, err = s.defaultAMILookup(imageLookupOrg, imageLookupBaseOS, *scope.Machine.Spec.Version)
+		imageLookupFormat := scope.AWSMachine.Spec.ImageLookupFormat
+		if imageLookupFormat == "" {
+			imageLookupFormat = scope.AWSCluster.Spec.ImageLookupFormat
+		}
+
+		imageLookupSSMParameter := scope.AWSMachine.Spec.ImageLookupSSMParameter
+		if imageLookupSSMParameter == "" {
+			imageLookupSSMParameter = scope.AWSCluster.Spec.ImageLookupSSMParameter
+		}
+
+		input.ImageID, err = s.lookupAMI(&AMILookup{
+			OwnerID:           imageLookupOrg,
+			BaseOS:            imageLookupBaseOS,
+			Architecture:      preferredArchitecture(architectures),
+			KubernetesVersion: *scope.Machine.Spec.Version,
+			NameFormat:        imageLookupFormat,
+			SSMParameter:      imageLookupSSMParameter,
+		})
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	// Whichever way the image was picked, it must run on the instance type.
+	imageArchitecture, err := s.imageArchitecture(input.ImageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !supportsArchitecture(architectures, imageArchitecture) {
+		err := errors.Errorf("AMI %q has architecture %q, which is not supported by instance type %q (supported: %s)",
+			input.ImageID, imageArchitecture, input.Type, strings.Join(architectures, ", "))
+		record.Warnf(scope.AWSMachine, "ArchitectureMismatch", "Failed to create instance: %v", err)
+		scope.SetFailureReason(capierrors.InvalidConfigurationMachineError)
+		scope.SetFailureMessage(err)
+		return nil, err
+	}
+	input.Architecture = imageArchitecture
+
 	// Prefer AWSMachine.Spec.FailureDomain for now while migrating to the use of
 	// Machine.Spec.FailureDomain. The MachineController will handle migrating the value for us.
 	failureDomain := scope.AWSMachine.Spec.FailureDomain
@@ -159,39 +214,9 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 		failureDomain = scope.Machine.Spec.FailureDomain
 	}
 
-	// Pick subnet from the machine configuration, or based on the availability zone specified,
-	// or default to the first private subnet available.
-	// TODO(vincepri): Move subnet picking logic to its own function/method.
-	switch {
-	case scope.AWSMachine.Spec.Subnet != nil && scope.AWSMachine.Spec.Subnet.ID != nil:
-		input.SubnetID = *scope.AWSMachine.Spec.Subnet.ID
-
-	case failureDomain != nil:
-		subnets := s.scope.Subnets().FilterPrivate().FilterByZone(*failureDomain)
-		if len(subnets) == 0 {
-			record.Warnf(scope.AWSMachine, "FailedCreate",
-				"Failed to create instance: no subnets available in availability zone %q", *failureDomain)
-
-			return nil, awserrors.NewFailedDependency(
-				errors.Errorf("failed to run machine %q, no subnets available in availability zone %q",
-					scope.Name(),
-					*failureDomain,
-				),
-			)
-		}
-		input.SubnetID = subnets[0].ID
-
-		// TODO(vincepri): Define a tag that would allow to pick a preferred subnet in an AZ when working
-		// with control plane machines.
-
-	case input.SubnetID == "":
-		sns := s.scope.Subnets().FilterPrivate()
-		if len(sns) == 0 {
-			return nil, awserrors.NewFailedDependency(
-				errors.Errorf("failed to run machine %q, no subnets available", scope.Name()),
-			)
-		}
-		input.SubnetID = sns[0].ID
+	input.SubnetID, err = s.findSubnet(scope, failureDomain)
+	if err != nil {
+		return nil, err
 	}
 
 	if s.scope.Network().APIServerELB.DNSName == "" {
@@ -215,19 +240,16 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 	}
 	input.SecurityGroupIDs = append(input.SecurityGroupIDs, ids...)
 
-	// If SSHKeyName WAS NOT provided in the AWSMachine Spec, fallback to the value provided in the AWSCluster Spec.
-	// If a value was not provided in the AWSCluster Spec, then use the defaultSSHKeyName
-	input.SSHKeyName = scope.AWSMachine.Spec.SSHKeyName
-	if input.SSHKeyName == nil {
-		if scope.AWSCluster.Spec.SSHKeyName != nil {
-			input.SSHKeyName = scope.AWSCluster.Spec.SSHKeyName
-		} else {
-			input.SSHKeyName = aws.String(defaultSSHKeyName)
+	input.SSHKeyName = s.sshKeyName(scope.AWSMachine.Spec.SSHKeyName)
+
+	if len(scope.AWSMachine.Spec.ManagedNetworkInterfaces) > 0 {
+		input.SecondaryNetworkInterfaces, err = s.ensureManagedNetworkInterfaces(scope, input, failureDomain)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	s.scope.V(2).Info("Running instance", "machine-role", scope.Role())
-	out, err := s.runInstance(scope.Role(), input)
+	out, err := s.runInstanceWithFallback(scope, input)
 	if err != nil {
 		// Only record the failure event if the error is not related to failed dependencies.
 		// This is to avoid spamming failure events since the machine will be requeued by the actuator.
@@ -246,10 +268,236 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 		}
 	}
 
-	record.Eventf(scope.AWSMachine, "SuccessfulCreate", "Created new %s instance with id %q", scope.Role(), out.ID)
+	record.Eventf(scope.AWSMachine, "SuccessfulCreate", "Created new %s instance of type %q with id %q", scope.Role(), out.Type, out.ID)
 	return out, nil
 }
 
+// runInstanceWithFallback runs an instance, falling back to the machine's alternative failure domains
+// and instance types, in that order, when there is not enough capacity to launch it.
+func (s *Service) runInstanceWithFallback(scope *scope.MachineScope, input *infrav1.Instance) (*infrav1.Instance, error) {
+	instanceTypes, err := s.fallbackInstanceTypes(scope, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// The fallback failure domains are only used if the machine doesn't specify a subnet,
+	// a static private IP address or managed network interfaces, which are bound to the
+	// subnet or availability zone the instance was first attempted in.
+	var fallbackFailureDomains []string
+	if scope.AWSMachine.Spec.Subnet == nil && scope.AWSMachine.Spec.PrivateIPAddress == nil &&
+		len(scope.AWSMachine.Spec.ManagedNetworkInterfaces) == 0 {
+		fallbackFailureDomains = scope.AWSMachine.Spec.FallbackFailureDomains
+	}
+
+	var lastErr error
+	subnetID := input.SubnetID
+	tried := map[string]bool{}
+	for i := 0; i <= len(fallbackFailureDomains); i++ {
+		if i > 0 {
+			zone := fallbackFailureDomains[i-1]
+			subnets := s.scope.Subnets().FilterPrivate().FilterByZone(zone)
+			if len(subnets) == 0 {
+				s.scope.V(2).Info("No subnets available in fallback failure domain", "availability-zone", zone)
+				continue
+			}
+
+			subnetID, err = s.leastUtilizedSubnet(scope, subnets.IDs())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if tried[subnetID] {
+			continue
+		}
+		tried[subnetID] = true
+
+		for _, instanceType := range instanceTypes {
+			input.SubnetID = subnetID
+			input.Type = instanceType
+
+			s.scope.V(2).Info("Running instance", "machine-role", scope.Role(), "instance-type", instanceType, "subnet-id", subnetID)
+			out, err := s.runInstance(scope.Role(), input)
+			if err == nil {
+				return out, nil
+			}
+			if !awserrors.IsInsufficientCapacity(errors.Cause(err)) {
+				return nil, err
+			}
+
+			record.Warnf(scope.AWSMachine, "InsufficientCapacity",
+				"Insufficient capacity to launch instance of type %q in subnet %q: %v", instanceType, subnetID, err)
+			lastErr = err
+		}
+	}
+
+	return nil, lastErr
+}
+
+// fallbackInstanceTypes returns the instance types to launch the machine with, in order of preference.
+// Fallback instance types that do not support the architecture of the instance AMI are skipped.
+func (s *Service) fallbackInstanceTypes(scope *scope.MachineScope, input *infrav1.Instance) ([]string, error) {
+	instanceTypes := []string{input.Type}
+	for _, instanceType := range scope.AWSMachine.Spec.FallbackInstanceTypes {
+		if containsGroup(instanceTypes, instanceType) {
+			continue
+		}
+
+		architectures, err := s.instanceTypeArchitectures(instanceType)
+		if err != nil {
+			return nil, err
+		}
+		if !supportsArchitecture(architectures, input.Architecture) {
+			record.Warnf(scope.AWSMachine, "ArchitectureMismatch",
+				"Skipping fallback instance type %q, which does not support architecture %q", instanceType, input.Architecture)
+			continue
+		}
+
+		instanceTypes = append(instanceTypes, instanceType)
+	}
+
+	return instanceTypes, nil
+}
+
+// findSubnet returns the ID of the subnet to launch the machine instance in. The subnet is resolved from
+// the machine configuration if specified there, otherwise it is picked from the private subnets of the
+// cluster in the failure domain of the machine, or from all the private subnets of the cluster.
+func (s *Service) findSubnet(scope *scope.MachineScope, failureDomain *string) (string, error) {
+	subnet := scope.AWSMachine.Spec.Subnet
+
+	switch {
+	case subnet != nil && (subnet.ID != nil || subnet.ARN != nil || len(subnet.Filters) > 0):
+		return s.resolveSubnetReference(scope, subnet, failureDomain)
+
+	case failureDomain != nil:
+		subnets := s.scope.Subnets().FilterPrivate().FilterByZone(*failureDomain)
+		if len(subnets) == 0 {
+			record.Warnf(scope.AWSMachine, "FailedCreate",
+				"Failed to create instance: no subnets available in availability zone %q", *failureDomain)
+
+			return "", awserrors.NewFailedDependency(
+				errors.Errorf("failed to run machine %q, no subnets available in availability zone %q",
+					scope.Name(),
+					*failureDomain,
+				),
+			)
+		}
+
+		// TODO(vincepri): Define a tag that would allow to pick a preferred subnet in an AZ when working
+		// with control plane machines.
+		return s.leastUtilizedSubnet(scope, subnets.IDs())
+
+	default:
+		subnets := s.scope.Subnets().FilterPrivate()
+		if len(subnets) == 0 {
+			return "", awserrors.NewFailedDependency(
+				errors.Errorf("failed to run machine %q, no subnets available", scope.Name()),
+			)
+		}
+		return s.leastUtilizedSubnet(scope, subnets.IDs())
+	}
+}
+
+// resolveSubnetReference returns the ID of the subnet referenced by ID, ARN or filters.
+// Subnets matched by filters are restricted to the given failure domain, if any.
+func (s *Service) resolveSubnetReference(scope *scope.MachineScope, subnet *infrav1.AWSResourceReference, failureDomain *string) (string, error) {
+	switch {
+	case subnet.ID != nil:
+		return *subnet.ID, nil
+
+	case subnet.ARN != nil:
+		id, err := resourceIDFromARN(*subnet.ARN, "subnet")
+		if err != nil {
+			record.Warnf(scope.AWSMachine, "FailedCreate", "Failed to create instance: %v", err)
+			return "", err
+		}
+		return id, nil
+
+	default:
+		input := &ec2.DescribeSubnetsInput{
+			Filters: append([]*ec2.Filter{filter.EC2.VPC(s.scope.VPC().ID)}, filtersToSDK(subnet.Filters)...),
+		}
+		if failureDomain != nil {
+			input.Filters = append(input.Filters, filter.EC2.AvailabilityZone(*failureDomain))
+		}
+
+		out, err := s.scope.EC2.DescribeSubnets(input)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to describe subnets in vpc %q", s.scope.VPC().ID)
+		}
+		if len(out.Subnets) == 0 {
+			record.Warnf(scope.AWSMachine, "FailedCreate", "Failed to create instance: no subnets match the subnet filters")
+			return "", awserrors.NewFailedDependency(
+				errors.Errorf("failed to run machine %q, no subnets match the subnet filters", scope.Name()),
+			)
+		}
+		return s.pickSubnet(scope, out.Subnets), nil
+	}
+}
+
+// leastUtilizedSubnet returns the ID of the subnet with the most available IP addresses.
+func (s *Service) leastUtilizedSubnet(scope *scope.MachineScope, subnetIDs []string) (string, error) {
+	out, err := s.scope.EC2.DescribeSubnets(&ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(subnetIDs),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe subnets %v", subnetIDs)
+	}
+	if len(out.Subnets) == 0 {
+		return "", awserrors.NewFailedDependency(
+			errors.Errorf("failed to run machine %q, subnets %v not found", scope.Name(), subnetIDs),
+		)
+	}
+
+	return s.pickSubnet(scope, out.Subnets), nil
+}
+
+// pickSubnet returns the ID of the subnet with the most available IP addresses, and records an event
+// if the subnets are nearly exhausted. The subnets are assumed not to be empty.
+func (s *Service) pickSubnet(scope *scope.MachineScope, subnets []*ec2.Subnet) string {
+	picked := subnets[0]
+	for _, sn := range subnets[1:] {
+		if aws.Int64Value(sn.AvailableIpAddressCount) > aws.Int64Value(picked.AvailableIpAddressCount) {
+			picked = sn
+		}
+	}
+
+	if available := aws.Int64Value(picked.AvailableIpAddressCount); available < subnetNearlyExhaustedIPCount {
+		record.Warnf(scope.AWSMachine, "SubnetsNearlyExhausted",
+			"Subnets in availability zone %q have at most %d available IP addresses", aws.StringValue(picked.AvailabilityZone), available)
+	}
+
+	return aws.StringValue(picked.SubnetId)
+}
+
+// filtersToSDK converts filters to AWS SDK filters.
+func filtersToSDK(filters []infrav1.Filter) []*ec2.Filter {
+	res := make([]*ec2.Filter, 0, len(filters))
+	for _, f := range filters {
+		res = append(res, &ec2.Filter{
+			Name:   aws.String(f.Name),
+			Values: aws.StringSlice(f.Values),
+		})
+	}
+	return res
+}
+
+// resourceIDFromARN returns the ID of an EC2 resource of the given type from its ARN,
+// for example "arn:aws:ec2:us-east-1:123456789012:subnet/subnet-1234".
+func resourceIDFromARN(resourceARN, resourceType string) (string, error) {
+	parsed, err := arn.Parse(resourceARN)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse ARN %q", resourceARN)
+	}
+
+	prefix := resourceType + "/"
+	if parsed.Service != ec2.ServiceName || !strings.HasPrefix(parsed.Resource, prefix) {
+		return "", errors.Errorf("ARN %q is not the ARN of an EC2 %s", resourceARN, resourceType)
+	}
+
+	return strings.TrimPrefix(parsed.Resource, prefix), nil
+}
+
 // GetCoreSecurityGroups looks up the security group IDs managed by this actuator
 // They are considered "core" to its proper functioning
 func (s *Service) GetCoreSecurityGroups(scope *scope.MachineScope) ([]string, error) {
@@ -278,6 +526,59 @@ func (s *Service) GetCoreSecurityGroups(scope *scope.MachineScope) ([]string, er
 	return ids, nil
 }
 
+// GetAdditionalSecurityGroupsIDs resolves the IDs of security group references. References by filters
+// must match exactly one security group of the cluster VPC.
+func (s *Service) GetAdditionalSecurityGroupsIDs(securityGroups []infrav1.AWSResourceReference) ([]string, error) {
+	ids := make([]string, 0, len(securityGroups))
+	for _, sg := range securityGroups {
+		switch {
+		case sg.ID != nil:
+			ids = append(ids, *sg.ID)
+
+		case sg.ARN != nil:
+			id, err := resourceIDFromARN(*sg.ARN, "security-group")
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+
+		case len(sg.Filters) > 0:
+			id, err := s.getFilteredSecurityGroupID(sg.Filters)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+
+		default:
+			return nil, errors.New("security group reference must set one of id, arn or filters")
+		}
+	}
+
+	return ids, nil
+}
+
+func (s *Service) getFilteredSecurityGroupID(filters []infrav1.Filter) (string, error) {
+	out, err := s.scope.EC2.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		Filters: append([]*ec2.Filter{filter.EC2.VPC(s.scope.VPC().ID)}, filtersToSDK(filters)...),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe security groups in vpc %q", s.scope.VPC().ID)
+	}
+
+	switch len(out.SecurityGroups) {
+	case 0:
+		return "", errors.Errorf("security group filters %v matched no security groups in vpc %q", filters, s.scope.VPC().ID)
+	case 1:
+		return aws.StringValue(out.SecurityGroups[0].GroupId), nil
+	default:
+		ids := make([]string, 0, len(out.SecurityGroups))
+		for _, sg := range out.SecurityGroups {
+			ids = append(ids, aws.StringValue(sg.GroupId))
+		}
+		return "", errors.Errorf("security group filters %v matched multiple security groups in vpc %q: %v", filters, s.scope.VPC().ID, ids)
+	}
+}
+
 // TerminateInstance terminates an EC2 instance.
 // Returns nil on success, error in all other cases.
 func (s *Service) TerminateInstance(instanceID string) error {
@@ -295,6 +596,272 @@ func (s *Service) TerminateInstance(instanceID string) error {
 	return nil
 }
 
+// CancelSpotInstanceRequest cancels a spot instance request so that no new instance
+// gets launched for it. Instances that were already launched are not terminated.
+func (s *Service) CancelSpotInstanceRequest(requestID string) error {
+	s.scope.V(2).Info("Attempting to cancel spot instance request", "spot-instance-request-id", requestID)
+
+	input := &ec2.CancelSpotInstanceRequestsInput{
+		SpotInstanceRequestIds: aws.StringSlice([]string{requestID}),
+	}
+
+	if _, err := s.scope.EC2.CancelSpotInstanceRequests(input); err != nil {
+		return errors.Wrapf(err, "failed to cancel spot instance request with id %q", requestID)
+	}
+
+	s.scope.V(2).Info("Cancelled spot instance request", "spot-instance-request-id", requestID)
+	return nil
+}
+
+// DisownInstance removes the cluster ownership tags from an instance, as well as from the Elastic IP
+// address and network interfaces created for its machine, so that they are left behind when the
+// machine and the cluster are deleted.
+func (s *Service) DisownInstance(scope *scope.MachineScope, instanceID string) error {
+	s.scope.V(2).Info("Attempting to remove cluster tags from instance", "instance-id", instanceID)
+
+	resources := []string{instanceID}
+
+	if eip := scope.AWSMachine.Spec.ElasticIP; eip != nil && eip.AllocationID == nil {
+		ip, err := s.describeMachineAddress(scope)
+		if err != nil {
+			return err
+		}
+		if ip != nil {
+			resources = append(resources, aws.StringValue(ip.AllocationId))
+		}
+	}
+
+	enis, err := s.describeManagedNetworkInterfaces(scope)
+	if err != nil {
+		return err
+	}
+	for _, eni := range enis {
+		resources = append(resources, aws.StringValue(eni.NetworkInterfaceId))
+	}
+
+	input := &ec2.DeleteTagsInput{
+		Resources: aws.StringSlice(resources),
+		Tags: []*ec2.Tag{
+			{Key: aws.String(infrav1.ClusterTagKey(s.scope.Name()))},
+			{Key: aws.String(infrav1.ClusterAWSCloudProviderTagKey(s.scope.Name()))},
+		},
+	}
+
+	if _, err := s.scope.EC2.DeleteTags(input); err != nil {
+		return errors.Wrapf(err, "failed to remove cluster tags from instance with id %q", instanceID)
+	}
+
+	s.scope.V(2).Info("Removed cluster tags from instance", "instance-id", instanceID, "resources", resources)
+	return nil
+}
+
+// DisableTerminationProtection disables the termination protection of an instance, so that it can be terminated.
+func (s *Service) DisableTerminationProtection(instanceID string) error {
+	s.scope.V(2).Info("Attempting to disable termination protection of instance", "instance-id", instanceID)
+
+	input := &ec2.ModifyInstanceAttributeInput{
+		InstanceId:            aws.String(instanceID),
+		DisableApiTermination: &ec2.AttributeBooleanValue{Value: aws.Bool(false)},
+	}
+
+	if _, err := s.scope.EC2.ModifyInstanceAttribute(input); err != nil {
+		return errors.Wrapf(err, "failed to disable termination protection of instance with id %q", instanceID)
+	}
+
+	s.scope.V(2).Info("Disabled termination protection of instance", "instance-id", instanceID)
+	return nil
+}
+
+// StopInstance stops an instance.
+func (s *Service) StopInstance(instanceID string) error {
+	s.scope.V(2).Info("Attempting to stop instance", "instance-id", instanceID)
+
+	input := &ec2.StopInstancesInput{
+		InstanceIds: aws.StringSlice([]string{instanceID}),
+	}
+
+	if _, err := s.scope.EC2.StopInstances(input); err != nil {
+		return errors.Wrapf(err, "failed to stop instance with id %q", instanceID)
+	}
+
+	s.scope.V(2).Info("Stopped instance", "instance-id", instanceID)
+	return nil
+}
+
+// StartInstance starts a stopped instance.
+func (s *Service) StartInstance(instanceID string) error {
+	s.scope.V(2).Info("Attempting to start instance", "instance-id", instanceID)
+
+	input := &ec2.StartInstancesInput{
+		InstanceIds: aws.StringSlice([]string{instanceID}),
+	}
+
+	if _, err := s.scope.EC2.StartInstances(input); err != nil {
+		return errors.Wrapf(err, "failed to start instance with id %q", instanceID)
+	}
+
+	s.scope.V(2).Info("Started instance", "instance-id", instanceID)
+	return nil
+}
+
+// GetInstanceScheduledEvents returns the events AWS has scheduled for an instance and that are
+// not completed or canceled yet.
+func (s *Service) GetInstanceScheduledEvents(instanceID string) ([]infrav1.InstanceScheduledEvent, error) {
+	input := &ec2.DescribeInstanceStatusInput{
+		InstanceIds: aws.StringSlice([]string{instanceID}),
+	}
+
+	out, err := s.scope.EC2.DescribeInstanceStatus(input)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe status of instance with id %q", instanceID)
+	}
+
+	var events []infrav1.InstanceScheduledEvent
+	for _, status := range out.InstanceStatuses {
+		for _, event := range status.Events {
+			// Events remain listed for a while once they are over, with their description
+			// prefixed accordingly.
+			description := aws.StringValue(event.Description)
+			if strings.HasPrefix(description, "[Completed]") || strings.HasPrefix(description, "[Canceled]") {
+				continue
+			}
+
+			scheduled := infrav1.InstanceScheduledEvent{
+				Code:        aws.StringValue(event.Code),
+				Description: description,
+			}
+			if event.NotBefore != nil {
+				notBefore := metav1.NewTime(*event.NotBefore)
+				scheduled.NotBefore = &notBefore
+			}
+			events = append(events, scheduled)
+		}
+	}
+
+	return events, nil
+}
+
+// ModifyInstanceType changes the type of a stopped instance.
+func (s *Service) ModifyInstanceType(instanceID string, instanceType string) error {
+	s.scope.V(2).Info("Attempting to modify instance type", "instance-id", instanceID, "instance-type", instanceType)
+
+	input := &ec2.ModifyInstanceAttributeInput{
+		InstanceId:   aws.String(instanceID),
+		InstanceType: &ec2.AttributeValue{Value: aws.String(instanceType)},
+	}
+
+	if _, err := s.scope.EC2.ModifyInstanceAttribute(input); err != nil {
+		return errors.Wrapf(err, "failed to modify type of instance with id %q", instanceID)
+	}
+
+	return nil
+}
+
+// GetRootVolumeSize returns the size (in Gi) of the root volume of an instance.
+func (s *Service) GetRootVolumeSize(instanceID string) (int64, error) {
+	volume, err := s.getRootVolume(instanceID)
+	if err != nil {
+		return 0, err
+	}
+
+	return aws.Int64Value(volume.Size), nil
+}
+
+// ModifyRootVolumeSize grows the root volume of an instance to the given size (in Gi).
+// Root volumes that are at least as large are left untouched, since volumes cannot shrink.
+func (s *Service) ModifyRootVolumeSize(instanceID string, size int64) error {
+	volume, err := s.getRootVolume(instanceID)
+	if err != nil {
+		return err
+	}
+
+	if aws.Int64Value(volume.Size) >= size {
+		return nil
+	}
+
+	s.scope.V(2).Info("Attempting to modify root volume size", "instance-id", instanceID, "volume-id", aws.StringValue(volume.VolumeId), "size", size)
+
+	input := &ec2.ModifyVolumeInput{
+		VolumeId: volume.VolumeId,
+		Size:     aws.Int64(size),
+	}
+
+	if _, err := s.scope.EC2.ModifyVolume(input); err != nil {
+		return errors.Wrapf(err, "failed to modify size of volume %q", aws.StringValue(volume.VolumeId))
+	}
+
+	return nil
+}
+
+func (s *Service) getRootVolume(instanceID string) (*ec2.Volume, error) {
+	out, err := s.scope.EC2.DescribeInstances(&ec2.DescribeInstancesInput{
+		InstanceIds: aws.StringSlice([]string{instanceID}),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe instance %q", instanceID)
+	}
+	if len(out.Reservations) == 0 || len(out.Reservations[0].Instances) == 0 {
+		return nil, errors.Errorf("instance %q not found", instanceID)
+	}
+	instance := out.Reservations[0].Instances[0]
+
+	for _, bdm := range instance.BlockDeviceMappings {
+		if aws.StringValue(bdm.DeviceName) != aws.StringValue(instance.RootDeviceName) || bdm.Ebs == nil {
+			continue
+		}
+
+		volumes, err := s.scope.EC2.DescribeVolumes(&ec2.DescribeVolumesInput{
+			VolumeIds: []*string{bdm.Ebs.VolumeId},
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to describe volume %q", aws.StringValue(bdm.Ebs.VolumeId))
+		}
+		if len(volumes.Volumes) == 0 {
+			break
+		}
+		return volumes.Volumes[0], nil
+	}
+
+	return nil, errors.Errorf("root volume of instance %q not found", instanceID)
+}
+
+// UpdateInstanceMetadataOptions modifies the metadata options of an instance if they differ
+// from the desired ones. Options that are not set are left untouched.
+// Returns true if the instance was modified.
+func (s *Service) UpdateInstanceMetadataOptions(instance *infrav1.Instance, desired *infrav1.InstanceMetadataOptions) (bool, error) {
+	if desired == nil {
+		return false, nil
+	}
+
+	current := instance.InstanceMetadataOptions
+	if current == nil {
+		current = &infrav1.InstanceMetadataOptions{}
+	}
+
+	if (desired.HTTPTokens == "" || desired.HTTPTokens == current.HTTPTokens) &&
+		(desired.HTTPPutResponseHopLimit == 0 || desired.HTTPPutResponseHopLimit == current.HTTPPutResponseHopLimit) &&
+		(desired.HTTPEndpoint == "" || desired.HTTPEndpoint == current.HTTPEndpoint) {
+		return false, nil
+	}
+
+	s.scope.V(2).Info("Attempting to update metadata options on instance", "instance-id", instance.ID)
+
+	request := getInstanceMetadataOptionsRequest(desired)
+	input := &ec2.ModifyInstanceMetadataOptionsInput{
+		InstanceId:              aws.String(instance.ID),
+		HttpTokens:              request.HttpTokens,
+		HttpPutResponseHopLimit: request.HttpPutResponseHopLimit,
+		HttpEndpoint:            request.HttpEndpoint,
+	}
+
+	if _, err := s.scope.EC2.ModifyInstanceMetadataOptions(input); err != nil {
+		return false, errors.Wrapf(err, "failed to modify metadata options of instance %q", instance.ID)
+	}
+
+	s.scope.V(2).Info("Updated metadata options on instance", "instance-id", instance.ID)
+	return true, nil
+}
+
 // TerminateInstanceAndWait terminates and waits
 // for an EC2 instance to terminate.
 func (s *Service) TerminateInstanceAndWait(instanceID string) error {
@@ -315,6 +882,26 @@ func (s *Service) TerminateInstanceAndWait(instanceID string) error {
 	return nil
 }
 
+// sshKeyName returns the name of the SSH key to launch an instance with, given the key set in its spec.
+// If no key was set, fallback to the value provided in the AWSCluster Spec. If a value was not provided in
+// the AWSCluster Spec either, then use the defaultSSHKeyName, unless the cluster is accessed through
+// Session Manager. An empty key name means the instance is launched without a key, in which case nil is returned.
+func (s *Service) sshKeyName(key *string) *string {
+	if key == nil {
+		key = s.scope.AWSCluster.Spec.SSHKeyName
+	}
+	if key == nil {
+		if s.scope.AccessMode() == infrav1.ClusterAccessModeSessionManager {
+			return nil
+		}
+		key = aws.String(defaultSSHKeyName)
+	}
+	if *key == "" {
+		return nil
+	}
+	return key
+}
+
 func (s *Service) runInstance(role string, i *infrav1.Instance) (*infrav1.Instance, error) {
 	input := &ec2.RunInstancesInput{
 		InstanceType: aws.String(i.Type),
@@ -328,19 +915,36 @@ func (s *Service) runInstance(role string, i *infrav1.Instance) (*infrav1.Instan
 
 	s.scope.V(2).Info("userData size", "bytes", len(*i.UserData), "role", role)
 
-	if len(i.NetworkInterfaces) > 0 {
-		netInterfaces := make([]*ec2.InstanceNetworkInterfaceSpecification, 0, len(i.NetworkInterfaces))
+	if len(i.NetworkInterfaces) > 0 || len(i.SecondaryNetworkInterfaces) > 0 {
+		netInterfaces := make([]*ec2.InstanceNetworkInterfaceSpecification, 0, len(i.NetworkInterfaces)+len(i.SecondaryNetworkInterfaces)+1)
 
-		for index, id := range i.NetworkInterfaces {
-			netInterfaces = append(netInterfaces, &ec2.InstanceNetworkInterfaceSpecification{
-				NetworkInterfaceId: aws.String(id),
-				DeviceIndex:        aws.Int64(int64(index)),
-			})
+		// The primary network interface has to be specified along with the secondary ones.
+		if len(i.NetworkInterfaces) == 0 {
+			primary := &ec2.InstanceNetworkInterfaceSpecification{
+				DeviceIndex:         aws.Int64(0),
+				SubnetId:            aws.String(i.SubnetID),
+				PrivateIpAddress:    i.PrivateIP,
+				DeleteOnTermination: aws.Bool(true),
+			}
+			if len(i.SecurityGroupIDs) > 0 {
+				primary.Groups = aws.StringSlice(i.SecurityGroupIDs)
+			}
+			netInterfaces = append(netInterfaces, primary)
+		}
+
+		for _, ids := range [][]string{i.NetworkInterfaces, i.SecondaryNetworkInterfaces} {
+			for _, id := range ids {
+				netInterfaces = append(netInterfaces, &ec2.InstanceNetworkInterfaceSpecification{
+					NetworkInterfaceId: aws.String(id),
+					DeviceIndex:        aws.Int64(int64(len(netInterfaces))),
+				})
+			}
 		}
 
 		input.NetworkInterfaces = netInterfaces
 	} else {
 		input.SubnetId = aws.String(i.SubnetID)
+		input.PrivateIpAddress = i.PrivateIP
 
 		if len(i.SecurityGroupIDs) > 0 {
 			input.SecurityGroupIds = aws.StringSlice(i.SecurityGroupIDs)
@@ -395,6 +999,22 @@ func (s *Service) runInstance(role string, i *infrav1.Instance) (*infrav1.Instan
 		}
 	}
 
+	for _, volume := range i.AdditionalVolumes {
+		input.BlockDeviceMappings = append(input.BlockDeviceMappings, &ec2.BlockDeviceMapping{
+			DeviceName: aws.String(volume.DeviceName),
+			Ebs:        getVolumeBlockDevice(volume),
+		})
+	}
+
+	input.InstanceMarketOptions = getInstanceMarketOptionsRequest(i.SpotMarketOptions)
+	input.CapacityReservationSpecification = getCapacityReservationSpecification(i.CapacityReservation)
+	input.Placement = getPlacement(i.Placement)
+	input.MetadataOptions = getInstanceMetadataOptionsRequest(i.InstanceMetadataOptions)
+
+	if i.DisableAPITermination {
+		input.DisableApiTermination = aws.Bool(true)
+	}
+
 	if len(i.Tags) > 0 {
 		spec := &ec2.TagSpecification{ResourceType: aws.String(ec2.ResourceTypeInstance)}
 		for key, value := range i.Tags {
@@ -405,6 +1025,13 @@ func (s *Service) runInstance(role string, i *infrav1.Instance) (*infrav1.Instan
 		}
 
 		input.TagSpecifications = append(input.TagSpecifications, spec)
+
+		// Tag the volumes as well, so that they can be identified for cleanup.
+		volumeSpec := &ec2.TagSpecification{
+			ResourceType: aws.String(ec2.ResourceTypeVolume),
+			Tags:         spec.Tags,
+		}
+		input.TagSpecifications = append(input.TagSpecifications, volumeSpec)
 	}
 
 	out, err := s.scope.EC2.RunInstances(input)
@@ -432,6 +1059,136 @@ func (s *Service) runInstance(role string, i *infrav1.Instance) (*infrav1.Instan
 	return s.SDKToInstance(out.Instances[0])
 }
 
+// getVolumeBlockDevice returns the EBS block device for an additional volume.
+func getVolumeBlockDevice(volume infrav1.Volume) *ec2.EbsBlockDevice {
+	ebsDevice := &ec2.EbsBlockDevice{
+		DeleteOnTermination: aws.Bool(true),
+		VolumeSize:          aws.Int64(volume.Size),
+		Encrypted:           aws.Bool(volume.Encrypted),
+	}
+
+	if volume.IOPS != 0 {
+		ebsDevice.Iops = aws.Int64(volume.IOPS)
+	}
+
+	if volume.EncryptionKey != "" {
+		ebsDevice.Encrypted = aws.Bool(true)
+		ebsDevice.KmsKeyId = aws.String(volume.EncryptionKey)
+	}
+
+	if volume.Type != "" {
+		ebsDevice.VolumeType = aws.String(volume.Type)
+	}
+
+	return ebsDevice
+}
+
+// getPlacement returns the placement to launch the instance with, or nil if no placement is configured.
+func getPlacement(placement *infrav1.Placement) *ec2.Placement {
+	if placement == nil {
+		return nil
+	}
+
+	out := &ec2.Placement{
+		PartitionNumber: placement.PartitionNumber,
+	}
+
+	if placement.GroupName != "" {
+		out.GroupName = aws.String(placement.GroupName)
+	}
+
+	if placement.Tenancy != "" {
+		out.Tenancy = aws.String(placement.Tenancy)
+	}
+
+	if placement.HostID != "" {
+		out.HostId = aws.String(placement.HostID)
+	}
+
+	if placement.HostResourceGroupARN != "" {
+		out.HostResourceGroupArn = aws.String(placement.HostResourceGroupARN)
+	}
+
+	return out
+}
+
+// getInstanceMetadataOptionsRequest returns the metadata options to launch the instance with,
+// or nil if the EC2 defaults should be used.
+func getInstanceMetadataOptionsRequest(options *infrav1.InstanceMetadataOptions) *ec2.InstanceMetadataOptionsRequest {
+	if options == nil {
+		return nil
+	}
+
+	request := &ec2.InstanceMetadataOptionsRequest{}
+
+	if options.HTTPTokens != "" {
+		request.HttpTokens = aws.String(options.HTTPTokens)
+	}
+
+	if options.HTTPPutResponseHopLimit != 0 {
+		request.HttpPutResponseHopLimit = aws.Int64(options.HTTPPutResponseHopLimit)
+	}
+
+	if options.HTTPEndpoint != "" {
+		request.HttpEndpoint = aws.String(options.HTTPEndpoint)
+	}
+
+	return request
+}
+
+// getInstanceMarketOptionsRequest returns the market options to request the instance on the spot
+// market, or nil if the instance should be launched on-demand.
+func getInstanceMarketOptionsRequest(spotMarketOptions *infrav1.SpotMarketOptions) *ec2.InstanceMarketOptionsRequest {
+	if spotMarketOptions == nil {
+		return nil
+	}
+
+	spotOptions := &ec2.SpotMarketOptions{
+		// The default for MaxPrice is the on-demand price.
+		MaxPrice:         spotMarketOptions.MaxPrice,
+		SpotInstanceType: aws.String(ec2.SpotInstanceTypeOneTime),
+	}
+
+	if spotMarketOptions.InterruptionBehavior != "" {
+		spotOptions.InstanceInterruptionBehavior = aws.String(string(spotMarketOptions.InterruptionBehavior))
+	}
+
+	// Stopping or hibernating an interrupted instance is only supported for persistent requests.
+	if spotMarketOptions.InterruptionBehavior == infrav1.SpotInterruptionBehaviorStop ||
+		spotMarketOptions.InterruptionBehavior == infrav1.SpotInterruptionBehaviorHibernate {
+		spotOptions.SpotInstanceType = aws.String(ec2.SpotInstanceTypePersistent)
+	}
+
+	return &ec2.InstanceMarketOptionsRequest{
+		MarketType:  aws.String(ec2.MarketTypeSpot),
+		SpotOptions: spotOptions,
+	}
+}
+
+// getCapacityReservationSpecification returns the Capacity Reservation options of the instance,
+// or nil if the AWS defaults should be used.
+func getCapacityReservationSpecification(capacityReservation *infrav1.CapacityReservation) *ec2.CapacityReservationSpecification {
+	if capacityReservation == nil {
+		return nil
+	}
+
+	if capacityReservation.ID != nil {
+		return &ec2.CapacityReservationSpecification{
+			CapacityReservationTarget: &ec2.CapacityReservationTarget{
+				CapacityReservationId: capacityReservation.ID,
+			},
+		}
+	}
+
+	if capacityReservation.Preference != "" {
+		return &ec2.CapacityReservationSpecification{
+			CapacityReservationPreference: aws.String(string(capacityReservation.Preference)),
+		}
+	}
+
+	return nil
+}
+
 // An internal type to satisfy aws' log interface.
 type awslog struct {
 	logr.Logger
@@ -451,6 +1208,11 @@ func (s *Service) GetInstanceSecurityGroups(instanceID string) (map[string][]str
 
 	out := make(map[string][]string)
 	for _, eni := range enis {
+		// Managed network interfaces keep the security groups they were created with.
+		if isManagedNetworkInterface(eni) {
+			continue
+		}
+
 		var groups []string
 		for _, group := range eni.Groups {
 			groups = append(groups, aws.StringValue(group.GroupId))
@@ -473,6 +1235,10 @@ func (s *Service) UpdateInstanceSecurityGroups(instanceID string, ids []string)
 	s.scope.V(3).Info("Found ENIs on instance", "number-of-enis", len(enis), "instance-id", instanceID)
 
 	for _, eni := range enis {
+		if isManagedNetworkInterface(eni) {
+			continue
+		}
+
 		if err := s.attachSecurityGroupsToNetworkInterface(ids, aws.StringValue(eni.NetworkInterfaceId)); err != nil {
 			return errors.Wrapf(err, "failed to modify network interfaces on instance %q", instanceID)
 		}
@@ -587,16 +1353,24 @@ func (s *Service) getImageSnapshotSize(imageID string) (*int64, error) {
 // additional call to EC2 is required to get this value.
 func (s *Service) SDKToInstance(v *ec2.Instance) (*infrav1.Instance, error) {
 	i := &infrav1.Instance{
-		ID:           aws.StringValue(v.InstanceId),
-		State:        infrav1.InstanceState(*v.State.Name),
-		Type:         aws.StringValue(v.InstanceType),
-		SubnetID:     aws.StringValue(v.SubnetId),
-		ImageID:      aws.StringValue(v.ImageId),
-		SSHKeyName:   v.KeyName,
-		PrivateIP:    v.PrivateIpAddress,
-		PublicIP:     v.PublicIpAddress,
-		ENASupport:   v.EnaSupport,
-		EBSOptimized: v.EbsOptimized,
+		ID:                    aws.StringValue(v.InstanceId),
+		State:                 infrav1.InstanceState(*v.State.Name),
+		Type:                  aws.StringValue(v.InstanceType),
+		SubnetID:              aws.StringValue(v.SubnetId),
+		ImageID:               aws.StringValue(v.ImageId),
+		SSHKeyName:            v.KeyName,
+		PrivateIP:             v.PrivateIpAddress,
+		PublicIP:              v.PublicIpAddress,
+		ENASupport:            v.EnaSupport,
+		EBSOptimized:          v.EbsOptimized,
+		Lifecycle:             infrav1.InstanceLifecycleOnDemand,
+		Architecture:          aws.StringValue(v.Architecture),
+		CapacityReservationID: v.CapacityReservationId,
+	}
+
+	if aws.StringValue(v.InstanceLifecycle) == ec2.InstanceLifecycleTypeSpot {
+		i.Lifecycle = infrav1.InstanceLifecycleSpot
+		i.SpotInstanceRequestID = v.SpotInstanceRequestId
 	}
 
 	// Extract IAM Instance Profile name from ARN
@@ -613,6 +1387,34 @@ func (s *Service) SDKToInstance(v *ec2.Instance) (*infrav1.Instance, error) {
 		i.SecurityGroupIDs = append(i.SecurityGroupIDs, *sg.GroupId)
 	}
 
+	if v.Placement != nil {
+		i.Placement = &infrav1.Placement{
+			GroupName:            aws.StringValue(v.Placement.GroupName),
+			PartitionNumber:      v.Placement.PartitionNumber,
+			Tenancy:              aws.StringValue(v.Placement.Tenancy),
+			HostID:               aws.StringValue(v.Placement.HostId),
+			HostResourceGroupARN: aws.StringValue(v.Placement.HostResourceGroupArn),
+		}
+	}
+
+	if v.MetadataOptions != nil {
+		i.InstanceMetadataOptions = &infrav1.InstanceMetadataOptions{
+			HTTPTokens:              aws.StringValue(v.MetadataOptions.HttpTokens),
+			HTTPPutResponseHopLimit: aws.Int64Value(v.MetadataOptions.HttpPutResponseHopLimit),
+			HTTPEndpoint:            aws.StringValue(v.MetadataOptions.HttpEndpoint),
+		}
+	}
+
+	for _, bdm := range v.BlockDeviceMappings {
+		if bdm.Ebs == nil || bdm.Ebs.VolumeId == nil {
+			continue
+		}
+		i.VolumeAttachments = append(i.VolumeAttachments, infrav1.VolumeAttachment{
+			DeviceName: aws.StringValue(bdm.DeviceName),
+			VolumeID:   aws.StringValue(bdm.Ebs.VolumeId),
+		})
+	}
+
 	if len(v.Tags) > 0 {
 		i.Tags = converters.TagsToMap(v.Tags)
 	}
@@ -727,6 +1529,87 @@ func (s *Service) DetachSecurityGroupsFromNetworkInterface(groups []string, inte
 	return nil
 }
 
+// DetachCoreSecurityGroupsFromInstance removes the cluster's core security groups from the network interfaces
+// of the given instance, so that a retained instance doesn't keep them from being deleted along with the cluster.
+// Network interfaces that would be left without security groups get the default security group of the VPC.
+func (s *Service) DetachCoreSecurityGroupsFromInstance(scope *scope.MachineScope, instanceID string) error {
+	core, err := s.GetCoreSecurityGroups(scope)
+	if err != nil {
+		return errors.Wrap(err, "failed to get core security groups to detach from instance")
+	}
+
+	enis, err := s.getInstanceENIs(instanceID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get ENIs for instance %q", instanceID)
+	}
+
+	var defaultGroup string
+	for _, eni := range enis {
+		existingGroups := make([]string, 0, len(eni.Groups))
+		for _, group := range eni.Groups {
+			existingGroups = append(existingGroups, aws.StringValue(group.GroupId))
+		}
+
+		remainingGroups := existingGroups
+		for _, group := range core {
+			remainingGroups = filterGroups(remainingGroups, group)
+		}
+
+		if len(remainingGroups) == len(existingGroups) {
+			continue
+		}
+
+		// Network interfaces need at least one security group.
+		if len(remainingGroups) == 0 {
+			if defaultGroup == "" {
+				defaultGroup, err = s.getDefaultSecurityGroupID()
+				if err != nil {
+					return err
+				}
+			}
+			remainingGroups = []string{defaultGroup}
+		}
+
+		s.scope.V(3).Info("Detaching core security groups from network interface",
+			"instance-id", instanceID, "interface-id", aws.StringValue(eni.NetworkInterfaceId), "groups", remainingGroups)
+
+		input := &ec2.ModifyNetworkInterfaceAttributeInput{
+			NetworkInterfaceId: eni.NetworkInterfaceId,
+			Groups:             aws.StringSlice(remainingGroups),
+		}
+
+		if _, err := s.scope.EC2.ModifyNetworkInterfaceAttribute(input); err != nil {
+			return errors.Wrapf(err, "failed to modify interface %q", aws.StringValue(eni.NetworkInterfaceId))
+		}
+	}
+
+	return nil
+}
+
+// getDefaultSecurityGroupID returns the ID of the default security group of the cluster VPC.
+func (s *Service) getDefaultSecurityGroupID() (string, error) {
+	input := &ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+			{
+				Name:   aws.String("group-name"),
+				Values: aws.StringSlice([]string{"default"}),
+			},
+		},
+	}
+
+	out, err := s.scope.EC2.DescribeSecurityGroups(input)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe default security group in vpc %q", s.scope.VPC().ID)
+	}
+
+	if len(out.SecurityGroups) == 0 {
+		return "", errors.Errorf("no default security group found in vpc %q", s.scope.VPC().ID)
+	}
+
+	return aws.StringValue(out.SecurityGroups[0].GroupId), nil
+}
+
 // filterGroups filters a list for a string.
 func filterGroups(list []string, strToFilter string) (newList []string) {
 	for _, item := range list {
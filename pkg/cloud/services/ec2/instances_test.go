@@ -17,7 +17,9 @@ limitations under the License.
 package ec2
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -29,6 +31,7 @@ import (
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb/mock_elbiface"
@@ -259,6 +262,220 @@ func TestTerminateInstance(t *testing.T) {
 	}
 }
 
+func TestGetAdditionalSecurityGroupsIDs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	monitoringFilters := []infrav1.Filter{
+		{
+			Name:   "tag:Name",
+			Values: []string{"corp-monitoring"},
+		},
+	}
+	describeInput := &ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: aws.StringSlice([]string{"vpc-1"}),
+			},
+			{
+				Name:   aws.String("tag:Name"),
+				Values: aws.StringSlice([]string{"corp-monitoring"}),
+			},
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		securityGroups []infrav1.AWSResourceReference
+		expect         func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectIDs      []string
+		expectErr      bool
+	}{
+		{
+			name: "by id and arn",
+			securityGroups: []infrav1.AWSResourceReference{
+				{ID: aws.String("sg-1")},
+				{ARN: aws.String("arn:aws:ec2:us-east-1:123456789012:security-group/sg-2")},
+			},
+			expect:    func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+			expectIDs: []string{"sg-1", "sg-2"},
+		},
+		{
+			name: "by filters matching one security group",
+			securityGroups: []infrav1.AWSResourceReference{
+				{Filters: monitoringFilters},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeSecurityGroups(gomock.Eq(describeInput)).
+					Return(&ec2.DescribeSecurityGroupsOutput{
+						SecurityGroups: []*ec2.SecurityGroup{
+							{GroupId: aws.String("sg-monitoring")},
+						},
+					}, nil)
+			},
+			expectIDs: []string{"sg-monitoring"},
+		},
+		{
+			name: "by filters matching no security groups",
+			securityGroups: []infrav1.AWSResourceReference{
+				{Filters: monitoringFilters},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeSecurityGroups(gomock.Eq(describeInput)).
+					Return(&ec2.DescribeSecurityGroupsOutput{}, nil)
+			},
+			expectErr: true,
+		},
+		{
+			name: "by filters matching multiple security groups",
+			securityGroups: []infrav1.AWSResourceReference{
+				{Filters: monitoringFilters},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeSecurityGroups(gomock.Eq(describeInput)).
+					Return(&ec2.DescribeSecurityGroupsOutput{
+						SecurityGroups: []*ec2.SecurityGroup{
+							{GroupId: aws.String("sg-monitoring-1")},
+							{GroupId: aws.String("sg-monitoring-2")},
+						},
+					}, nil)
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				Cluster: &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							VPC: infrav1.VPCSpec{
+								ID: "vpc-1",
+							},
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			ids, err := s.GetAdditionalSecurityGroupsIDs(tc.securityGroups)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error, got IDs %v", ids)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+			if !reflect.DeepEqual(ids, tc.expectIDs) {
+				t.Fatalf("expected IDs %v, got %v", tc.expectIDs, ids)
+			}
+		})
+	}
+}
+
+func TestUpdateInstanceMetadataOptions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name            string
+		current         *infrav1.InstanceMetadataOptions
+		desired         *infrav1.InstanceMetadataOptions
+		expect          func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectedUpdated bool
+	}{
+		{
+			name: "no desired options",
+			current: &infrav1.InstanceMetadataOptions{
+				HTTPTokens: ec2.HttpTokensStateOptional,
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+		},
+		{
+			name: "options already match",
+			current: &infrav1.InstanceMetadataOptions{
+				HTTPTokens:              ec2.HttpTokensStateRequired,
+				HTTPPutResponseHopLimit: 2,
+				HTTPEndpoint:            ec2.InstanceMetadataEndpointStateEnabled,
+			},
+			desired: &infrav1.InstanceMetadataOptions{
+				HTTPTokens:              ec2.HttpTokensStateRequired,
+				HTTPPutResponseHopLimit: 2,
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+		},
+		{
+			name: "options differ",
+			current: &infrav1.InstanceMetadataOptions{
+				HTTPTokens:              ec2.HttpTokensStateOptional,
+				HTTPPutResponseHopLimit: 1,
+				HTTPEndpoint:            ec2.InstanceMetadataEndpointStateEnabled,
+			},
+			desired: &infrav1.InstanceMetadataOptions{
+				HTTPTokens:              ec2.HttpTokensStateRequired,
+				HTTPPutResponseHopLimit: 2,
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.ModifyInstanceMetadataOptions(gomock.Eq(&ec2.ModifyInstanceMetadataOptionsInput{
+					InstanceId:              aws.String("i-1"),
+					HttpTokens:              aws.String(ec2.HttpTokensStateRequired),
+					HttpPutResponseHopLimit: aws.Int64(2),
+				})).
+					Return(&ec2.ModifyInstanceMetadataOptionsOutput{}, nil)
+			},
+			expectedUpdated: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			instance := &infrav1.Instance{
+				ID:                      "i-1",
+				InstanceMetadataOptions: tc.current,
+			}
+			updated, err := s.UpdateInstanceMetadataOptions(instance, tc.desired)
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+
+			if updated != tc.expectedUpdated {
+				t.Fatalf("expected updated to be %v, got %v", tc.expectedUpdated, updated)
+			}
+		})
+	}
+}
+
 func TestCreateInstance(t *testing.T) {
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -274,6 +491,7 @@ func TestCreateInstance(t *testing.T) {
 		machine       clusterv1.Machine
 		machineConfig *infrav1.AWSMachineSpec
 		awsCluster    *infrav1.AWSCluster
+		resolveAMI    fakeAMIResolver
 		expect        func(m *mock_ec2iface.MockEC2APIMockRecorder)
 		check         func(instance *infrav1.Instance, err error)
 	}{
@@ -329,12 +547,24 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypes(gomock.Any()).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
+							},
+						},
+					}, nil)
 				m.
 					DescribeImages(gomock.Any()).
 					Return(&ec2.DescribeImagesOutput{
 						Images: []*ec2.Image{
 							{
-								Name: aws.String("ami-1"),
+								Name:         aws.String("ami-1"),
+								Architecture: aws.String("x86_64"),
 							},
 						},
 					}, nil)
@@ -376,7 +606,7 @@ func TestCreateInstance(t *testing.T) {
 			},
 		},
 		{
-			name: "with availability zone",
+			name: "with additional volumes",
 			machine: clusterv1.Machine{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: map[string]string{"set": "node"},
@@ -391,32 +621,27 @@ func TestCreateInstance(t *testing.T) {
 				AMI: infrav1.AWSResourceReference{
 					ID: aws.String("abc"),
 				},
-				InstanceType:  "m5.2xlarge",
-				FailureDomain: aws.String("us-east-1c"),
+				InstanceType: "m5.large",
+				AdditionalVolumes: []infrav1.Volume{
+					{
+						DeviceName:    "/dev/sdb",
+						Size:          20,
+						Type:          "io1",
+						IOPS:          1000,
+						EncryptionKey: "key",
+					},
+				},
 			},
 			awsCluster: &infrav1.AWSCluster{
 				Spec: infrav1.AWSClusterSpec{
 					NetworkSpec: infrav1.NetworkSpec{
 						Subnets: infrav1.Subnets{
 							&infrav1.SubnetSpec{
-								ID:               "subnet-1",
-								AvailabilityZone: "us-east-1a",
-								IsPublic:         false,
-							},
-							&infrav1.SubnetSpec{
-								ID:               "subnet-2",
-								AvailabilityZone: "us-east-1b",
-								IsPublic:         false,
-							},
-							&infrav1.SubnetSpec{
-								ID:               "subnet-3",
-								AvailabilityZone: "us-east-1c",
-								IsPublic:         false,
+								ID:       "subnet-1",
+								IsPublic: false,
 							},
 							&infrav1.SubnetSpec{
-								ID:               "subnet-3-public",
-								AvailabilityZone: "us-east-1c",
-								IsPublic:         true,
+								IsPublic: false,
 							},
 						},
 					},
@@ -441,18 +666,47 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypes(gomock.Any()).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
+							},
+						},
+					}, nil)
 				m.
 					DescribeImages(gomock.Any()).
 					Return(&ec2.DescribeImagesOutput{
 						Images: []*ec2.Image{
 							{
-								Name: aws.String("ami-1"),
+								Name:         aws.String("ami-1"),
+								Architecture: aws.String("x86_64"),
 							},
 						},
 					}, nil)
-
 				m.
 					RunInstances(gomock.Any()).
+					Do(func(input *ec2.RunInstancesInput) {
+						expected := []*ec2.BlockDeviceMapping{
+							{
+								DeviceName: aws.String("/dev/sdb"),
+								Ebs: &ec2.EbsBlockDevice{
+									DeleteOnTermination: aws.Bool(true),
+									VolumeSize:          aws.Int64(20),
+									VolumeType:          aws.String("io1"),
+									Iops:                aws.Int64(1000),
+									Encrypted:           aws.Bool(true),
+									KmsKeyId:            aws.String("key"),
+								},
+							},
+						}
+						if !reflect.DeepEqual(input.BlockDeviceMappings, expected) {
+							t.Fatalf("expected block device mappings %v, got %v", expected, input.BlockDeviceMappings)
+						}
+					}).
 					Return(&ec2.Reservation{
 						Instances: []*ec2.Instance{
 							{
@@ -464,7 +718,7 @@ func TestCreateInstance(t *testing.T) {
 								},
 								InstanceId:     aws.String("two"),
 								InstanceType:   aws.String("m5.large"),
-								SubnetId:       aws.String("subnet-3"),
+								SubnetId:       aws.String("subnet-1"),
 								ImageId:        aws.String("ami-1"),
 								RootDeviceName: aws.String("device-1"),
 								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
@@ -474,11 +728,16 @@ func TestCreateInstance(t *testing.T) {
 											VolumeId: aws.String("volume-1"),
 										},
 									},
+									{
+										DeviceName: aws.String("/dev/sdb"),
+										Ebs: &ec2.EbsInstanceBlockDevice{
+											VolumeId: aws.String("volume-2"),
+										},
+									},
 								},
 							},
 						},
 					}, nil)
-
 				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil)
 
@@ -488,13 +747,17 @@ func TestCreateInstance(t *testing.T) {
 					t.Fatalf("did not expect error: %v", err)
 				}
 
-				if instance.SubnetID != "subnet-3" {
-					t.Fatalf("expected subnet-3 from availability zone us-east-1c, got %q", instance.SubnetID)
+				expected := []infrav1.VolumeAttachment{
+					{DeviceName: "device-1", VolumeID: "volume-1"},
+					{DeviceName: "/dev/sdb", VolumeID: "volume-2"},
+				}
+				if !reflect.DeepEqual(instance.VolumeAttachments, expected) {
+					t.Fatalf("expected volume attachments %v, got %v", expected, instance.VolumeAttachments)
 				}
 			},
 		},
 		{
-			name: "with ImageLookupOrg specified at the machine level",
+			name: "with spot market options",
 			machine: clusterv1.Machine{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: map[string]string{"set": "node"},
@@ -503,12 +766,17 @@ func TestCreateInstance(t *testing.T) {
 					Bootstrap: clusterv1.Bootstrap{
 						DataSecretName: pointer.StringPtr("bootstrap-data"),
 					},
-					Version: pointer.StringPtr("v1.16.1"),
 				},
 			},
 			machineConfig: &infrav1.AWSMachineSpec{
-				ImageLookupOrg: "test-org-123",
-				InstanceType:   "m5.large",
+				AMI: infrav1.AWSResourceReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				SpotMarketOptions: &infrav1.SpotMarketOptions{
+					MaxPrice:             aws.String("0.05"),
+					InterruptionBehavior: infrav1.SpotInterruptionBehaviorStop,
+				},
 			},
 			awsCluster: &infrav1.AWSCluster{
 				Spec: infrav1.AWSClusterSpec{
@@ -544,42 +812,42 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
-				// verify that the ImageLookupOrg is used when finding AMIs
 				m.
-					DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
-						Filters: []*ec2.Filter{
-							{
-								Name:   aws.String("owner-id"),
-								Values: []*string{aws.String("test-org-123")},
-							},
-							{
-								Name:   aws.String("name"),
-								Values: []*string{aws.String(amiName("ubuntu-18.04", "v1.16.1"))},
-							},
-							{
-								Name:   aws.String("architecture"),
-								Values: []*string{aws.String("x86_64")},
-							},
-							{
-								Name:   aws.String("state"),
-								Values: []*string{aws.String("available")},
-							},
+					DescribeInstanceTypes(gomock.Any()).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
 							{
-								Name:   aws.String("virtualization-type"),
-								Values: []*string{aws.String("hvm")},
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
 							},
 						},
-					})).
+					}, nil)
+				m.
+					DescribeImages(gomock.Any()).
 					Return(&ec2.DescribeImagesOutput{
 						Images: []*ec2.Image{
 							{
 								Name:         aws.String("ami-1"),
-								CreationDate: aws.String("2006-01-02T15:04:05.000Z"),
+								Architecture: aws.String("x86_64"),
 							},
 						},
 					}, nil)
-				m. // TODO: Restore these parameters, but with the tags as well
+				m.
 					RunInstances(gomock.Any()).
+					Do(func(input *ec2.RunInstancesInput) {
+						expected := &ec2.InstanceMarketOptionsRequest{
+							MarketType: aws.String(ec2.MarketTypeSpot),
+							SpotOptions: &ec2.SpotMarketOptions{
+								MaxPrice:                     aws.String("0.05"),
+								InstanceInterruptionBehavior: aws.String("stop"),
+								SpotInstanceType:             aws.String(ec2.SpotInstanceTypePersistent),
+							},
+						}
+						if !reflect.DeepEqual(input.InstanceMarketOptions, expected) {
+							t.Fatalf("expected instance market options %v, got %v", expected, input.InstanceMarketOptions)
+						}
+					}).
 					Return(&ec2.Reservation{
 						Instances: []*ec2.Instance{
 							{
@@ -589,11 +857,13 @@ func TestCreateInstance(t *testing.T) {
 								IamInstanceProfile: &ec2.IamInstanceProfile{
 									Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
 								},
-								InstanceId:     aws.String("two"),
-								InstanceType:   aws.String("m5.large"),
-								SubnetId:       aws.String("subnet-1"),
-								ImageId:        aws.String("ami-1"),
-								RootDeviceName: aws.String("device-1"),
+								InstanceId:            aws.String("two"),
+								InstanceLifecycle:     aws.String(ec2.InstanceLifecycleTypeSpot),
+								SpotInstanceRequestId: aws.String("sir-1"),
+								InstanceType:          aws.String("m5.large"),
+								SubnetId:              aws.String("subnet-1"),
+								ImageId:               aws.String("ami-1"),
+								RootDeviceName:        aws.String("device-1"),
 								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
 									{
 										DeviceName: aws.String("device-1"),
@@ -605,7 +875,6 @@ func TestCreateInstance(t *testing.T) {
 							},
 						},
 					}, nil)
-
 				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil)
 
@@ -614,10 +883,18 @@ func TestCreateInstance(t *testing.T) {
 				if err != nil {
 					t.Fatalf("did not expect error: %v", err)
 				}
+
+				if instance.Lifecycle != infrav1.InstanceLifecycleSpot {
+					t.Fatalf("expected spot lifecycle, got %q", instance.Lifecycle)
+				}
+
+				if aws.StringValue(instance.SpotInstanceRequestID) != "sir-1" {
+					t.Fatalf("expected spot instance request id sir-1, got %v", instance.SpotInstanceRequestID)
+				}
 			},
 		},
 		{
-			name: "with ImageLookupOrg specified at the cluster-level",
+			name: "with availability zone",
 			machine: clusterv1.Machine{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: map[string]string{"set": "node"},
@@ -626,26 +903,41 @@ func TestCreateInstance(t *testing.T) {
 					Bootstrap: clusterv1.Bootstrap{
 						DataSecretName: pointer.StringPtr("bootstrap-data"),
 					},
-					Version: pointer.StringPtr("v1.16.1"),
 				},
 			},
 			machineConfig: &infrav1.AWSMachineSpec{
-				InstanceType: "m5.large",
+				AMI: infrav1.AWSResourceReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType:  "m5.2xlarge",
+				FailureDomain: aws.String("us-east-1c"),
 			},
 			awsCluster: &infrav1.AWSCluster{
 				Spec: infrav1.AWSClusterSpec{
 					NetworkSpec: infrav1.NetworkSpec{
 						Subnets: infrav1.Subnets{
 							&infrav1.SubnetSpec{
-								ID:       "subnet-1",
-								IsPublic: false,
+								ID:               "subnet-1",
+								AvailabilityZone: "us-east-1a",
+								IsPublic:         false,
 							},
 							&infrav1.SubnetSpec{
-								IsPublic: false,
+								ID:               "subnet-2",
+								AvailabilityZone: "us-east-1b",
+								IsPublic:         false,
+							},
+							&infrav1.SubnetSpec{
+								ID:               "subnet-3",
+								AvailabilityZone: "us-east-1c",
+								IsPublic:         false,
+							},
+							&infrav1.SubnetSpec{
+								ID:               "subnet-3-public",
+								AvailabilityZone: "us-east-1c",
+								IsPublic:         true,
 							},
 						},
 					},
-					ImageLookupOrg: "cluster-level-image-lookup-org",
 				},
 				Status: infrav1.AWSClusterStatus{
 					Network: infrav1.Network{
@@ -667,80 +959,1455 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
-				// verify that the ImageLookupOrg is used when finding AMIs
 				m.
-					DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
-						Filters: []*ec2.Filter{
-							{
-								Name:   aws.String("owner-id"),
-								Values: []*string{aws.String("cluster-level-image-lookup-org")},
-							},
-							{
-								Name:   aws.String("name"),
-								Values: []*string{aws.String(amiName("ubuntu-18.04", "v1.16.1"))},
-							},
-							{
-								Name:   aws.String("architecture"),
-								Values: []*string{aws.String("x86_64")},
-							},
-							{
-								Name:   aws.String("state"),
-								Values: []*string{aws.String("available")},
-							},
+					DescribeInstanceTypes(gomock.Any()).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
 							{
-								Name:   aws.String("virtualization-type"),
-								Values: []*string{aws.String("hvm")},
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
 							},
 						},
-					})).
+					}, nil)
+				m.
+					DescribeImages(gomock.Any()).
 					Return(&ec2.DescribeImagesOutput{
 						Images: []*ec2.Image{
 							{
 								Name:         aws.String("ami-1"),
-								CreationDate: aws.String("2006-01-02T15:04:05.000Z"),
+								Architecture: aws.String("x86_64"),
 							},
 						},
 					}, nil)
-				m. // TODO: Restore these parameters, but with the tags as well
-					RunInstances(gomock.Any()).
+
+				m.
+					RunInstances(gomock.Any()).
+					Return(&ec2.Reservation{
+						Instances: []*ec2.Instance{
+							{
+								State: &ec2.InstanceState{
+									Name: aws.String(ec2.InstanceStateNamePending),
+								},
+								IamInstanceProfile: &ec2.IamInstanceProfile{
+									Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
+								},
+								InstanceId:     aws.String("two"),
+								InstanceType:   aws.String("m5.large"),
+								SubnetId:       aws.String("subnet-3"),
+								ImageId:        aws.String("ami-1"),
+								RootDeviceName: aws.String("device-1"),
+								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+									{
+										DeviceName: aws.String("device-1"),
+										Ebs: &ec2.EbsInstanceBlockDevice{
+											VolumeId: aws.String("volume-1"),
+										},
+									},
+								},
+							},
+						},
+					}, nil)
+
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+
+				if instance.SubnetID != "subnet-3" {
+					t.Fatalf("expected subnet-3 from availability zone us-east-1c, got %q", instance.SubnetID)
+				}
+			},
+		},
+		{
+			name: "with ImageLookupOrg specified at the machine level",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+					Version: pointer.StringPtr("v1.16.1"),
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				ImageLookupOrg: "test-org-123",
+				InstanceType:   "m5.large",
+			},
+			awsCluster: &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+							&infrav1.SubnetSpec{
+								IsPublic: false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			// verify that the ImageLookupOrg is used when looking up AMIs
+			resolveAMI: func(lookup *AMILookup) (string, error) {
+				if lookup.OwnerID != "test-org-123" {
+					t.Errorf("expected lookup owner ID %q, got %q", "test-org-123", lookup.OwnerID)
+				}
+				return "ami-1", nil
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypes(gomock.Any()).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
+							},
+						},
+					}, nil)
+				m.
+					DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
+						ImageIds: aws.StringSlice([]string{"ami-1"}),
+					})).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								ImageId:      aws.String("ami-1"),
+								Architecture: aws.String("x86_64"),
+							},
+						},
+					}, nil)
+				m. // TODO: Restore these parameters, but with the tags as well
+					RunInstances(gomock.Any()).
+					Return(&ec2.Reservation{
+						Instances: []*ec2.Instance{
+							{
+								State: &ec2.InstanceState{
+									Name: aws.String(ec2.InstanceStateNamePending),
+								},
+								IamInstanceProfile: &ec2.IamInstanceProfile{
+									Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
+								},
+								InstanceId:     aws.String("two"),
+								InstanceType:   aws.String("m5.large"),
+								SubnetId:       aws.String("subnet-1"),
+								ImageId:        aws.String("ami-1"),
+								RootDeviceName: aws.String("device-1"),
+								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+									{
+										DeviceName: aws.String("device-1"),
+										Ebs: &ec2.EbsInstanceBlockDevice{
+											VolumeId: aws.String("volume-1"),
+										},
+									},
+								},
+							},
+						},
+					}, nil)
+
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+			},
+		},
+		{
+			name: "with ImageLookupOrg specified at the cluster-level",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+					Version: pointer.StringPtr("v1.16.1"),
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				InstanceType: "m5.large",
+			},
+			awsCluster: &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+							&infrav1.SubnetSpec{
+								IsPublic: false,
+							},
+						},
+					},
+					ImageLookupOrg: "cluster-level-image-lookup-org",
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			// verify that the ImageLookupOrg is used when looking up AMIs
+			resolveAMI: func(lookup *AMILookup) (string, error) {
+				if lookup.OwnerID != "cluster-level-image-lookup-org" {
+					t.Errorf("expected lookup owner ID %q, got %q", "cluster-level-image-lookup-org", lookup.OwnerID)
+				}
+				return "ami-1", nil
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypes(gomock.Any()).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
+							},
+						},
+					}, nil)
+				m.
+					DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
+						ImageIds: aws.StringSlice([]string{"ami-1"}),
+					})).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								ImageId:      aws.String("ami-1"),
+								Architecture: aws.String("x86_64"),
+							},
+						},
+					}, nil)
+				m. // TODO: Restore these parameters, but with the tags as well
+					RunInstances(gomock.Any()).
+					Return(&ec2.Reservation{
+						Instances: []*ec2.Instance{
+							{
+								State: &ec2.InstanceState{
+									Name: aws.String(ec2.InstanceStateNamePending),
+								},
+								IamInstanceProfile: &ec2.IamInstanceProfile{
+									Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
+								},
+								InstanceId:     aws.String("two"),
+								InstanceType:   aws.String("m5.large"),
+								SubnetId:       aws.String("subnet-1"),
+								ImageId:        aws.String("ami-1"),
+								RootDeviceName: aws.String("device-1"),
+								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+									{
+										DeviceName: aws.String("device-1"),
+										Ebs: &ec2.EbsInstanceBlockDevice{
+											VolumeId: aws.String("volume-1"),
+										},
+									},
+								},
+							},
+						},
+					}, nil)
+
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+			},
+		},
+		{
+			name: "AWSMachine ImageLookupOrg overrides AWSCluster ImageLookupOrg",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+					Version: pointer.StringPtr("v1.16.1"),
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				InstanceType:   "m5.large",
+				ImageLookupOrg: "machine-level-image-lookup-org",
+			},
+			awsCluster: &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+							&infrav1.SubnetSpec{
+								IsPublic: false,
+							},
+						},
+					},
+					ImageLookupOrg: "cluster-level-image-lookup-org",
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			// verify that the ImageLookupOrg is used when looking up AMIs
+			resolveAMI: func(lookup *AMILookup) (string, error) {
+				if lookup.OwnerID != "machine-level-image-lookup-org" {
+					t.Errorf("expected lookup owner ID %q, got %q", "machine-level-image-lookup-org", lookup.OwnerID)
+				}
+				return "ami-1", nil
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypes(gomock.Any()).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
+							},
+						},
+					}, nil)
+				m.
+					DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
+						ImageIds: aws.StringSlice([]string{"ami-1"}),
+					})).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								ImageId:      aws.String("ami-1"),
+								Architecture: aws.String("x86_64"),
+							},
+						},
+					}, nil)
+				m. // TODO: Restore these parameters, but with the tags as well
+					RunInstances(gomock.Any()).
+					Return(&ec2.Reservation{
+						Instances: []*ec2.Instance{
+							{
+								State: &ec2.InstanceState{
+									Name: aws.String(ec2.InstanceStateNamePending),
+								},
+								IamInstanceProfile: &ec2.IamInstanceProfile{
+									Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
+								},
+								InstanceId:     aws.String("two"),
+								InstanceType:   aws.String("m5.large"),
+								SubnetId:       aws.String("subnet-1"),
+								ImageId:        aws.String("ami-1"),
+								RootDeviceName: aws.String("device-1"),
+								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+									{
+										DeviceName: aws.String("device-1"),
+										Ebs: &ec2.EbsInstanceBlockDevice{
+											VolumeId: aws.String("volume-1"),
+										},
+									},
+								},
+							},
+						},
+					}, nil)
+
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+			},
+		},
+		{
+			name: "with an arm64 instance type",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+					Version: pointer.StringPtr("v1.16.1"),
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				InstanceType: "m6g.large",
+			},
+			awsCluster: &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			// verify that the architecture of the instance type is used when looking up AMIs
+			resolveAMI: func(lookup *AMILookup) (string, error) {
+				if lookup.Architecture != "arm64" {
+					t.Errorf("expected lookup Architecture %q, got %q", "arm64", lookup.Architecture)
+				}
+				return "ami-arm64", nil
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypes(gomock.Eq(&ec2.DescribeInstanceTypesInput{
+						InstanceTypes: aws.StringSlice([]string{"m6g.large"}),
+					})).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"arm64"}),
+								},
+							},
+						},
+					}, nil)
+				m.
+					DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
+						ImageIds: aws.StringSlice([]string{"ami-arm64"}),
+					})).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								ImageId:      aws.String("ami-arm64"),
+								Architecture: aws.String("arm64"),
+							},
+						},
+					}, nil)
+				m.
+					RunInstances(gomock.Any()).
+					Return(&ec2.Reservation{
+						Instances: []*ec2.Instance{
+							{
+								State: &ec2.InstanceState{
+									Name: aws.String(ec2.InstanceStateNamePending),
+								},
+								InstanceId:     aws.String("two"),
+								InstanceType:   aws.String("m6g.large"),
+								Architecture:   aws.String("arm64"),
+								SubnetId:       aws.String("subnet-1"),
+								ImageId:        aws.String("ami-arm64"),
+								RootDeviceName: aws.String("device-1"),
+								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+									{
+										DeviceName: aws.String("device-1"),
+										Ebs: &ec2.EbsInstanceBlockDevice{
+											VolumeId: aws.String("volume-1"),
+										},
+									},
+								},
+							},
+						},
+					}, nil)
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+
+				if instance.Architecture != "arm64" {
+					t.Fatalf("expected architecture arm64, got %q", instance.Architecture)
+				}
+			},
+		},
+		{
+			name: "with fallback instance types on insufficient capacity",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AWSResourceReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType:          "m5.large",
+				FallbackInstanceTypes: []string{"m5.large", "m5a.large"},
+			},
+			awsCluster: &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypes(gomock.Eq(&ec2.DescribeInstanceTypesInput{
+						InstanceTypes: aws.StringSlice([]string{"m5.large"}),
+					})).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
+							},
+						},
+					}, nil)
+				m.
+					DescribeImages(gomock.Any()).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								Name:         aws.String("ami-1"),
+								Architecture: aws.String("x86_64"),
+							},
+						},
+					}, nil)
+				m.
+					DescribeInstanceTypes(gomock.Eq(&ec2.DescribeInstanceTypesInput{
+						InstanceTypes: aws.StringSlice([]string{"m5a.large"}),
+					})).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
+							},
+						},
+					}, nil)
+				m.
+					RunInstances(gomock.Any()).
+					Return(nil, awserr.New(awserrors.InsufficientInstanceCapacity, "insufficient capacity", nil))
+				m.
+					RunInstances(gomock.Any()).
+					Do(func(input *ec2.RunInstancesInput) {
+						if aws.StringValue(input.InstanceType) != "m5a.large" {
+							t.Fatalf("expected fallback instance type m5a.large, got %q", aws.StringValue(input.InstanceType))
+						}
+					}).
+					Return(&ec2.Reservation{
+						Instances: []*ec2.Instance{
+							{
+								State: &ec2.InstanceState{
+									Name: aws.String(ec2.InstanceStateNamePending),
+								},
+								InstanceId:     aws.String("two"),
+								InstanceType:   aws.String("m5a.large"),
+								SubnetId:       aws.String("subnet-1"),
+								ImageId:        aws.String("ami-1"),
+								RootDeviceName: aws.String("device-1"),
+								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+									{
+										DeviceName: aws.String("device-1"),
+										Ebs: &ec2.EbsInstanceBlockDevice{
+											VolumeId: aws.String("volume-1"),
+										},
+									},
+								},
+							},
+						},
+					}, nil)
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+
+				if instance.Type != "m5a.large" {
+					t.Fatalf("expected instance type m5a.large, got %q", instance.Type)
+				}
+			},
+		},
+		{
+			name: "with fallback failure domains on insufficient capacity",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AWSResourceReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType:           "m5.large",
+				FailureDomain:          aws.String("us-east-1a"),
+				FallbackFailureDomains: []string{"us-east-1b"},
+			},
+			awsCluster: &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								ID:               "subnet-1",
+								AvailabilityZone: "us-east-1a",
+								IsPublic:         false,
+							},
+							&infrav1.SubnetSpec{
+								ID:               "subnet-2",
+								AvailabilityZone: "us-east-1b",
+								IsPublic:         false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypes(gomock.Any()).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
+							},
+						},
+					}, nil)
+				m.
+					DescribeImages(gomock.Any()).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								Name:         aws.String("ami-1"),
+								Architecture: aws.String("x86_64"),
+							},
+						},
+					}, nil)
+				m.
+					RunInstances(gomock.Any()).
+					Return(nil, awserr.New(awserrors.InsufficientInstanceCapacity, "insufficient capacity", nil))
+				m.
+					RunInstances(gomock.Any()).
+					Do(func(input *ec2.RunInstancesInput) {
+						if aws.StringValue(input.SubnetId) != "subnet-2" {
+							t.Fatalf("expected fallback subnet subnet-2, got %q", aws.StringValue(input.SubnetId))
+						}
+					}).
+					Return(&ec2.Reservation{
+						Instances: []*ec2.Instance{
+							{
+								State: &ec2.InstanceState{
+									Name: aws.String(ec2.InstanceStateNamePending),
+								},
+								InstanceId:     aws.String("two"),
+								InstanceType:   aws.String("m5.large"),
+								SubnetId:       aws.String("subnet-2"),
+								ImageId:        aws.String("ami-1"),
+								RootDeviceName: aws.String("device-1"),
+								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+									{
+										DeviceName: aws.String("device-1"),
+										Ebs: &ec2.EbsInstanceBlockDevice{
+											VolumeId: aws.String("volume-1"),
+										},
+									},
+								},
+							},
+						},
+					}, nil)
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+
+				if instance.SubnetID != "subnet-2" {
+					t.Fatalf("expected subnet-2 from fallback availability zone us-east-1b, got %q", instance.SubnetID)
+				}
+			},
+		},
+		{
+			name: "with several subnets in the availability zone, picks the least utilized",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AWSResourceReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType:  "m5.large",
+				FailureDomain: aws.String("us-east-1a"),
+			},
+			awsCluster: &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								ID:               "subnet-1",
+								AvailabilityZone: "us-east-1a",
+								IsPublic:         false,
+							},
+							&infrav1.SubnetSpec{
+								ID:               "subnet-2",
+								AvailabilityZone: "us-east-1a",
+								IsPublic:         false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypes(gomock.Any()).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
+							},
+						},
+					}, nil)
+				m.
+					DescribeImages(gomock.Any()).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								Name:         aws.String("ami-1"),
+								Architecture: aws.String("x86_64"),
+							},
+						},
+					}, nil)
+				m.
+					DescribeSubnets(gomock.Eq(&ec2.DescribeSubnetsInput{
+						SubnetIds: aws.StringSlice([]string{"subnet-1", "subnet-2"}),
+					})).
+					Return(&ec2.DescribeSubnetsOutput{
+						Subnets: []*ec2.Subnet{
+							{
+								SubnetId:                aws.String("subnet-1"),
+								AvailabilityZone:        aws.String("us-east-1a"),
+								AvailableIpAddressCount: aws.Int64(10),
+							},
+							{
+								SubnetId:                aws.String("subnet-2"),
+								AvailabilityZone:        aws.String("us-east-1a"),
+								AvailableIpAddressCount: aws.Int64(200),
+							},
+						},
+					}, nil)
+				m.
+					RunInstances(gomock.Any()).
+					Do(func(input *ec2.RunInstancesInput) {
+						if aws.StringValue(input.SubnetId) != "subnet-2" {
+							t.Fatalf("expected subnet subnet-2, got %q", aws.StringValue(input.SubnetId))
+						}
+					}).
+					Return(&ec2.Reservation{
+						Instances: []*ec2.Instance{
+							{
+								State: &ec2.InstanceState{
+									Name: aws.String(ec2.InstanceStateNamePending),
+								},
+								InstanceId:     aws.String("two"),
+								InstanceType:   aws.String("m5.large"),
+								SubnetId:       aws.String("subnet-2"),
+								ImageId:        aws.String("ami-1"),
+								RootDeviceName: aws.String("device-1"),
+								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+									{
+										DeviceName: aws.String("device-1"),
+										Ebs: &ec2.EbsInstanceBlockDevice{
+											VolumeId: aws.String("volume-1"),
+										},
+									},
+								},
+							},
+						},
+					}, nil)
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+			},
+		},
+		{
+			name: "with subnet filters",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AWSResourceReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				Subnet: &infrav1.AWSResourceReference{
+					Filters: []infrav1.Filter{
+						{
+							Name:   "tag:Name",
+							Values: []string{"workers-*"},
+						},
+					},
+				},
+			},
+			awsCluster: &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						VPC: infrav1.VPCSpec{
+							ID: "vpc-1",
+						},
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypes(gomock.Any()).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
+							},
+						},
+					}, nil)
+				m.
+					DescribeImages(gomock.Any()).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								Name:         aws.String("ami-1"),
+								Architecture: aws.String("x86_64"),
+							},
+						},
+					}, nil)
+				m.
+					DescribeSubnets(gomock.Eq(&ec2.DescribeSubnetsInput{
+						Filters: []*ec2.Filter{
+							{
+								Name:   aws.String("vpc-id"),
+								Values: aws.StringSlice([]string{"vpc-1"}),
+							},
+							{
+								Name:   aws.String("tag:Name"),
+								Values: aws.StringSlice([]string{"workers-*"}),
+							},
+						},
+					})).
+					Return(&ec2.DescribeSubnetsOutput{
+						Subnets: []*ec2.Subnet{
+							{
+								SubnetId:                aws.String("subnet-workers-a"),
+								AvailabilityZone:        aws.String("us-east-1a"),
+								AvailableIpAddressCount: aws.Int64(50),
+							},
+							{
+								SubnetId:                aws.String("subnet-workers-b"),
+								AvailabilityZone:        aws.String("us-east-1b"),
+								AvailableIpAddressCount: aws.Int64(60),
+							},
+						},
+					}, nil)
+				m.
+					RunInstances(gomock.Any()).
+					Do(func(input *ec2.RunInstancesInput) {
+						if aws.StringValue(input.SubnetId) != "subnet-workers-b" {
+							t.Fatalf("expected subnet subnet-workers-b, got %q", aws.StringValue(input.SubnetId))
+						}
+					}).
+					Return(&ec2.Reservation{
+						Instances: []*ec2.Instance{
+							{
+								State: &ec2.InstanceState{
+									Name: aws.String(ec2.InstanceStateNamePending),
+								},
+								InstanceId:     aws.String("two"),
+								InstanceType:   aws.String("m5.large"),
+								SubnetId:       aws.String("subnet-workers-b"),
+								ImageId:        aws.String("ami-1"),
+								RootDeviceName: aws.String("device-1"),
+								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+									{
+										DeviceName: aws.String("device-1"),
+										Ebs: &ec2.EbsInstanceBlockDevice{
+											VolumeId: aws.String("volume-1"),
+										},
+									},
+								},
+							},
+						},
+					}, nil)
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+			},
+		},
+		{
+			name: "with an AMI that does not match the instance type architecture",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AWSResourceReference{
+					ID: aws.String("ami-x86"),
+				},
+				InstanceType: "m6g.large",
+			},
+			awsCluster: &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypes(gomock.Any()).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"arm64"}),
+								},
+							},
+						},
+					}, nil)
+				m.
+					DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
+						ImageIds: aws.StringSlice([]string{"ami-x86"}),
+					})).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								ImageId:      aws.String("ami-x86"),
+								Architecture: aws.String("x86_64"),
+							},
+						},
+					}, nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err == nil {
+					t.Fatalf("expected an architecture mismatch error, got instance %v", instance)
+				}
+			},
+		},
+		{
+			name: "with a looked up AMI that does not match the instance type architecture",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+					Version: pointer.StringPtr("v1.16.1"),
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				InstanceType: "m6g.large",
+			},
+			awsCluster: &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			// an SSM parameter or name format may resolve to an AMI of any architecture
+			resolveAMI: func(lookup *AMILookup) (string, error) {
+				return "ami-x86", nil
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypes(gomock.Any()).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"arm64"}),
+								},
+							},
+						},
+					}, nil)
+				m.
+					DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
+						ImageIds: aws.StringSlice([]string{"ami-x86"}),
+					})).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								ImageId:      aws.String("ami-x86"),
+								Architecture: aws.String("x86_64"),
+							},
+						},
+					}, nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err == nil {
+					t.Fatalf("expected an architecture mismatch error, got instance %v", instance)
+				}
+			},
+		},
+		{
+			name: "with a static private IP address",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AWSResourceReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType:     "m5.large",
+				PrivateIPAddress: aws.String("10.0.1.10"),
+			},
+			awsCluster: &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypes(gomock.Any()).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
+							},
+						},
+					}, nil)
+				m.
+					DescribeImages(gomock.Any()).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								Name:         aws.String("ami-1"),
+								Architecture: aws.String("x86_64"),
+							},
+						},
+					}, nil)
+				m.
+					RunInstances(gomock.Any()).
+					Do(func(input *ec2.RunInstancesInput) {
+						if aws.StringValue(input.PrivateIpAddress) != "10.0.1.10" {
+							t.Fatalf("expected private IP address 10.0.1.10, got %q", aws.StringValue(input.PrivateIpAddress))
+						}
+					}).
+					Return(&ec2.Reservation{
+						Instances: []*ec2.Instance{
+							{
+								State: &ec2.InstanceState{
+									Name: aws.String(ec2.InstanceStateNamePending),
+								},
+								InstanceId:       aws.String("two"),
+								InstanceType:     aws.String("m5.large"),
+								SubnetId:         aws.String("subnet-1"),
+								ImageId:          aws.String("ami-1"),
+								PrivateIpAddress: aws.String("10.0.1.10"),
+							},
+						},
+					}, nil)
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+				if aws.StringValue(instance.PrivateIP) != "10.0.1.10" {
+					t.Fatalf("expected private IP address 10.0.1.10, got %q", aws.StringValue(instance.PrivateIP))
+				}
+			},
+		},
+		{
+			name: "with a capacity reservation target",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AWSResourceReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				CapacityReservation: &infrav1.CapacityReservation{
+					ID: aws.String("cr-1"),
+				},
+			},
+			awsCluster: &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypes(gomock.Any()).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
+							},
+						},
+					}, nil)
+				m.
+					DescribeImages(gomock.Any()).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								Name:         aws.String("ami-1"),
+								Architecture: aws.String("x86_64"),
+							},
+						},
+					}, nil)
+				m.
+					RunInstances(gomock.Any()).
+					Do(func(input *ec2.RunInstancesInput) {
+						expected := &ec2.CapacityReservationSpecification{
+							CapacityReservationTarget: &ec2.CapacityReservationTarget{
+								CapacityReservationId: aws.String("cr-1"),
+							},
+						}
+						if !reflect.DeepEqual(input.CapacityReservationSpecification, expected) {
+							t.Fatalf("expected capacity reservation specification %v, got %v", expected, input.CapacityReservationSpecification)
+						}
+					}).
 					Return(&ec2.Reservation{
 						Instances: []*ec2.Instance{
 							{
 								State: &ec2.InstanceState{
 									Name: aws.String(ec2.InstanceStateNamePending),
 								},
-								IamInstanceProfile: &ec2.IamInstanceProfile{
-									Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
-								},
-								InstanceId:     aws.String("two"),
-								InstanceType:   aws.String("m5.large"),
-								SubnetId:       aws.String("subnet-1"),
-								ImageId:        aws.String("ami-1"),
-								RootDeviceName: aws.String("device-1"),
-								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
-									{
-										DeviceName: aws.String("device-1"),
-										Ebs: &ec2.EbsInstanceBlockDevice{
-											VolumeId: aws.String("volume-1"),
-										},
-									},
-								},
+								InstanceId:            aws.String("two"),
+								InstanceType:          aws.String("m5.large"),
+								SubnetId:              aws.String("subnet-1"),
+								ImageId:               aws.String("ami-1"),
+								CapacityReservationId: aws.String("cr-1"),
 							},
 						},
 					}, nil)
-
 				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil)
-
 			},
 			check: func(instance *infrav1.Instance, err error) {
 				if err != nil {
 					t.Fatalf("did not expect error: %v", err)
 				}
+				if aws.StringValue(instance.CapacityReservationID) != "cr-1" {
+					t.Fatalf("expected capacity reservation cr-1, got %q", aws.StringValue(instance.CapacityReservationID))
+				}
 			},
 		},
 		{
-			name: "AWSMachine ImageLookupOrg overrides AWSCluster ImageLookupOrg",
+			name: "with managed network interfaces",
 			machine: clusterv1.Machine{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: map[string]string{"set": "node"},
@@ -749,12 +2416,16 @@ func TestCreateInstance(t *testing.T) {
 					Bootstrap: clusterv1.Bootstrap{
 						DataSecretName: pointer.StringPtr("bootstrap-data"),
 					},
-					Version: pointer.StringPtr("v1.16.1"),
 				},
 			},
 			machineConfig: &infrav1.AWSMachineSpec{
-				InstanceType:   "m5.large",
-				ImageLookupOrg: "machine-level-image-lookup-org",
+				AMI: infrav1.AWSResourceReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				ManagedNetworkInterfaces: []infrav1.ManagedNetworkInterface{
+					{},
+				},
 			},
 			awsCluster: &infrav1.AWSCluster{
 				Spec: infrav1.AWSClusterSpec{
@@ -764,12 +2435,8 @@ func TestCreateInstance(t *testing.T) {
 								ID:       "subnet-1",
 								IsPublic: false,
 							},
-							&infrav1.SubnetSpec{
-								IsPublic: false,
-							},
 						},
 					},
-					ImageLookupOrg: "cluster-level-image-lookup-org",
 				},
 				Status: infrav1.AWSClusterStatus{
 					Network: infrav1.Network{
@@ -791,71 +2458,73 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
-				// verify that the ImageLookupOrg is used when finding AMIs
 				m.
-					DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
-						Filters: []*ec2.Filter{
-							{
-								Name:   aws.String("owner-id"),
-								Values: []*string{aws.String("machine-level-image-lookup-org")},
-							},
-							{
-								Name:   aws.String("name"),
-								Values: []*string{aws.String(amiName("ubuntu-18.04", "v1.16.1"))},
-							},
-							{
-								Name:   aws.String("architecture"),
-								Values: []*string{aws.String("x86_64")},
-							},
-							{
-								Name:   aws.String("state"),
-								Values: []*string{aws.String("available")},
-							},
+					DescribeInstanceTypes(gomock.Any()).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
 							{
-								Name:   aws.String("virtualization-type"),
-								Values: []*string{aws.String("hvm")},
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
 							},
 						},
-					})).
+					}, nil)
+				m.
+					DescribeImages(gomock.Any()).
 					Return(&ec2.DescribeImagesOutput{
 						Images: []*ec2.Image{
 							{
 								Name:         aws.String("ami-1"),
-								CreationDate: aws.String("2006-01-02T15:04:05.000Z"),
+								Architecture: aws.String("x86_64"),
 							},
 						},
 					}, nil)
-				m. // TODO: Restore these parameters, but with the tags as well
+				m.
+					DescribeNetworkInterfaces(gomock.Any()).
+					Return(&ec2.DescribeNetworkInterfacesOutput{}, nil)
+				m.
+					CreateNetworkInterface(gomock.Eq(&ec2.CreateNetworkInterfaceInput{
+						SubnetId: aws.String("subnet-1"),
+						Groups:   aws.StringSlice([]string{"2", "3"}),
+					})).
+					Return(&ec2.CreateNetworkInterfaceOutput{
+						NetworkInterface: &ec2.NetworkInterface{NetworkInterfaceId: aws.String("eni-1")},
+					}, nil)
+				m.
+					CreateTags(gomock.Any()).
+					Return(nil, nil)
+				m.
 					RunInstances(gomock.Any()).
+					Do(func(input *ec2.RunInstancesInput) {
+						if input.SubnetId != nil || input.SecurityGroupIds != nil {
+							t.Fatalf("expected the subnet and security groups to be set on the primary network interface")
+						}
+						if len(input.NetworkInterfaces) != 2 {
+							t.Fatalf("expected 2 network interfaces, got %d", len(input.NetworkInterfaces))
+						}
+						primary, secondary := input.NetworkInterfaces[0], input.NetworkInterfaces[1]
+						if aws.Int64Value(primary.DeviceIndex) != 0 || aws.StringValue(primary.SubnetId) != "subnet-1" {
+							t.Fatalf("unexpected primary network interface: %v", primary)
+						}
+						if aws.Int64Value(secondary.DeviceIndex) != 1 || aws.StringValue(secondary.NetworkInterfaceId) != "eni-1" {
+							t.Fatalf("unexpected secondary network interface: %v", secondary)
+						}
+					}).
 					Return(&ec2.Reservation{
 						Instances: []*ec2.Instance{
 							{
 								State: &ec2.InstanceState{
 									Name: aws.String(ec2.InstanceStateNamePending),
 								},
-								IamInstanceProfile: &ec2.IamInstanceProfile{
-									Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
-								},
-								InstanceId:     aws.String("two"),
-								InstanceType:   aws.String("m5.large"),
-								SubnetId:       aws.String("subnet-1"),
-								ImageId:        aws.String("ami-1"),
-								RootDeviceName: aws.String("device-1"),
-								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
-									{
-										DeviceName: aws.String("device-1"),
-										Ebs: &ec2.EbsInstanceBlockDevice{
-											VolumeId: aws.String("volume-1"),
-										},
-									},
-								},
+								InstanceId:   aws.String("two"),
+								InstanceType: aws.String("m5.large"),
+								SubnetId:     aws.String("subnet-1"),
+								ImageId:      aws.String("ami-1"),
 							},
 						},
 					}, nil)
-
 				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil)
-
 			},
 			check: func(instance *infrav1.Instance, err error) {
 				if err != nil {
@@ -923,6 +2592,11 @@ func TestCreateInstance(t *testing.T) {
 			}
 			machineScope.AWSMachine.Spec = *tc.machineConfig
 			tc.expect(ec2Mock.EXPECT())
+			// Subnets not described by the test case all have plenty of available IP addresses.
+			ec2Mock.EXPECT().
+				DescribeSubnets(gomock.Any()).
+				DoAndReturn(describeSubnetsWithAvailableIPs(100)).
+				AnyTimes()
 
 			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
 				Client: client,
@@ -938,8 +2612,396 @@ func TestCreateInstance(t *testing.T) {
 			}
 
 			s := NewService(clusterScope)
+			if tc.resolveAMI != nil {
+				s.amiResolverFactory = func(*AMILookup) AMIResolver { return tc.resolveAMI }
+			}
 			instance, err := s.CreateInstance(machineScope, []byte("userData"))
 			tc.check(instance, err)
 		})
 	}
 }
+
+// describeSubnetsWithAvailableIPs returns a DescribeSubnets mock implementation that
+// returns the requested subnets, each with the given number of available IP addresses.
+func describeSubnetsWithAvailableIPs(count int64) func(*ec2.DescribeSubnetsInput) (*ec2.DescribeSubnetsOutput, error) {
+	return func(input *ec2.DescribeSubnetsInput) (*ec2.DescribeSubnetsOutput, error) {
+		out := &ec2.DescribeSubnetsOutput{}
+		for _, id := range input.SubnetIds {
+			out.Subnets = append(out.Subnets, &ec2.Subnet{
+				SubnetId:                id,
+				AvailableIpAddressCount: aws.Int64(count),
+			})
+		}
+		return out, nil
+	}
+}
+
+func TestResourceIDFromARN(t *testing.T) {
+	testCases := []struct {
+		name         string
+		arn          string
+		resourceType string
+		expectID     string
+		expectErr    bool
+	}{
+		{
+			name:         "subnet ARN",
+			arn:          "arn:aws:ec2:us-east-1:123456789012:subnet/subnet-1234",
+			resourceType: "subnet",
+			expectID:     "subnet-1234",
+		},
+		{
+			name:         "ARN of another resource type",
+			arn:          "arn:aws:ec2:us-east-1:123456789012:security-group/sg-1234",
+			resourceType: "subnet",
+			expectErr:    true,
+		},
+		{
+			name:         "ARN of another service",
+			arn:          "arn:aws:s3:::subnet/subnet-1234",
+			resourceType: "subnet",
+			expectErr:    true,
+		},
+		{
+			name:         "invalid ARN",
+			arn:          "subnet-1234",
+			resourceType: "subnet",
+			expectErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			id, err := resourceIDFromARN(tc.arn, tc.resourceType)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error, got ID %q", id)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+			if id != tc.expectID {
+				t.Fatalf("expected ID %q, got %q", tc.expectID, id)
+			}
+		})
+	}
+}
+
+func TestModifyRootVolumeSize(t *testing.T) {
+	testCases := []struct {
+		name   string
+		size   int64
+		expect func(m *mock_ec2iface.MockEC2APIMockRecorder)
+	}{
+		{
+			name: "grows the root volume",
+			size: 40,
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.ModifyVolume(gomock.Eq(&ec2.ModifyVolumeInput{
+					VolumeId: aws.String("vol-root"),
+					Size:     aws.Int64(40),
+				})).
+					Return(&ec2.ModifyVolumeOutput{}, nil)
+			},
+		},
+		{
+			name:   "root volume is already large enough",
+			size:   20,
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+			clusterScope, _ := newMachineTestScopes(t, ec2Mock, infrav1.AWSMachineSpec{})
+
+			m := ec2Mock.EXPECT()
+			m.DescribeInstances(gomock.Eq(&ec2.DescribeInstancesInput{
+				InstanceIds: aws.StringSlice([]string{"i-1"}),
+			})).
+				Return(&ec2.DescribeInstancesOutput{
+					Reservations: []*ec2.Reservation{
+						{
+							Instances: []*ec2.Instance{
+								{
+									InstanceId:     aws.String("i-1"),
+									RootDeviceName: aws.String("/dev/sda1"),
+									BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+										{
+											DeviceName: aws.String("/dev/sdb"),
+											Ebs:        &ec2.EbsInstanceBlockDevice{VolumeId: aws.String("vol-data")},
+										},
+										{
+											DeviceName: aws.String("/dev/sda1"),
+											Ebs:        &ec2.EbsInstanceBlockDevice{VolumeId: aws.String("vol-root")},
+										},
+									},
+								},
+							},
+						},
+					},
+				}, nil)
+			m.DescribeVolumes(gomock.Eq(&ec2.DescribeVolumesInput{
+				VolumeIds: aws.StringSlice([]string{"vol-root"}),
+			})).
+				Return(&ec2.DescribeVolumesOutput{
+					Volumes: []*ec2.Volume{
+						{VolumeId: aws.String("vol-root"), Size: aws.Int64(20)},
+					},
+				}, nil)
+			tc.expect(m)
+
+			s := NewService(clusterScope)
+			if err := s.ModifyRootVolumeSize("i-1", tc.size); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDisownInstance(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+	clusterScope, machineScope := newMachineTestScopes(t, ec2Mock, infrav1.AWSMachineSpec{
+		ElasticIP:                &infrav1.ElasticIP{},
+		ManagedNetworkInterfaces: []infrav1.ManagedNetworkInterface{{}},
+	})
+
+	m := ec2Mock.EXPECT()
+	m.DescribeAddresses(gomock.AssignableToTypeOf(&ec2.DescribeAddressesInput{})).
+		Return(&ec2.DescribeAddressesOutput{
+			Addresses: []*ec2.Address{
+				{AllocationId: aws.String("eipalloc-1")},
+			},
+		}, nil)
+	m.DescribeNetworkInterfaces(gomock.AssignableToTypeOf(&ec2.DescribeNetworkInterfacesInput{})).
+		Return(&ec2.DescribeNetworkInterfacesOutput{
+			NetworkInterfaces: []*ec2.NetworkInterface{
+				{
+					NetworkInterfaceId: aws.String("eni-1"),
+					TagSet: []*ec2.Tag{
+						{Key: aws.String("Name"), Value: aws.String("test-machine-eni-0")},
+					},
+				},
+			},
+		}, nil)
+	m.DeleteTags(gomock.Eq(&ec2.DeleteTagsInput{
+		Resources: aws.StringSlice([]string{"i-1", "eipalloc-1", "eni-1"}),
+		Tags: []*ec2.Tag{
+			{Key: aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster")},
+			{Key: aws.String("kubernetes.io/cluster/test-cluster")},
+		},
+	})).
+		Return(&ec2.DeleteTagsOutput{}, nil)
+
+	s := NewService(clusterScope)
+	if err := s.DisownInstance(machineScope, "i-1"); err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+}
+
+func TestDetachCoreSecurityGroupsFromInstance(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+	clusterScope, machineScope := newMachineTestScopes(t, ec2Mock, infrav1.AWSMachineSpec{})
+	clusterScope.AWSCluster.Spec.NetworkSpec.VPC.ID = "vpc-1"
+	clusterScope.AWSCluster.Status.Network.SecurityGroups = map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+		infrav1.SecurityGroupNode: {ID: "sg-node"},
+		infrav1.SecurityGroupLB:   {ID: "sg-lb"},
+	}
+
+	m := ec2Mock.EXPECT()
+	m.DescribeNetworkInterfaces(gomock.AssignableToTypeOf(&ec2.DescribeNetworkInterfacesInput{})).
+		Return(&ec2.DescribeNetworkInterfacesOutput{
+			NetworkInterfaces: []*ec2.NetworkInterface{
+				{
+					NetworkInterfaceId: aws.String("eni-1"),
+					Groups: []*ec2.GroupIdentifier{
+						{GroupId: aws.String("sg-node")},
+						{GroupId: aws.String("sg-lb")},
+					},
+				},
+				{
+					NetworkInterfaceId: aws.String("eni-2"),
+					Groups: []*ec2.GroupIdentifier{
+						{GroupId: aws.String("sg-node")},
+						{GroupId: aws.String("sg-custom")},
+					},
+				},
+				{
+					NetworkInterfaceId: aws.String("eni-3"),
+					Groups: []*ec2.GroupIdentifier{
+						{GroupId: aws.String("sg-custom")},
+					},
+				},
+			},
+		}, nil)
+	m.DescribeSecurityGroups(gomock.Eq(&ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC("vpc-1"),
+			{
+				Name:   aws.String("group-name"),
+				Values: aws.StringSlice([]string{"default"}),
+			},
+		},
+	})).
+		Return(&ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []*ec2.SecurityGroup{{GroupId: aws.String("sg-default")}},
+		}, nil)
+	m.ModifyNetworkInterfaceAttribute(gomock.Eq(&ec2.ModifyNetworkInterfaceAttributeInput{
+		NetworkInterfaceId: aws.String("eni-1"),
+		Groups:             aws.StringSlice([]string{"sg-default"}),
+	})).
+		Return(&ec2.ModifyNetworkInterfaceAttributeOutput{}, nil)
+	m.ModifyNetworkInterfaceAttribute(gomock.Eq(&ec2.ModifyNetworkInterfaceAttributeInput{
+		NetworkInterfaceId: aws.String("eni-2"),
+		Groups:             aws.StringSlice([]string{"sg-custom"}),
+	})).
+		Return(&ec2.ModifyNetworkInterfaceAttributeOutput{}, nil)
+
+	s := NewService(clusterScope)
+	if err := s.DetachCoreSecurityGroupsFromInstance(machineScope, "i-1"); err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+}
+
+func TestDisableTerminationProtection(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+	clusterScope, _ := newMachineTestScopes(t, ec2Mock, infrav1.AWSMachineSpec{})
+
+	ec2Mock.EXPECT().ModifyInstanceAttribute(gomock.Eq(&ec2.ModifyInstanceAttributeInput{
+		InstanceId:            aws.String("i-1"),
+		DisableApiTermination: &ec2.AttributeBooleanValue{Value: aws.Bool(false)},
+	})).
+		Return(&ec2.ModifyInstanceAttributeOutput{}, nil)
+
+	s := NewService(clusterScope)
+	if err := s.DisableTerminationProtection("i-1"); err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+}
+
+func TestGetInstanceScheduledEvents(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+	clusterScope, _ := newMachineTestScopes(t, ec2Mock, infrav1.AWSMachineSpec{})
+
+	notBefore := time.Date(2020, time.June, 1, 10, 0, 0, 0, time.UTC)
+	ec2Mock.EXPECT().
+		DescribeInstanceStatus(gomock.Eq(&ec2.DescribeInstanceStatusInput{
+			InstanceIds: aws.StringSlice([]string{"i-1"}),
+		})).
+		Return(&ec2.DescribeInstanceStatusOutput{
+			InstanceStatuses: []*ec2.InstanceStatus{
+				{
+					InstanceId: aws.String("i-1"),
+					Events: []*ec2.InstanceStatusEvent{
+						{
+							Code:        aws.String("system-reboot"),
+							Description: aws.String("[Completed] Scheduled reboot"),
+						},
+						{
+							Code:        aws.String("instance-retirement"),
+							Description: aws.String("The instance is running on degraded hardware"),
+							NotBefore:   aws.Time(notBefore),
+						},
+					},
+				},
+			},
+		}, nil)
+
+	s := NewService(clusterScope)
+	events, err := s.GetInstanceScheduledEvents("i-1")
+	if err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+
+	expected := []infrav1.InstanceScheduledEvent{
+		{
+			Code:        "instance-retirement",
+			Description: "The instance is running on degraded hardware",
+			NotBefore:   &metav1.Time{Time: notBefore},
+		},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected scheduled events %v, got %v", expected, events)
+	}
+}
+
+func TestSSHKeyName(t *testing.T) {
+	testCases := []struct {
+		name       string
+		accessMode infrav1.ClusterAccessMode
+		clusterKey *string
+		machineKey *string
+		expected   *string
+	}{
+		{
+			name:     "defaults to the default SSH key",
+			expected: aws.String(defaultSSHKeyName),
+		},
+		{
+			name:       "no default SSH key with Session Manager",
+			accessMode: infrav1.ClusterAccessModeSessionManager,
+		},
+		{
+			name:       "falls back to the cluster SSH key",
+			clusterKey: aws.String("cluster-key"),
+			expected:   aws.String("cluster-key"),
+		},
+		{
+			name:       "cluster without SSH key",
+			clusterKey: aws.String(""),
+		},
+		{
+			name:       "machine SSH key overrides the cluster one",
+			clusterKey: aws.String(""),
+			machineKey: aws.String("machine-key"),
+			expected:   aws.String("machine-key"),
+		},
+		{
+			name:       "machine without SSH key",
+			clusterKey: aws.String("cluster-key"),
+			machineKey: aws.String(""),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						AccessMode: tc.accessMode,
+						SSHKeyName: tc.clusterKey,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			s := NewService(scope)
+			if key := s.sshKeyName(tc.machineKey); !reflect.DeepEqual(key, tc.expected) {
+				t.Fatalf("expected SSH key name %v, got %v", aws.StringValue(tc.expected), aws.StringValue(key))
+			}
+		})
+	}
+}
@@ -0,0 +1,497 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"encoding/base64"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/userdata"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+// GetLaunchTemplate returns the latest version of the launch template with the given name,
+// along with its base64 encoded user data, or nil if the launch template doesn't exist.
+func (s *Service) GetLaunchTemplate(name string) (*infrav1.AWSLaunchTemplate, string, error) {
+	lt, err := s.getOwnedLaunchTemplate(&ec2.DescribeLaunchTemplatesInput{
+		LaunchTemplateNames: []*string{aws.String(name)},
+	})
+	if err != nil || lt == nil {
+		return nil, "", err
+	}
+
+	input := &ec2.DescribeLaunchTemplateVersionsInput{
+		LaunchTemplateId: lt.LaunchTemplateId,
+		Versions:         []*string{aws.String("$Latest")},
+	}
+
+	out, err := s.scope.EC2.DescribeLaunchTemplateVersions(input)
+	switch {
+	case awserrors.IsNotFound(err):
+		return nil, "", nil
+	case err != nil:
+		return nil, "", errors.Wrapf(err, "failed to describe launch template %q", name)
+	}
+
+	if out == nil || len(out.LaunchTemplateVersions) == 0 {
+		return nil, "", nil
+	}
+
+	return s.SDKToLaunchTemplate(out.LaunchTemplateVersions[0])
+}
+
+// GetLaunchTemplateID returns the ID of the launch template with the given name,
+// or an empty string if the launch template doesn't exist.
+func (s *Service) GetLaunchTemplateID(name string) (string, error) {
+	lt, err := s.getOwnedLaunchTemplate(&ec2.DescribeLaunchTemplatesInput{
+		LaunchTemplateNames: []*string{aws.String(name)},
+	})
+	if err != nil || lt == nil {
+		return "", err
+	}
+
+	return aws.StringValue(lt.LaunchTemplateId), nil
+}
+
+// CreateLaunchTemplate creates a launch template for the given machine pool and returns its ID.
+func (s *Service) CreateLaunchTemplate(scope *scope.MachinePoolScope, imageID *string, userData []byte) (string, error) {
+	s.scope.V(2).Info("Creating a launch template for a machine pool")
+
+	data, err := s.createLaunchTemplateData(scope, imageID, userData)
+	if err != nil {
+		return "", err
+	}
+
+	input := &ec2.CreateLaunchTemplateInput{
+		LaunchTemplateData: data,
+		LaunchTemplateName: aws.String(scope.ResourceName()),
+		TagSpecifications: []*ec2.TagSpecification{
+			tagSpecification(ec2.ResourceTypeLaunchTemplate, s.launchTemplateTags(scope)),
+		},
+	}
+
+	out, err := s.scope.EC2.CreateLaunchTemplate(input)
+	if err != nil {
+		record.Warnf(scope.AWSMachinePool, "FailedCreateLaunchTemplate", "Failed to create launch template %q: %v", scope.ResourceName(), err)
+		return "", errors.Wrapf(err, "failed to create launch template %q", scope.ResourceName())
+	}
+
+	record.Eventf(scope.AWSMachinePool, "SuccessfulCreateLaunchTemplate", "Created launch template %q", aws.StringValue(out.LaunchTemplate.LaunchTemplateId))
+	return aws.StringValue(out.LaunchTemplate.LaunchTemplateId), nil
+}
+
+// CreateLaunchTemplateVersion creates a new version of the launch template for the given machine pool.
+func (s *Service) CreateLaunchTemplateVersion(scope *scope.MachinePoolScope, imageID *string, userData []byte) error {
+	s.scope.V(2).Info("Creating a new launch template version", "launch-template", scope.ResourceName())
+
+	data, err := s.createLaunchTemplateData(scope, imageID, userData)
+	if err != nil {
+		return err
+	}
+
+	input := &ec2.CreateLaunchTemplateVersionInput{
+		LaunchTemplateData: data,
+		LaunchTemplateName: aws.String(scope.ResourceName()),
+	}
+
+	out, err := s.scope.EC2.CreateLaunchTemplateVersion(input)
+	if err != nil {
+		record.Warnf(scope.AWSMachinePool, "FailedCreateLaunchTemplateVersion", "Failed to create a new version of launch template %q: %v", scope.ResourceName(), err)
+		return errors.Wrapf(err, "failed to create launch template version for %q", scope.ResourceName())
+	}
+
+	record.Eventf(scope.AWSMachinePool, "SuccessfulCreateLaunchTemplateVersion", "Created version %d of launch template %q",
+		aws.Int64Value(out.LaunchTemplateVersion.VersionNumber), scope.ResourceName())
+	return nil
+}
+
+// DeleteLaunchTemplate deletes the launch template with the given ID. Launch templates that are
+// already gone are ignored, the ones that aren't owned by the cluster are never deleted.
+func (s *Service) DeleteLaunchTemplate(id string) error {
+	lt, err := s.getOwnedLaunchTemplate(&ec2.DescribeLaunchTemplatesInput{
+		LaunchTemplateIds: []*string{aws.String(id)},
+	})
+	if err != nil || lt == nil {
+		return err
+	}
+
+	s.scope.V(2).Info("Deleting launch template", "id", id)
+
+	input := &ec2.DeleteLaunchTemplateInput{
+		LaunchTemplateId: aws.String(id),
+	}
+
+	if _, err := s.scope.EC2.DeleteLaunchTemplate(input); err != nil {
+		if awserrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete launch template %q", id)
+	}
+
+	s.scope.V(2).Info("Deleted launch template", "id", id)
+	return nil
+}
+
+// LaunchTemplateNeedsUpdate checks if a new launch template version is needed to reconcile the
+// existing launch template with the machine pool's spec, the given image and the given user data.
+func (s *Service) LaunchTemplateNeedsUpdate(scope *scope.MachinePoolScope, existing *infrav1.AWSLaunchTemplate, existingUserData string, imageID *string, userData []byte) (bool, error) {
+	incoming := scope.AWSMachinePool.Spec.AWSLaunchTemplate
+
+	if incoming.InstanceType != existing.InstanceType {
+		return true, nil
+	}
+
+	if incoming.IAMInstanceProfile != existing.IAMInstanceProfile {
+		return true, nil
+	}
+
+	if aws.StringValue(s.launchTemplateSSHKeyName(scope)) != aws.StringValue(existing.SSHKeyName) {
+		return true, nil
+	}
+
+	if aws.StringValue(imageID) != aws.StringValue(existing.AMI.ID) {
+		return true, nil
+	}
+
+	if rootVolumeNeedsUpdate(incoming.RootVolume, existing.RootVolume) {
+		return true, nil
+	}
+
+	encoded, err := launchTemplateUserData(userData)
+	if err != nil {
+		return false, err
+	}
+	if encoded != existingUserData {
+		return true, nil
+	}
+
+	incomingIDs, err := s.launchTemplateSecurityGroups(scope)
+	if err != nil {
+		return false, err
+	}
+
+	existingIDs := make([]string, 0, len(existing.AdditionalSecurityGroups))
+	for _, sg := range existing.AdditionalSecurityGroups {
+		existingIDs = append(existingIDs, aws.StringValue(sg.ID))
+	}
+
+	sort.Strings(incomingIDs)
+	sort.Strings(existingIDs)
+	if len(incomingIDs) != len(existingIDs) {
+		return true, nil
+	}
+	for i := range incomingIDs {
+		if incomingIDs[i] != existingIDs[i] {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// DiscoverLaunchTemplateAMI returns the AMI to use for the machine pool's launch template,
+// either from its spec or looked up for the MachinePool's Kubernetes version.
+func (s *Service) DiscoverLaunchTemplateAMI(scope *scope.MachinePoolScope) (*string, error) {
+	lt := scope.AWSMachinePool.Spec.AWSLaunchTemplate
+
+	if lt.AMI.ID != nil {
+		return lt.AMI.ID, nil
+	}
+
+	if scope.MachinePool.Spec.Template.Spec.Version == nil {
+		return nil, errors.New("Either AWSMachinePool's spec.awsLaunchTemplate.ami.id or MachinePool's spec.template.spec.version must be defined")
+	}
+
+	imageLookupOrg := lt.ImageLookupOrg
+	if imageLookupOrg == "" {
+		imageLookupOrg = scope.AWSCluster.Spec.ImageLookupOrg
+	}
+
+	imageLookupBaseOS := lt.ImageLookupBaseOS
+	if imageLookupBaseOS == "" {
+		imageLookupBaseOS = scope.AWSCluster.Spec.ImageLookupBaseOS
+	}
+
+	// Without an instance type nothing restricts the architecture of the AMI.
+	architecture := defaultArchitecture
+	if lt.InstanceType != "" {
+		architectures, err := s.instanceTypeArchitectures(lt.InstanceType)
+		if err != nil {
+			return nil, err
+		}
+		architecture = preferredArchitecture(architectures)
+	}
+
+	imageID, err := s.lookupAMI(&AMILookup{
+		OwnerID:           imageLookupOrg,
+		BaseOS:            imageLookupBaseOS,
+		Architecture:      architecture,
+		KubernetesVersion: *scope.MachinePool.Spec.Template.Spec.Version,
+		NameFormat:        scope.AWSCluster.Spec.ImageLookupFormat,
+		SSMParameter:      scope.AWSCluster.Spec.ImageLookupSSMParameter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return aws.String(imageID), nil
+}
+
+// SDKToLaunchTemplate converts an AWS EC2 SDK launch template version to the CAPA launch template type,
+// and returns the base64 encoded user data of the version alongside it.
+func (s *Service) SDKToLaunchTemplate(d *ec2.LaunchTemplateVersion) (*infrav1.AWSLaunchTemplate, string, error) {
+	v := d.LaunchTemplateData
+	if v == nil {
+		return nil, "", errors.Errorf("launch template %q version %d has no data",
+			aws.StringValue(d.LaunchTemplateName), aws.Int64Value(d.VersionNumber))
+	}
+
+	i := &infrav1.AWSLaunchTemplate{
+		AMI: infrav1.AWSResourceReference{
+			ID: v.ImageId,
+		},
+		InstanceType: aws.StringValue(v.InstanceType),
+		SSHKeyName:   v.KeyName,
+	}
+
+	if v.IamInstanceProfile != nil {
+		i.IAMInstanceProfile = aws.StringValue(v.IamInstanceProfile.Name)
+	}
+
+	for _, id := range v.SecurityGroupIds {
+		i.AdditionalSecurityGroups = append(i.AdditionalSecurityGroups, infrav1.AWSResourceReference{ID: id})
+	}
+
+	// The root volume is the only block device mapping set in launch templates, see createLaunchTemplateData.
+	for _, mapping := range v.BlockDeviceMappings {
+		if mapping.Ebs == nil {
+			continue
+		}
+		i.RootVolume = &infrav1.RootVolume{
+			Size:          aws.Int64Value(mapping.Ebs.VolumeSize),
+			Type:          aws.StringValue(mapping.Ebs.VolumeType),
+			IOPS:          aws.Int64Value(mapping.Ebs.Iops),
+			Encrypted:     aws.BoolValue(mapping.Ebs.Encrypted),
+			EncryptionKey: aws.StringValue(mapping.Ebs.KmsKeyId),
+		}
+		break
+	}
+
+	return i, aws.StringValue(v.UserData), nil
+}
+
+func (s *Service) createLaunchTemplateData(scope *scope.MachinePoolScope, imageID *string, userData []byte) (*ec2.RequestLaunchTemplateData, error) {
+	lt := scope.AWSMachinePool.Spec.AWSLaunchTemplate
+
+	encoded, err := launchTemplateUserData(userData)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &ec2.RequestLaunchTemplateData{
+		ImageId:  imageID,
+		KeyName:  s.launchTemplateSSHKeyName(scope),
+		UserData: aws.String(encoded),
+	}
+
+	if lt.InstanceType != "" {
+		data.InstanceType = aws.String(lt.InstanceType)
+	}
+
+	if lt.IAMInstanceProfile != "" {
+		data.IamInstanceProfile = &ec2.LaunchTemplateIamInstanceProfileSpecificationRequest{
+			Name: aws.String(lt.IAMInstanceProfile),
+		}
+	}
+
+	ids, err := s.launchTemplateSecurityGroups(scope)
+	if err != nil {
+		return nil, err
+	}
+	data.SecurityGroupIds = aws.StringSlice(ids)
+
+	if lt.RootVolume != nil {
+		mapping, err := s.launchTemplateRootVolume(aws.StringValue(imageID), lt.RootVolume)
+		if err != nil {
+			return nil, err
+		}
+		data.BlockDeviceMappings = []*ec2.LaunchTemplateBlockDeviceMappingRequest{mapping}
+	}
+
+	// Instances launched from the template are tagged the same way as the ones created for AWSMachines.
+	instanceTags := s.launchTemplateTags(scope)
+	instanceTags[infrav1.ClusterAWSCloudProviderTagKey(s.scope.Name())] = string(infrav1.ResourceLifecycleOwned)
+
+	for _, resourceType := range []string{ec2.ResourceTypeInstance, ec2.ResourceTypeVolume} {
+		spec := &ec2.LaunchTemplateTagSpecificationRequest{ResourceType: aws.String(resourceType)}
+		for key, value := range instanceTags {
+			spec.Tags = append(spec.Tags, &ec2.Tag{
+				Key:   aws.String(key),
+				Value: aws.String(value),
+			})
+		}
+		data.TagSpecifications = append(data.TagSpecifications, spec)
+	}
+
+	return data, nil
+}
+
+func (s *Service) launchTemplateRootVolume(imageID string, volume *infrav1.RootVolume) (*ec2.LaunchTemplateBlockDeviceMappingRequest, error) {
+	rootDeviceName, err := s.getImageRootDevice(imageID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get root volume from image %q", imageID)
+	}
+
+	snapshotSize, err := s.getImageSnapshotSize(imageID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get root volume from image %q", imageID)
+	}
+
+	if volume.Size < *snapshotSize {
+		return nil, errors.Errorf("root volume size (%d) must be greater than or equal to snapshot size (%d)", volume.Size, *snapshotSize)
+	}
+
+	ebsRootDevice := &ec2.LaunchTemplateEbsBlockDeviceRequest{
+		DeleteOnTermination: aws.Bool(true),
+		VolumeSize:          aws.Int64(volume.Size),
+		Encrypted:           aws.Bool(volume.Encrypted),
+	}
+
+	if volume.IOPS != 0 {
+		ebsRootDevice.Iops = aws.Int64(volume.IOPS)
+	}
+
+	if volume.EncryptionKey != "" {
+		ebsRootDevice.Encrypted = aws.Bool(true)
+		ebsRootDevice.KmsKeyId = aws.String(volume.EncryptionKey)
+	}
+
+	if volume.Type != "" {
+		ebsRootDevice.VolumeType = aws.String(volume.Type)
+	}
+
+	return &ec2.LaunchTemplateBlockDeviceMappingRequest{
+		DeviceName: rootDeviceName,
+		Ebs:        ebsRootDevice,
+	}, nil
+}
+
+// rootVolumeNeedsUpdate checks if the root volume of a launch template differs from the desired one.
+// A volume is encrypted when an encryption key is set, see launchTemplateRootVolume.
+func rootVolumeNeedsUpdate(incoming, existing *infrav1.RootVolume) bool {
+	if incoming == nil || existing == nil {
+		return incoming != existing
+	}
+
+	return incoming.Size != existing.Size ||
+		incoming.Type != existing.Type ||
+		incoming.IOPS != existing.IOPS ||
+		(incoming.Encrypted || incoming.EncryptionKey != "") != existing.Encrypted ||
+		incoming.EncryptionKey != existing.EncryptionKey
+}
+
+// getOwnedLaunchTemplate returns the launch template matching the input, or nil if it doesn't exist.
+// Launch templates that aren't owned by the cluster are never adopted, nor deleted, so an error is returned for them.
+func (s *Service) getOwnedLaunchTemplate(input *ec2.DescribeLaunchTemplatesInput) (*ec2.LaunchTemplate, error) {
+	out, err := s.scope.EC2.DescribeLaunchTemplates(input)
+	switch {
+	case awserrors.IsNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, errors.Wrap(err, "failed to describe launch templates")
+	}
+
+	if out == nil || len(out.LaunchTemplates) == 0 {
+		return nil, nil
+	}
+
+	lt := out.LaunchTemplates[0]
+	if !converters.TagsToMap(lt.Tags).HasOwned(s.scope.Name()) {
+		return nil, errors.Errorf("launch template %q is not owned by cluster %q, refusing to adopt or delete it",
+			aws.StringValue(lt.LaunchTemplateName), s.scope.Name())
+	}
+
+	return lt, nil
+}
+
+// launchTemplateSecurityGroups returns the node security groups and the additional
+// security groups to apply to the machine pool's instances.
+func (s *Service) launchTemplateSecurityGroups(scope *scope.MachinePoolScope) ([]string, error) {
+	sgRoles := []infrav1.SecurityGroupRole{
+		infrav1.SecurityGroupNode,
+		infrav1.SecurityGroupLB,
+	}
+
+	ids := make([]string, 0, len(sgRoles))
+	for _, sg := range sgRoles {
+		if _, ok := s.scope.SecurityGroups()[sg]; !ok {
+			return nil, awserrors.NewFailedDependency(
+				errors.Errorf("%s security group not available", sg),
+			)
+		}
+		ids = append(ids, s.scope.SecurityGroups()[sg].ID)
+	}
+
+	additional, err := s.GetAdditionalSecurityGroupsIDs(scope.AWSMachinePool.Spec.AWSLaunchTemplate.AdditionalSecurityGroups)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ids, additional...), nil
+}
+
+// launchTemplateSSHKeyName returns the SSH key name to use for the machine pool's instances, see sshKeyName.
+func (s *Service) launchTemplateSSHKeyName(scope *scope.MachinePoolScope) *string {
+	return s.sshKeyName(scope.AWSMachinePool.Spec.AWSLaunchTemplate.SSHKeyName)
+}
+
+func (s *Service) launchTemplateTags(scope *scope.MachinePoolScope) infrav1.Tags {
+	return infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(scope.Name()),
+		Role:        aws.String(scope.Role()),
+		Additional:  scope.AdditionalTags(),
+	})
+}
+
+// launchTemplateUserData compresses and encodes the user data the way it is stored in launch templates.
+func launchTemplateUserData(userData []byte) (string, error) {
+	compressed, err := userdata.GzipBytes(userData)
+	if err != nil {
+		return "", errors.New("failed to gzip userdata")
+	}
+	return base64.StdEncoding.EncodeToString(compressed), nil
+}
+
+func tagSpecification(resourceType string, tags infrav1.Tags) *ec2.TagSpecification {
+	spec := &ec2.TagSpecification{ResourceType: aws.String(resourceType)}
+	for key, value := range tags {
+		spec.Tags = append(spec.Tags, &ec2.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(value),
+		})
+	}
+	return spec
+}
@@ -0,0 +1,572 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetLaunchTemplate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name   string
+		expect func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		check  func(lt *infrav1.AWSLaunchTemplate, userData string, err error)
+	}{
+		{
+			name: "does not exist",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeLaunchTemplates(gomock.Eq(&ec2.DescribeLaunchTemplatesInput{
+					LaunchTemplateNames: []*string{aws.String("pool")},
+				})).
+					Return(nil, awserr.New(awserrors.LaunchTemplateNameNotFound, "does not exist", nil))
+			},
+			check: func(lt *infrav1.AWSLaunchTemplate, userData string, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+
+				if lt != nil {
+					t.Fatalf("Did not expect anything but got something: %+v", lt)
+				}
+			},
+		},
+		{
+			name: "exists but is not owned by the cluster",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeLaunchTemplates(gomock.Eq(&ec2.DescribeLaunchTemplatesInput{
+					LaunchTemplateNames: []*string{aws.String("pool")},
+				})).
+					Return(&ec2.DescribeLaunchTemplatesOutput{
+						LaunchTemplates: []*ec2.LaunchTemplate{
+							{
+								LaunchTemplateId:   aws.String("lt-1"),
+								LaunchTemplateName: aws.String("pool"),
+								Tags: []*ec2.Tag{
+									{
+										Key:   aws.String(infrav1.ClusterTagKey("other-cluster")),
+										Value: aws.String(string(infrav1.ResourceLifecycleOwned)),
+									},
+								},
+							},
+						},
+					}, nil)
+			},
+			check: func(lt *infrav1.AWSLaunchTemplate, userData string, err error) {
+				if err == nil {
+					t.Fatal("expected an error for a launch template owned by another cluster")
+				}
+			},
+		},
+		{
+			name: "exists",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeLaunchTemplates(gomock.Eq(&ec2.DescribeLaunchTemplatesInput{
+					LaunchTemplateNames: []*string{aws.String("pool")},
+				})).
+					Return(&ec2.DescribeLaunchTemplatesOutput{
+						LaunchTemplates: []*ec2.LaunchTemplate{ownedLaunchTemplate("lt-1", "pool")},
+					}, nil)
+				m.DescribeLaunchTemplateVersions(gomock.Eq(&ec2.DescribeLaunchTemplateVersionsInput{
+					LaunchTemplateId: aws.String("lt-1"),
+					Versions:         []*string{aws.String("$Latest")},
+				})).
+					Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+						LaunchTemplateVersions: []*ec2.LaunchTemplateVersion{
+							{
+								LaunchTemplateId:   aws.String("lt-1"),
+								LaunchTemplateName: aws.String("pool"),
+								VersionNumber:      aws.Int64(2),
+								LaunchTemplateData: &ec2.ResponseLaunchTemplateData{
+									ImageId:      aws.String("ami-1"),
+									InstanceType: aws.String("m5.large"),
+									KeyName:      aws.String("default"),
+									IamInstanceProfile: &ec2.LaunchTemplateIamInstanceProfileSpecification{
+										Name: aws.String("nodes"),
+									},
+									SecurityGroupIds: aws.StringSlice([]string{"sg-1", "sg-2"}),
+									UserData:         aws.String("dXNlcmRhdGE="),
+									BlockDeviceMappings: []*ec2.LaunchTemplateBlockDeviceMapping{
+										{
+											DeviceName: aws.String("/dev/sda1"),
+											Ebs: &ec2.LaunchTemplateEbsBlockDevice{
+												VolumeSize: aws.Int64(50),
+												VolumeType: aws.String("gp2"),
+												Encrypted:  aws.Bool(true),
+											},
+										},
+									},
+								},
+							},
+						},
+					}, nil)
+			},
+			check: func(lt *infrav1.AWSLaunchTemplate, userData string, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+
+				if lt == nil {
+					t.Fatalf("expected launch template but got nothing")
+				}
+
+				if aws.StringValue(lt.AMI.ID) != "ami-1" {
+					t.Fatalf("expected ami-1 but got: %v", aws.StringValue(lt.AMI.ID))
+				}
+
+				if lt.IAMInstanceProfile != "nodes" {
+					t.Fatalf("expected nodes instance profile but got: %v", lt.IAMInstanceProfile)
+				}
+
+				if len(lt.AdditionalSecurityGroups) != 2 {
+					t.Fatalf("expected 2 security groups but got: %v", len(lt.AdditionalSecurityGroups))
+				}
+
+				if lt.RootVolume == nil || lt.RootVolume.Size != 50 || lt.RootVolume.Type != "gp2" || !lt.RootVolume.Encrypted {
+					t.Fatalf("expected an encrypted 50Gi gp2 root volume but got: %+v", lt.RootVolume)
+				}
+
+				if userData != "dXNlcmRhdGE=" {
+					t.Fatalf("expected user data to be returned but got: %v", userData)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				Cluster:    &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"}},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			lt, userData, err := s.GetLaunchTemplate("pool")
+			tc.check(lt, userData, err)
+		})
+	}
+}
+
+func TestDeleteLaunchTemplate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name      string
+		expect    func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectErr bool
+	}{
+		{
+			name: "already deleted",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeLaunchTemplates(gomock.Eq(&ec2.DescribeLaunchTemplatesInput{
+					LaunchTemplateIds: []*string{aws.String("lt-1")},
+				})).
+					Return(nil, awserr.New(awserrors.LaunchTemplateIDNotFound, "does not exist", nil))
+			},
+		},
+		{
+			name: "not owned by the cluster",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeLaunchTemplates(gomock.Eq(&ec2.DescribeLaunchTemplatesInput{
+					LaunchTemplateIds: []*string{aws.String("lt-1")},
+				})).
+					Return(&ec2.DescribeLaunchTemplatesOutput{
+						LaunchTemplates: []*ec2.LaunchTemplate{
+							{
+								LaunchTemplateId:   aws.String("lt-1"),
+								LaunchTemplateName: aws.String("pool"),
+							},
+						},
+					}, nil)
+			},
+			expectErr: true,
+		},
+		{
+			name: "deleted while being looked up",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeLaunchTemplates(gomock.Eq(&ec2.DescribeLaunchTemplatesInput{
+					LaunchTemplateIds: []*string{aws.String("lt-1")},
+				})).
+					Return(&ec2.DescribeLaunchTemplatesOutput{
+						LaunchTemplates: []*ec2.LaunchTemplate{ownedLaunchTemplate("lt-1", "pool")},
+					}, nil)
+				m.DeleteLaunchTemplate(gomock.Eq(&ec2.DeleteLaunchTemplateInput{
+					LaunchTemplateId: aws.String("lt-1"),
+				})).
+					Return(nil, awserr.New(awserrors.LaunchTemplateIDNotFound, "does not exist", nil))
+			},
+		},
+		{
+			name: "owned by the cluster",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeLaunchTemplates(gomock.Eq(&ec2.DescribeLaunchTemplatesInput{
+					LaunchTemplateIds: []*string{aws.String("lt-1")},
+				})).
+					Return(&ec2.DescribeLaunchTemplatesOutput{
+						LaunchTemplates: []*ec2.LaunchTemplate{ownedLaunchTemplate("lt-1", "pool")},
+					}, nil)
+				m.DeleteLaunchTemplate(gomock.Eq(&ec2.DeleteLaunchTemplateInput{
+					LaunchTemplateId: aws.String("lt-1"),
+				})).
+					Return(&ec2.DeleteLaunchTemplateOutput{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				Cluster:    &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"}},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			err = s.DeleteLaunchTemplate("lt-1")
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+		})
+	}
+}
+
+func ownedLaunchTemplate(id, name string) *ec2.LaunchTemplate {
+	return &ec2.LaunchTemplate{
+		LaunchTemplateId:   aws.String(id),
+		LaunchTemplateName: aws.String(name),
+		Tags: []*ec2.Tag{
+			{
+				Key:   aws.String(infrav1.ClusterTagKey("test-cluster")),
+				Value: aws.String(string(infrav1.ResourceLifecycleOwned)),
+			},
+		},
+	}
+}
+
+func TestDiscoverLaunchTemplateAMI(t *testing.T) {
+	testCases := []struct {
+		name         string
+		instanceType string
+		expect       func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectArch   string
+	}{
+		{
+			name:         "uses the architecture of the instance type",
+			instanceType: "m6g.large",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeInstanceTypes(gomock.Eq(&ec2.DescribeInstanceTypesInput{
+					InstanceTypes: aws.StringSlice([]string{"m6g.large"}),
+				})).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"arm64"}),
+								},
+							},
+						},
+					}, nil)
+			},
+			expectArch: "arm64",
+		},
+		{
+			name:       "falls back to the default architecture without an instance type",
+			expect:     func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+			expectArch: defaultArchitecture,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(gomock.NewController(t))
+
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			machinePool := &expv1.MachinePool{}
+			machinePool.Spec.Template.Spec.Version = aws.String("v1.17.3")
+			machinePoolScope, err := scope.NewMachinePoolScope(scope.MachinePoolScopeParams{
+				Client:      fake.NewFakeClientWithScheme(runtime.NewScheme()),
+				Cluster:     &clusterv1.Cluster{},
+				MachinePool: machinePool,
+				AWSCluster:  clusterScope.AWSCluster,
+				AWSMachinePool: &infrav1.AWSMachinePool{
+					Spec: infrav1.AWSMachinePoolSpec{
+						AWSLaunchTemplate: infrav1.AWSLaunchTemplate{
+							InstanceType: tc.instanceType,
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(clusterScope)
+			s.amiResolverFactory = func(*AMILookup) AMIResolver {
+				return fakeAMIResolver(func(lookup *AMILookup) (string, error) {
+					if lookup.Architecture != tc.expectArch {
+						t.Errorf("expected lookup architecture %q, got %q", tc.expectArch, lookup.Architecture)
+					}
+					return "ami-1", nil
+				})
+			}
+
+			id, err := s.DiscoverLaunchTemplateAMI(machinePoolScope)
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+			if aws.StringValue(id) != "ami-1" {
+				t.Fatalf("expected AMI %q, got %q", "ami-1", aws.StringValue(id))
+			}
+		})
+	}
+}
+
+func TestLaunchTemplateNeedsUpdate(t *testing.T) {
+	userData := []byte("userdata")
+	encoded, err := launchTemplateUserData(userData)
+	if err != nil {
+		t.Fatalf("failed to encode user data: %v", err)
+	}
+
+	existing := func() *infrav1.AWSLaunchTemplate {
+		return &infrav1.AWSLaunchTemplate{
+			AMI:                infrav1.AWSResourceReference{ID: aws.String("ami-1")},
+			InstanceType:       "m5.large",
+			IAMInstanceProfile: "nodes",
+			SSHKeyName:         aws.String("default"),
+			AdditionalSecurityGroups: []infrav1.AWSResourceReference{
+				{ID: aws.String("sg-lb")},
+				{ID: aws.String("sg-node")},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		spec             infrav1.AWSLaunchTemplate
+		existing         *infrav1.AWSLaunchTemplate
+		existingUserData string
+		imageID          string
+		expected         bool
+	}{
+		{
+			name: "nothing changed",
+			spec: infrav1.AWSLaunchTemplate{
+				InstanceType:       "m5.large",
+				IAMInstanceProfile: "nodes",
+			},
+			existing:         existing(),
+			existingUserData: encoded,
+			imageID:          "ami-1",
+			expected:         false,
+		},
+		{
+			name: "instance type changed",
+			spec: infrav1.AWSLaunchTemplate{
+				InstanceType:       "m5.xlarge",
+				IAMInstanceProfile: "nodes",
+			},
+			existing:         existing(),
+			existingUserData: encoded,
+			imageID:          "ami-1",
+			expected:         true,
+		},
+		{
+			name: "image changed",
+			spec: infrav1.AWSLaunchTemplate{
+				InstanceType:       "m5.large",
+				IAMInstanceProfile: "nodes",
+			},
+			existing:         existing(),
+			existingUserData: encoded,
+			imageID:          "ami-2",
+			expected:         true,
+		},
+		{
+			name: "user data changed",
+			spec: infrav1.AWSLaunchTemplate{
+				InstanceType:       "m5.large",
+				IAMInstanceProfile: "nodes",
+			},
+			existing:         existing(),
+			existingUserData: "b2xkLXVzZXJkYXRh",
+			imageID:          "ami-1",
+			expected:         true,
+		},
+		{
+			name: "additional security group added",
+			spec: infrav1.AWSLaunchTemplate{
+				InstanceType:       "m5.large",
+				IAMInstanceProfile: "nodes",
+				AdditionalSecurityGroups: []infrav1.AWSResourceReference{
+					{ID: aws.String("sg-extra")},
+				},
+			},
+			existing:         existing(),
+			existingUserData: encoded,
+			imageID:          "ami-1",
+			expected:         true,
+		},
+		{
+			name: "root volume added",
+			spec: infrav1.AWSLaunchTemplate{
+				InstanceType:       "m5.large",
+				IAMInstanceProfile: "nodes",
+				RootVolume:         &infrav1.RootVolume{Size: 50},
+			},
+			existing:         existing(),
+			existingUserData: encoded,
+			imageID:          "ami-1",
+			expected:         true,
+		},
+		{
+			name: "root volume unchanged",
+			spec: infrav1.AWSLaunchTemplate{
+				InstanceType:       "m5.large",
+				IAMInstanceProfile: "nodes",
+				RootVolume:         &infrav1.RootVolume{Size: 50, Type: "gp2", EncryptionKey: "alias/nodes"},
+			},
+			existing: func() *infrav1.AWSLaunchTemplate {
+				lt := existing()
+				lt.RootVolume = &infrav1.RootVolume{Size: 50, Type: "gp2", Encrypted: true, EncryptionKey: "alias/nodes"}
+				return lt
+			}(),
+			existingUserData: encoded,
+			imageID:          "ami-1",
+			expected:         false,
+		},
+		{
+			name: "root volume size changed",
+			spec: infrav1.AWSLaunchTemplate{
+				InstanceType:       "m5.large",
+				IAMInstanceProfile: "nodes",
+				RootVolume:         &infrav1.RootVolume{Size: 100, Type: "gp2"},
+			},
+			existing: func() *infrav1.AWSLaunchTemplate {
+				lt := existing()
+				lt.RootVolume = &infrav1.RootVolume{Size: 50, Type: "gp2"}
+				return lt
+			}(),
+			existingUserData: encoded,
+			imageID:          "ami-1",
+			expected:         true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				AWSClients: scope.AWSClients{
+					EC2: mock_ec2iface.NewMockEC2API(gomock.NewController(t)),
+				},
+				Cluster: &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{
+					Status: infrav1.AWSClusterStatus{
+						Network: infrav1.Network{
+							SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+								infrav1.SecurityGroupNode: {ID: "sg-node"},
+								infrav1.SecurityGroupLB:   {ID: "sg-lb"},
+							},
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			machinePoolScope, err := scope.NewMachinePoolScope(scope.MachinePoolScopeParams{
+				Client:      fake.NewFakeClientWithScheme(runtime.NewScheme()),
+				Cluster:     &clusterv1.Cluster{},
+				MachinePool: &expv1.MachinePool{},
+				AWSCluster:  clusterScope.AWSCluster,
+				AWSMachinePool: &infrav1.AWSMachinePool{
+					Spec: infrav1.AWSMachinePoolSpec{
+						AWSLaunchTemplate: tc.spec,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			s := NewService(clusterScope)
+			needsUpdate, err := s.LaunchTemplateNeedsUpdate(machinePoolScope, tc.existing, tc.existingUserData, aws.String(tc.imageID), userData)
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+
+			if needsUpdate != tc.expected {
+				t.Fatalf("expected needsUpdate to be %v, got %v", tc.expected, needsUpdate)
+			}
+		})
+	}
+}
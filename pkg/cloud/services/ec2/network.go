@@ -54,6 +54,11 @@ func (s *Service) ReconcileNetwork() (err error) {
 		return err
 	}
 
+	// VPC endpoints.
+	if err := s.reconcileVPCEndpoints(); err != nil {
+		return err
+	}
+
 	s.scope.V(2).Info("Reconcile network completed successfully")
 	return nil
 }
@@ -73,6 +78,11 @@ func (s *Service) DeleteNetwork() (err error) {
 	}
 	vpc.DeepCopyInto(s.scope.VPC())
 
+	// VPC endpoints.
+	if err := s.deleteAllVPCEndpoints(); err != nil {
+		return err
+	}
+
 	// Security groups.
 	if err := s.deleteSecurityGroups(); err != nil {
 		return err
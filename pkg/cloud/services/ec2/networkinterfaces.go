@@ -0,0 +1,223 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+// ensureManagedNetworkInterfaces creates the network interfaces declared in the machine spec that
+// do not exist yet, and returns the IDs of all of them in the order they were declared.
+// Network interfaces without a subnet are created in the subnet of the instance.
+func (s *Service) ensureManagedNetworkInterfaces(scope *scope.MachineScope, instance *infrav1.Instance, failureDomain *string) ([]string, error) {
+	existing, err := s.describeManagedNetworkInterfaces(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	// Network interfaces have to be in the availability zone of the instance.
+	if failureDomain == nil {
+		if sn := s.scope.Subnets().FindByID(instance.SubnetID); sn != nil && sn.AvailabilityZone != "" {
+			failureDomain = aws.String(sn.AvailabilityZone)
+		}
+	}
+
+	ids := make([]string, 0, len(scope.AWSMachine.Spec.ManagedNetworkInterfaces))
+	for i, spec := range scope.AWSMachine.Spec.ManagedNetworkInterfaces {
+		name := managedNetworkInterfaceName(scope, i)
+		if eni, ok := existing[name]; ok {
+			ids = append(ids, aws.StringValue(eni.NetworkInterfaceId))
+			continue
+		}
+
+		id, err := s.createManagedNetworkInterface(scope, name, spec, instance, failureDomain)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// DeleteManagedNetworkInterfaces deletes the network interfaces created for the machine.
+func (s *Service) DeleteManagedNetworkInterfaces(scope *scope.MachineScope) error {
+	existing, err := s.describeManagedNetworkInterfaces(scope)
+	if err != nil {
+		return err
+	}
+
+	for name, eni := range existing {
+		id := aws.StringValue(eni.NetworkInterfaceId)
+
+		// Network interfaces are detached asynchronously once the instance is terminated.
+		if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+			if _, err := s.scope.EC2.DeleteNetworkInterface(&ec2.DeleteNetworkInterfaceInput{
+				NetworkInterfaceId: eni.NetworkInterfaceId,
+			}); err != nil {
+				if code, ok := awserrors.Code(errors.Cause(err)); ok && code == awserrors.NetworkInterfaceNotFound {
+					return true, nil
+				}
+				return false, err
+			}
+			return true, nil
+		}, awserrors.NetworkInterfaceInUse); err != nil {
+			record.Warnf(scope.AWSMachine, "FailedDeleteNetworkInterface", "Failed to delete managed network interface %q: %v", id, err)
+			return errors.Wrapf(err, "failed to delete network interface %q", id)
+		}
+
+		record.Eventf(scope.AWSMachine, "SuccessfulDeleteNetworkInterface", "Deleted managed network interface %q", id)
+		s.scope.Info("Deleted network interface", "network-interface-id", id, "name", name)
+	}
+
+	return nil
+}
+
+func (s *Service) createManagedNetworkInterface(scope *scope.MachineScope, name string, spec infrav1.ManagedNetworkInterface, instance *infrav1.Instance, failureDomain *string) (string, error) {
+	subnetID := instance.SubnetID
+	if spec.Subnet != nil {
+		var err error
+		subnetID, err = s.resolveSubnetReference(scope, spec.Subnet, failureDomain)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	groups := instance.SecurityGroupIDs
+	if len(spec.SecurityGroups) > 0 {
+		var err error
+		groups, err = s.GetAdditionalSecurityGroupsIDs(spec.SecurityGroups)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	input := &ec2.CreateNetworkInterfaceInput{
+		SubnetId:                       aws.String(subnetID),
+		SecondaryPrivateIpAddressCount: spec.SecondaryPrivateIPAddressCount,
+	}
+	if len(groups) > 0 {
+		input.Groups = aws.StringSlice(groups)
+	}
+	if spec.Description != "" {
+		input.Description = aws.String(spec.Description)
+	}
+
+	out, err := s.scope.EC2.CreateNetworkInterface(input)
+	if err != nil {
+		record.Warnf(scope.AWSMachine, "FailedCreateNetworkInterface", "Failed to create managed network interface %q: %v", name, err)
+		return "", errors.Wrapf(err, "failed to create network interface %q", name)
+	}
+	id := aws.StringValue(out.NetworkInterface.NetworkInterfaceId)
+
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if err := tags.Apply(&tags.ApplyParams{
+			EC2Client: s.scope.EC2,
+			BuildParams: infrav1.BuildParams{
+				ClusterName: s.scope.Name(),
+				ResourceID:  id,
+				Lifecycle:   infrav1.ResourceLifecycleOwned,
+				Name:        aws.String(name),
+				Role:        aws.String(infrav1.NetworkInterfaceRoleTagValue),
+				Additional:  scope.AdditionalTags(),
+			},
+		}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, awserrors.NetworkInterfaceNotFound); err != nil {
+		record.Warnf(scope.AWSMachine, "FailedTagNetworkInterface", "Failed to tag managed network interface %q: %v", id, err)
+
+		// Untagged network interfaces can't be found again, so delete it rather than leaking it.
+		if _, deleteErr := s.scope.EC2.DeleteNetworkInterface(&ec2.DeleteNetworkInterfaceInput{
+			NetworkInterfaceId: aws.String(id),
+		}); deleteErr != nil {
+			record.Warnf(scope.AWSMachine, "FailedDeleteNetworkInterface", "Failed to delete untagged managed network interface %q: %v", id, deleteErr)
+			return "", errors.Wrapf(err, "failed to tag network interface %q, and failed to delete it: %v", id, deleteErr)
+		}
+
+		return "", errors.Wrapf(err, "failed to tag network interface %q", id)
+	}
+
+	record.Eventf(scope.AWSMachine, "SuccessfulCreateNetworkInterface", "Created new managed network interface %q in subnet %q", id, subnetID)
+	s.scope.Info("Created network interface", "network-interface-id", id, "name", name, "subnet-id", subnetID)
+	return id, nil
+}
+
+// describeManagedNetworkInterfaces returns the network interfaces created for the machine, by name.
+func (s *Service) describeManagedNetworkInterfaces(scope *scope.MachineScope) (map[string]*ec2.NetworkInterface, error) {
+	count := len(scope.AWSMachine.Spec.ManagedNetworkInterfaces)
+	if count == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		names = append(names, managedNetworkInterfaceName(scope, i))
+	}
+
+	out, err := s.scope.EC2.DescribeNetworkInterfaces(&ec2.DescribeNetworkInterfacesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.ClusterOwned(s.scope.Name()),
+			filter.EC2.ProviderRole(infrav1.NetworkInterfaceRoleTagValue),
+			{
+				Name:   aws.String("tag:Name"),
+				Values: aws.StringSlice(names),
+			},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe network interfaces of machine %q", scope.Name())
+	}
+
+	enis := make(map[string]*ec2.NetworkInterface, len(out.NetworkInterfaces))
+	for _, eni := range out.NetworkInterfaces {
+		for _, tag := range eni.TagSet {
+			if aws.StringValue(tag.Key) == "Name" {
+				enis[aws.StringValue(tag.Value)] = eni
+			}
+		}
+	}
+
+	return enis, nil
+}
+
+// isManagedNetworkInterface returns whether the network interface was created for a machine
+// as declared in its spec.
+func isManagedNetworkInterface(eni *ec2.NetworkInterface) bool {
+	for _, tag := range eni.TagSet {
+		if aws.StringValue(tag.Key) == infrav1.NameAWSClusterAPIRole && aws.StringValue(tag.Value) == infrav1.NetworkInterfaceRoleTagValue {
+			return true
+		}
+	}
+	return false
+}
+
+func managedNetworkInterfaceName(scope *scope.MachineScope, index int) string {
+	return fmt.Sprintf("%s-eni-%d", scope.Name(), index)
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+)
+
+func TestEnsureManagedNetworkInterfaces(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+	clusterScope, machineScope := newMachineTestScopes(t, ec2Mock, infrav1.AWSMachineSpec{
+		ManagedNetworkInterfaces: []infrav1.ManagedNetworkInterface{
+			{},
+			{
+				Subnet:                         &infrav1.AWSResourceReference{ID: aws.String("subnet-2")},
+				SecondaryPrivateIPAddressCount: aws.Int64(2),
+				Description:                    "storage",
+			},
+		},
+	})
+
+	m := ec2Mock.EXPECT()
+	m.DescribeNetworkInterfaces(gomock.AssignableToTypeOf(&ec2.DescribeNetworkInterfacesInput{})).
+		Return(&ec2.DescribeNetworkInterfacesOutput{
+			NetworkInterfaces: []*ec2.NetworkInterface{
+				{
+					NetworkInterfaceId: aws.String("eni-1"),
+					TagSet: []*ec2.Tag{
+						{Key: aws.String("Name"), Value: aws.String("test-machine-eni-0")},
+					},
+				},
+			},
+		}, nil)
+	m.CreateNetworkInterface(gomock.Eq(&ec2.CreateNetworkInterfaceInput{
+		SubnetId:                       aws.String("subnet-2"),
+		Groups:                         aws.StringSlice([]string{"sg-1"}),
+		SecondaryPrivateIpAddressCount: aws.Int64(2),
+		Description:                    aws.String("storage"),
+	})).
+		Return(&ec2.CreateNetworkInterfaceOutput{
+			NetworkInterface: &ec2.NetworkInterface{NetworkInterfaceId: aws.String("eni-2")},
+		}, nil)
+	m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+		Return(nil, nil)
+
+	s := NewService(clusterScope)
+	ids, err := s.ensureManagedNetworkInterfaces(machineScope, &infrav1.Instance{
+		SubnetID:         "subnet-1",
+		SecurityGroupIDs: []string{"sg-1"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+
+	if expected := []string{"eni-1", "eni-2"}; !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("expected network interfaces %v, got %v", expected, ids)
+	}
+}
+
+func TestEnsureManagedNetworkInterfacesDeletesUntaggedNetworkInterface(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+	clusterScope, machineScope := newMachineTestScopes(t, ec2Mock, infrav1.AWSMachineSpec{
+		ManagedNetworkInterfaces: []infrav1.ManagedNetworkInterface{{}},
+	})
+
+	m := ec2Mock.EXPECT()
+	m.DescribeNetworkInterfaces(gomock.AssignableToTypeOf(&ec2.DescribeNetworkInterfacesInput{})).
+		Return(&ec2.DescribeNetworkInterfacesOutput{}, nil)
+	m.CreateNetworkInterface(gomock.AssignableToTypeOf(&ec2.CreateNetworkInterfaceInput{})).
+		Return(&ec2.CreateNetworkInterfaceOutput{
+			NetworkInterface: &ec2.NetworkInterface{NetworkInterfaceId: aws.String("eni-1")},
+		}, nil)
+	m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+		Return(nil, awserr.New("UnauthorizedOperation", "not authorized to create tags", nil))
+	m.DeleteNetworkInterface(gomock.Eq(&ec2.DeleteNetworkInterfaceInput{NetworkInterfaceId: aws.String("eni-1")})).
+		Return(&ec2.DeleteNetworkInterfaceOutput{}, nil)
+
+	s := NewService(clusterScope)
+	if _, err := s.ensureManagedNetworkInterfaces(machineScope, &infrav1.Instance{
+		SubnetID:         "subnet-1",
+		SecurityGroupIDs: []string{"sg-1"},
+	}, nil); err == nil {
+		t.Fatal("expected an error when the network interface can't be tagged")
+	}
+}
+
+func TestDeleteManagedNetworkInterfaces(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+	clusterScope, machineScope := newMachineTestScopes(t, ec2Mock, infrav1.AWSMachineSpec{
+		ManagedNetworkInterfaces: []infrav1.ManagedNetworkInterface{{}, {}},
+	})
+
+	m := ec2Mock.EXPECT()
+	m.DescribeNetworkInterfaces(gomock.AssignableToTypeOf(&ec2.DescribeNetworkInterfacesInput{})).
+		Return(&ec2.DescribeNetworkInterfacesOutput{
+			NetworkInterfaces: []*ec2.NetworkInterface{
+				{
+					NetworkInterfaceId: aws.String("eni-1"),
+					TagSet: []*ec2.Tag{
+						{Key: aws.String("Name"), Value: aws.String("test-machine-eni-0")},
+					},
+				},
+				{
+					NetworkInterfaceId: aws.String("eni-2"),
+					TagSet: []*ec2.Tag{
+						{Key: aws.String("Name"), Value: aws.String("test-machine-eni-1")},
+					},
+				},
+			},
+		}, nil)
+	m.DeleteNetworkInterface(gomock.Eq(&ec2.DeleteNetworkInterfaceInput{NetworkInterfaceId: aws.String("eni-1")})).
+		Return(&ec2.DeleteNetworkInterfaceOutput{}, nil)
+	m.DeleteNetworkInterface(gomock.Eq(&ec2.DeleteNetworkInterfaceInput{NetworkInterfaceId: aws.String("eni-2")})).
+		Return(nil, awserr.New(awserrors.NetworkInterfaceNotFound, "not found", nil))
+
+	s := NewService(clusterScope)
+	if err := s.DeleteManagedNetworkInterfaces(machineScope); err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+}
+
+func TestGetInstanceSecurityGroupsSkipsManagedNetworkInterfaces(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+	clusterScope, _ := newMachineTestScopes(t, ec2Mock, infrav1.AWSMachineSpec{})
+
+	ec2Mock.EXPECT().
+		DescribeNetworkInterfaces(gomock.AssignableToTypeOf(&ec2.DescribeNetworkInterfacesInput{})).
+		Return(&ec2.DescribeNetworkInterfacesOutput{
+			NetworkInterfaces: []*ec2.NetworkInterface{
+				{
+					NetworkInterfaceId: aws.String("eni-primary"),
+					Groups:             []*ec2.GroupIdentifier{{GroupId: aws.String("sg-1")}},
+				},
+				{
+					NetworkInterfaceId: aws.String("eni-managed"),
+					Groups:             []*ec2.GroupIdentifier{{GroupId: aws.String("sg-2")}},
+					TagSet: []*ec2.Tag{
+						{Key: aws.String(infrav1.NameAWSClusterAPIRole), Value: aws.String(infrav1.NetworkInterfaceRoleTagValue)},
+					},
+				},
+			},
+		}, nil)
+
+	s := NewService(clusterScope)
+	groups, err := s.GetInstanceSecurityGroups("i-1")
+	if err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+
+	if expected := map[string][]string{"eni-primary": {"sg-1"}}; !reflect.DeepEqual(groups, expected) {
+		t.Fatalf("expected security groups %v, got %v", expected, groups)
+	}
+}
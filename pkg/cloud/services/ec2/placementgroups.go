@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+// ReconcilePlacementGroups creates the placement groups declared in the AWSCluster spec
+// that do not exist yet.
+func (s *Service) ReconcilePlacementGroups() error {
+	if len(s.scope.AWSCluster.Spec.PlacementGroups) == 0 {
+		return nil
+	}
+
+	s.scope.V(2).Info("Reconciling placement groups")
+
+	names := make([]string, 0, len(s.scope.AWSCluster.Spec.PlacementGroups))
+	for _, pg := range s.scope.AWSCluster.Spec.PlacementGroups {
+		names = append(names, pg.Name)
+	}
+
+	existing, err := s.describePlacementGroups(&ec2.Filter{
+		Name:   aws.String("group-name"),
+		Values: aws.StringSlice(names),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, spec := range s.scope.AWSCluster.Spec.PlacementGroups {
+		if pg, ok := existing[spec.Name]; ok {
+			// The strategy of a placement group can't be changed, and instances launched in it wouldn't be
+			// placed the way the spec asks for.
+			if aws.StringValue(pg.Strategy) != string(spec.Strategy) {
+				record.Warnf(s.scope.AWSCluster, "PlacementGroupStrategyMismatch", "Placement group %q has strategy %q, expected %q", spec.Name, aws.StringValue(pg.Strategy), spec.Strategy)
+				return errors.Errorf("placement group %q has strategy %q, expected %q", spec.Name, aws.StringValue(pg.Strategy), spec.Strategy)
+			}
+			continue
+		}
+
+		if err := s.createPlacementGroup(spec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeletePlacementGroups deletes the placement groups owned by the cluster.
+func (s *Service) DeletePlacementGroups() error {
+	existing, err := s.describePlacementGroups(filter.EC2.ClusterOwned(s.scope.Name()))
+	if err != nil {
+		return err
+	}
+
+	for name := range existing {
+		if _, err := s.scope.EC2.DeletePlacementGroup(&ec2.DeletePlacementGroupInput{
+			GroupName: aws.String(name),
+		}); err != nil {
+			if code, ok := awserrors.Code(err); ok && code == awserrors.PlacementGroupNotFound {
+				continue
+			}
+			record.Warnf(s.scope.AWSCluster, "FailedDeletePlacementGroup", "Failed to delete managed placement group %q: %v", name, err)
+			return errors.Wrapf(err, "failed to delete placement group %q", name)
+		}
+
+		record.Eventf(s.scope.AWSCluster, "SuccessfulDeletePlacementGroup", "Deleted managed placement group %q", name)
+		s.scope.Info("Deleted placement group", "placement-group", name)
+	}
+
+	return nil
+}
+
+func (s *Service) createPlacementGroup(spec infrav1.PlacementGroupSpec) error {
+	input := &ec2.CreatePlacementGroupInput{
+		GroupName:      aws.String(spec.Name),
+		Strategy:       aws.String(string(spec.Strategy)),
+		PartitionCount: spec.PartitionCount,
+	}
+
+	if _, err := s.scope.EC2.CreatePlacementGroup(input); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedCreatePlacementGroup", "Failed to create managed placement group %q: %v", spec.Name, err)
+		return errors.Wrapf(err, "failed to create placement group %q", spec.Name)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulCreatePlacementGroup", "Created new managed placement group %q", spec.Name)
+	s.scope.Info("Created placement group", "placement-group", spec.Name)
+
+	// The placement group ID is not returned on creation, so it has to be looked up to tag the group.
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		out, err := s.scope.EC2.DescribePlacementGroups(&ec2.DescribePlacementGroupsInput{
+			GroupNames: aws.StringSlice([]string{spec.Name}),
+		})
+		if err != nil {
+			return false, err
+		}
+		if len(out.PlacementGroups) == 0 {
+			return false, nil
+		}
+
+		if err := tags.Apply(&tags.ApplyParams{
+			EC2Client: s.scope.EC2,
+			BuildParams: infrav1.BuildParams{
+				ClusterName: s.scope.Name(),
+				ResourceID:  aws.StringValue(out.PlacementGroups[0].GroupId),
+				Lifecycle:   infrav1.ResourceLifecycleOwned,
+				Name:        aws.String(spec.Name),
+				Role:        aws.String(infrav1.CommonRoleTagValue),
+				Additional:  s.scope.AdditionalTags(),
+			},
+		}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, awserrors.PlacementGroupNotFound); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedTagPlacementGroup", "Failed to tag managed placement group %q: %v", spec.Name, err)
+
+		// Untagged placement groups would be taken for unmanaged ones and never be deleted, so delete it
+		// and create it again on the next reconciliation.
+		if _, deleteErr := s.scope.EC2.DeletePlacementGroup(&ec2.DeletePlacementGroupInput{
+			GroupName: aws.String(spec.Name),
+		}); deleteErr != nil {
+			record.Warnf(s.scope.AWSCluster, "FailedDeletePlacementGroup", "Failed to delete untagged managed placement group %q: %v", spec.Name, deleteErr)
+			return errors.Wrapf(err, "failed to tag placement group %q, and failed to delete it: %v", spec.Name, deleteErr)
+		}
+
+		return errors.Wrapf(err, "failed to tag placement group %q", spec.Name)
+	}
+
+	return nil
+}
+
+func (s *Service) describePlacementGroups(filters ...*ec2.Filter) (map[string]*ec2.PlacementGroup, error) {
+	out, err := s.scope.EC2.DescribePlacementGroups(&ec2.DescribePlacementGroupsInput{
+		Filters: filters,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to describe placement groups")
+	}
+
+	groups := make(map[string]*ec2.PlacementGroup, len(out.PlacementGroups))
+	for _, pg := range out.PlacementGroups {
+		groups[aws.StringValue(pg.GroupName)] = pg
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+func TestReconcilePlacementGroups(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name      string
+		input     []infrav1.PlacementGroupSpec
+		expect    func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectErr bool
+	}{
+		{
+			name: "no placement groups",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+			},
+		},
+		{
+			name: "placement group exists",
+			input: []infrav1.PlacementGroupSpec{
+				{Name: "pg-cluster", Strategy: infrav1.PlacementGroupStrategyCluster},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribePlacementGroups(gomock.AssignableToTypeOf(&ec2.DescribePlacementGroupsInput{})).
+					Return(&ec2.DescribePlacementGroupsOutput{
+						PlacementGroups: []*ec2.PlacementGroup{
+							{
+								GroupId:   aws.String("pg-1"),
+								GroupName: aws.String("pg-cluster"),
+								Strategy:  aws.String(ec2.PlacementStrategyCluster),
+							},
+						},
+					}, nil)
+			},
+		},
+		{
+			name: "placement group does not exist, creates one",
+			input: []infrav1.PlacementGroupSpec{
+				{Name: "pg-partition", Strategy: infrav1.PlacementGroupStrategyPartition, PartitionCount: aws.Int64(3)},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribePlacementGroups(gomock.AssignableToTypeOf(&ec2.DescribePlacementGroupsInput{})).
+					Return(&ec2.DescribePlacementGroupsOutput{}, nil)
+
+				m.CreatePlacementGroup(gomock.Eq(&ec2.CreatePlacementGroupInput{
+					GroupName:      aws.String("pg-partition"),
+					Strategy:       aws.String(ec2.PlacementStrategyPartition),
+					PartitionCount: aws.Int64(3),
+				})).
+					Return(&ec2.CreatePlacementGroupOutput{}, nil)
+
+				m.DescribePlacementGroups(gomock.Eq(&ec2.DescribePlacementGroupsInput{
+					GroupNames: aws.StringSlice([]string{"pg-partition"}),
+				})).
+					Return(&ec2.DescribePlacementGroupsOutput{
+						PlacementGroups: []*ec2.PlacementGroup{
+							{
+								GroupId:   aws.String("pg-2"),
+								GroupName: aws.String("pg-partition"),
+							},
+						},
+					}, nil)
+
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, nil)
+			},
+		},
+		{
+			name: "placement group exists with a different strategy",
+			input: []infrav1.PlacementGroupSpec{
+				{Name: "pg-cluster", Strategy: infrav1.PlacementGroupStrategyCluster},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribePlacementGroups(gomock.AssignableToTypeOf(&ec2.DescribePlacementGroupsInput{})).
+					Return(&ec2.DescribePlacementGroupsOutput{
+						PlacementGroups: []*ec2.PlacementGroup{
+							{
+								GroupId:   aws.String("pg-1"),
+								GroupName: aws.String("pg-cluster"),
+								Strategy:  aws.String(ec2.PlacementStrategySpread),
+							},
+						},
+					}, nil)
+			},
+			expectErr: true,
+		},
+		{
+			name: "placement group can't be tagged, deletes it",
+			input: []infrav1.PlacementGroupSpec{
+				{Name: "pg-spread", Strategy: infrav1.PlacementGroupStrategySpread},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribePlacementGroups(gomock.AssignableToTypeOf(&ec2.DescribePlacementGroupsInput{})).
+					Return(&ec2.DescribePlacementGroupsOutput{}, nil)
+
+				m.CreatePlacementGroup(gomock.AssignableToTypeOf(&ec2.CreatePlacementGroupInput{})).
+					Return(&ec2.CreatePlacementGroupOutput{}, nil)
+
+				m.DescribePlacementGroups(gomock.Eq(&ec2.DescribePlacementGroupsInput{
+					GroupNames: aws.StringSlice([]string{"pg-spread"}),
+				})).
+					Return(&ec2.DescribePlacementGroupsOutput{
+						PlacementGroups: []*ec2.PlacementGroup{
+							{
+								GroupId:   aws.String("pg-3"),
+								GroupName: aws.String("pg-spread"),
+							},
+						},
+					}, nil)
+
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, awserr.New("UnauthorizedOperation", "not authorized to create tags", nil))
+
+				m.DeletePlacementGroup(gomock.Eq(&ec2.DeletePlacementGroupInput{
+					GroupName: aws.String("pg-spread"),
+				})).
+					Return(&ec2.DeletePlacementGroupOutput{}, nil)
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						PlacementGroups: tc.input,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			err = s.ReconcilePlacementGroups()
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDeletePlacementGroups(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSClients: scope.AWSClients{
+			EC2: ec2Mock,
+		},
+		AWSCluster: &infrav1.AWSCluster{},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	m := ec2Mock.EXPECT()
+	m.DescribePlacementGroups(gomock.AssignableToTypeOf(&ec2.DescribePlacementGroupsInput{})).
+		Return(&ec2.DescribePlacementGroupsOutput{
+			PlacementGroups: []*ec2.PlacementGroup{
+				{GroupName: aws.String("pg-1")},
+				{GroupName: aws.String("pg-2")},
+			},
+		}, nil)
+	m.DeletePlacementGroup(gomock.Eq(&ec2.DeletePlacementGroupInput{GroupName: aws.String("pg-1")})).
+		Return(&ec2.DeletePlacementGroupOutput{}, nil)
+	m.DeletePlacementGroup(gomock.Eq(&ec2.DeletePlacementGroupInput{GroupName: aws.String("pg-2")})).
+		Return(nil, awserr.New(awserrors.PlacementGroupNotFound, "not found", nil))
+
+	s := NewService(scope)
+	if err := s.DeletePlacementGroups(); err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+}
@@ -67,6 +67,9 @@ func (s *Service) reconcileSecurityGroups() error {
 		infrav1.SecurityGroupControlPlane,
 		infrav1.SecurityGroupNode,
 	}
+	if s.scope.VPCEndpointsEnabled() {
+		roles = append(roles, infrav1.SecurityGroupVPCEndpoint)
+	}
 
 	// First iteration makes sure that the security group are valid and fully created.
 	for i := range roles {
@@ -365,21 +368,75 @@ func (s *Service) defaultSSHIngressRule(sourceSecurityGroupID string) *infrav1.I
 	}
 }
 
+// sshIngressRules returns the rules allowing SSH from the bastion host to the cluster machines,
+// which are omitted when machines are accessed through Session Manager or none of them use an SSH key.
+func (s *Service) sshIngressRules() (infrav1.IngressRules, error) {
+	if s.scope.AccessMode() == infrav1.ClusterAccessModeSessionManager {
+		return infrav1.IngressRules{}, nil
+	}
+
+	inUse, err := s.sshKeysInUse()
+	if err != nil {
+		return nil, err
+	}
+	if !inUse {
+		return infrav1.IngressRules{}, nil
+	}
+
+	return infrav1.IngressRules{
+		s.defaultSSHIngressRule(s.scope.SecurityGroups()[infrav1.SecurityGroupBastion].ID),
+	}, nil
+}
+
+// sshKeysInUse returns whether any machine of the cluster is launched with an SSH key. Machines
+// are only listed when the cluster is configured without an SSH key, as they use it by default.
+func (s *Service) sshKeysInUse() (bool, error) {
+	if s.sshKeyName(nil) != nil {
+		return true, nil
+	}
+
+	keys, err := s.scope.MachineSSHKeyNames()
+	if err != nil {
+		return false, err
+	}
+	for _, key := range keys {
+		if s.sshKeyName(key) != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (infrav1.IngressRules, error) {
+	var sshRules infrav1.IngressRules
+	if role == infrav1.SecurityGroupControlPlane || role == infrav1.SecurityGroupNode {
+		var err error
+		if sshRules, err = s.sshIngressRules(); err != nil {
+			return nil, err
+		}
+	}
+
 	switch role {
 	case infrav1.SecurityGroupBastion:
+		if s.scope.AccessMode() == infrav1.ClusterAccessModeSessionManager {
+			return infrav1.IngressRules{}, nil
+		}
+		// Copy the CIDR blocks, as comparing ingress rules sorts them.
+		cidrBlocks := append([]string{}, s.scope.AWSCluster.Spec.Bastion.AllowedCIDRBlocks...)
+		if len(cidrBlocks) == 0 {
+			cidrBlocks = []string{anyIPv4CidrBlock}
+		}
 		return infrav1.IngressRules{
 			{
 				Description: "SSH",
 				Protocol:    infrav1.SecurityGroupProtocolTCP,
 				FromPort:    22,
 				ToPort:      22,
-				CidrBlocks:  []string{anyIPv4CidrBlock},
+				CidrBlocks:  cidrBlocks,
 			},
 		}, nil
 	case infrav1.SecurityGroupControlPlane:
-		return infrav1.IngressRules{
-			s.defaultSSHIngressRule(s.scope.SecurityGroups()[infrav1.SecurityGroupBastion].ID),
+		return append(sshRules, infrav1.IngressRules{
 			{
 				Description: "Kubernetes API",
 				Protocol:    infrav1.SecurityGroupProtocolTCP,
@@ -425,11 +482,10 @@ func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (
 					s.scope.SecurityGroups()[infrav1.SecurityGroupNode].ID,
 				},
 			},
-		}, nil
+		}...), nil
 
 	case infrav1.SecurityGroupNode:
-		return infrav1.IngressRules{
-			s.defaultSSHIngressRule(s.scope.SecurityGroups()[infrav1.SecurityGroupBastion].ID),
+		return append(sshRules, infrav1.IngressRules{
 			{
 				Description: "Node Port Services",
 				Protocol:    infrav1.SecurityGroupProtocolTCP,
@@ -468,7 +524,7 @@ func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (
 					s.scope.SecurityGroups()[infrav1.SecurityGroupControlPlane].ID,
 				},
 			},
-		}, nil
+		}...), nil
 	case infrav1.SecurityGroupAPIServerLB:
 		return infrav1.IngressRules{
 			{
@@ -479,6 +535,19 @@ func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (
 				CidrBlocks:  []string{anyIPv4CidrBlock},
 			},
 		}, nil
+	case infrav1.SecurityGroupVPCEndpoint:
+		return infrav1.IngressRules{
+			{
+				Description: "HTTPS",
+				Protocol:    infrav1.SecurityGroupProtocolTCP,
+				FromPort:    443,
+				ToPort:      443,
+				SourceSecurityGroupIDs: []string{
+					s.scope.SecurityGroups()[infrav1.SecurityGroupControlPlane].ID,
+					s.scope.SecurityGroups()[infrav1.SecurityGroupNode].ID,
+				},
+			},
+		}, nil
 	case infrav1.SecurityGroupLB:
 		// We hand this group off to the in-cluster cloud provider, so these rules aren't used
 		return infrav1.IngressRules{}, nil
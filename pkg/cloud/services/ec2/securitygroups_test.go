@@ -26,12 +26,14 @@ import (
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/golang/mock/gomock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb/mock_elbiface"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestReconcileSecurityGroups(t *testing.T) {
@@ -305,6 +307,159 @@ func TestControlPlaneSecurityGroupNotOpenToAnyCIDR(t *testing.T) {
 	}
 }
 
+func TestBastionSecurityGroupAllowedCIDRBlocks(t *testing.T) {
+	testCases := []struct {
+		name     string
+		allowed  []string
+		expected []string
+	}{
+		{
+			name:     "defaults to any CIDR block",
+			expected: []string{anyIPv4CidrBlock},
+		},
+		{
+			name:     "allows the configured CIDR blocks",
+			allowed:  []string{"192.168.0.0/24", "10.0.0.0/16"},
+			expected: []string{"10.0.0.0/16", "192.168.0.0/24"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						Bastion: infrav1.Bastion{AllowedCIDRBlocks: tc.allowed},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			s := NewService(scope)
+			rules, err := s.getSecurityGroupIngressRules(infrav1.SecurityGroupBastion)
+			if err != nil {
+				t.Fatalf("Failed to lookup bastion security group ingress rules: %v", err)
+			}
+
+			if len(rules) != 1 || !sets.NewString(rules[0].CidrBlocks...).Equal(sets.NewString(tc.expected...)) {
+				t.Fatalf("Expected a single SSH rule from %v, got %v", tc.expected, rules)
+			}
+		})
+	}
+}
+
+func TestSessionManagerSecurityGroupsHaveNoSSHIngressRules(t *testing.T) {
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			Spec: infrav1.AWSClusterSpec{
+				AccessMode: infrav1.ClusterAccessModeSessionManager,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	s := NewService(scope)
+	for _, role := range []infrav1.SecurityGroupRole{infrav1.SecurityGroupBastion, infrav1.SecurityGroupControlPlane, infrav1.SecurityGroupNode} {
+		rules, err := s.getSecurityGroupIngressRules(role)
+		if err != nil {
+			t.Fatalf("Failed to lookup %s security group ingress rules: %v", role, err)
+		}
+
+		for _, r := range rules {
+			if r.FromPort <= 22 && r.ToPort >= 22 && r.Protocol == infrav1.SecurityGroupProtocolTCP {
+				t.Fatalf("Ingress rule %q of the %s security group allows SSH", r.Description, role)
+			}
+		}
+	}
+}
+
+func TestSSHIngressRulesFollowMachineSSHKeys(t *testing.T) {
+	awsMachine := func(name string, key *string) *infrav1.AWSMachine {
+		return &infrav1.AWSMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{clusterv1.ClusterLabelName: "test-cluster"},
+			},
+			Spec: infrav1.AWSMachineSpec{SSHKeyName: key},
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		clusterKey *string
+		machines   []runtime.Object
+		expectSSH  bool
+	}{
+		{
+			name:      "machines use the default SSH key",
+			machines:  []runtime.Object{awsMachine("machine-1", nil)},
+			expectSSH: true,
+		},
+		{
+			name:       "no machine uses an SSH key",
+			clusterKey: aws.String(""),
+			machines:   []runtime.Object{awsMachine("machine-1", nil), awsMachine("machine-2", aws.String(""))},
+		},
+		{
+			name:       "a machine sets its own SSH key",
+			clusterKey: aws.String(""),
+			machines:   []runtime.Object{awsMachine("machine-1", nil), awsMachine("machine-2", aws.String("my-key"))},
+			expectSSH:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := infrav1.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed to add infrav1 to scheme: %v", err)
+			}
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: fake.NewFakeClientWithScheme(scheme, tc.machines...),
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{SSHKeyName: tc.clusterKey},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			s := NewService(scope)
+			for _, role := range []infrav1.SecurityGroupRole{infrav1.SecurityGroupControlPlane, infrav1.SecurityGroupNode} {
+				rules, err := s.getSecurityGroupIngressRules(role)
+				if err != nil {
+					t.Fatalf("Failed to lookup %s security group ingress rules: %v", role, err)
+				}
+
+				hasSSH := false
+				for _, r := range rules {
+					if r.Description == "SSH" {
+						hasSSH = true
+					}
+				}
+				if hasSSH != tc.expectSSH {
+					t.Fatalf("Expected SSH ingress rule in the %s security group to be %t, got %t", role, tc.expectSSH, hasSSH)
+				}
+			}
+		})
+	}
+}
+
 func matchesTags(input *ec2.CreateTagsInput) gomock.Matcher {
 	return tagMatcher{input}
 }
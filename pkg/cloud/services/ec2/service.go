@@ -25,6 +25,10 @@ import (
 // One alternative is to have a large list of functions from the ec2 client.
 type Service struct {
 	scope *scope.ClusterScope
+
+	// amiResolverFactory returns the resolver used for an AMI lookup.
+	// When nil, AMIs are resolved from SSM Parameter Store or by name.
+	amiResolverFactory func(lookup *AMILookup) AMIResolver
 }
 
 // NewService returns a new service given the ec2 api client.
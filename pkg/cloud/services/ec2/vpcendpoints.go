@@ -0,0 +1,251 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+// sessionManagerServices are the services instances have to reach to be managed by Session Manager.
+var sessionManagerServices = []string{"ssm", "ssmmessages", "ec2messages"}
+
+// reconcileVPCEndpoints creates the VPC interface endpoints of Session Manager in the private subnets
+// of the cluster when they are enabled, and deletes them along with their security group otherwise.
+func (s *Service) reconcileVPCEndpoints() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping VPC endpoints reconcile in unmanaged mode")
+		return nil
+	}
+
+	if !s.scope.VPCEndpointsEnabled() {
+		sg, ok := s.scope.SecurityGroups()[infrav1.SecurityGroupVPCEndpoint]
+		if !ok {
+			return nil
+		}
+
+		if err := s.deleteAllVPCEndpoints(); err != nil {
+			return err
+		}
+		if err := s.deleteSecurityGroup(&sg, "VPC endpoint"); err != nil {
+			return err
+		}
+		delete(s.scope.SecurityGroups(), infrav1.SecurityGroupVPCEndpoint)
+		return nil
+	}
+
+	s.scope.V(2).Info("Reconciling VPC endpoints")
+
+	existing, err := s.describeVPCEndpoints()
+	if err != nil {
+		return err
+	}
+
+	subnetIDs := s.vpcEndpointSubnetIDs()
+	if len(subnetIDs) == 0 {
+		s.scope.V(2).Info("No private subnets available, skipping VPC endpoints")
+		return nil
+	}
+
+	for _, service := range sessionManagerServices {
+		serviceName := s.vpcEndpointServiceName(service)
+		if _, ok := existing[serviceName]; ok {
+			continue
+		}
+
+		if err := s.createVPCEndpoint(service, serviceName, subnetIDs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteAllVPCEndpoints deletes the VPC endpoints created for the cluster. There are none unless the
+// security group of the endpoints was created.
+func (s *Service) deleteAllVPCEndpoints() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping VPC endpoints deletion in unmanaged mode")
+		return nil
+	}
+
+	if _, ok := s.scope.SecurityGroups()[infrav1.SecurityGroupVPCEndpoint]; !ok {
+		return nil
+	}
+
+	existing, err := s.describeVPCEndpoints()
+	if err != nil {
+		return err
+	}
+
+	return s.deleteVPCEndpoints(existing)
+}
+
+func (s *Service) createVPCEndpoint(service, serviceName string, subnetIDs []string) error {
+	out, err := s.scope.EC2.CreateVpcEndpoint(&ec2.CreateVpcEndpointInput{
+		VpcEndpointType:   aws.String(ec2.VpcEndpointTypeInterface),
+		VpcId:             aws.String(s.scope.VPC().ID),
+		ServiceName:       aws.String(serviceName),
+		SubnetIds:         aws.StringSlice(subnetIDs),
+		SecurityGroupIds:  aws.StringSlice([]string{s.scope.SecurityGroups()[infrav1.SecurityGroupVPCEndpoint].ID}),
+		PrivateDnsEnabled: aws.Bool(true),
+	})
+	if err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedCreateVPCEndpoint", "Failed to create VPC endpoint for service %q: %v", serviceName, err)
+		return errors.Wrapf(err, "failed to create VPC endpoint for service %q", serviceName)
+	}
+	id := aws.StringValue(out.VpcEndpoint.VpcEndpointId)
+
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if err := tags.Apply(&tags.ApplyParams{
+			EC2Client:   s.scope.EC2,
+			BuildParams: s.getVPCEndpointTagParams(id, service),
+		}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, awserrors.VPCEndpointNotFound); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedTagVPCEndpoint", "Failed to tag VPC endpoint %q: %v", id, err)
+
+		// Untagged VPC endpoints can't be found again, so delete it rather than leaking it.
+		out, deleteErr := s.scope.EC2.DeleteVpcEndpoints(&ec2.DeleteVpcEndpointsInput{
+			VpcEndpointIds: aws.StringSlice([]string{id}),
+		})
+		if deleteErr == nil && len(out.Unsuccessful) > 0 {
+			deleteErr = errors.New(aws.StringValue(out.Unsuccessful[0].Error.Message))
+		}
+		if deleteErr != nil {
+			record.Warnf(s.scope.AWSCluster, "FailedDeleteVPCEndpoints", "Failed to delete untagged VPC endpoint %q: %v", id, deleteErr)
+			return errors.Wrapf(err, "failed to tag VPC endpoint %q, and failed to delete it: %v", id, deleteErr)
+		}
+
+		return errors.Wrapf(err, "failed to tag VPC endpoint %q", id)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulCreateVPCEndpoint", "Created new VPC endpoint %q for service %q", id, serviceName)
+	s.scope.Info("Created VPC endpoint", "vpc-endpoint-id", id, "service-name", serviceName, "subnet-ids", subnetIDs)
+	return nil
+}
+
+// deleteVPCEndpoints deletes the given VPC endpoints and waits for them to be gone, as they hold
+// network interfaces in the security groups and subnets of the cluster.
+func (s *Service) deleteVPCEndpoints(endpoints map[string]*ec2.VpcEndpoint) error {
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	ids := make([]*string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		ids = append(ids, endpoint.VpcEndpointId)
+	}
+
+	out, err := s.scope.EC2.DeleteVpcEndpoints(&ec2.DeleteVpcEndpointsInput{
+		VpcEndpointIds: ids,
+	})
+	if err == nil && len(out.Unsuccessful) > 0 {
+		err = errors.New(aws.StringValue(out.Unsuccessful[0].Error.Message))
+	}
+	if err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedDeleteVPCEndpoints", "Failed to delete VPC endpoints %v: %v", aws.StringValueSlice(ids), err)
+		return errors.Wrapf(err, "failed to delete VPC endpoints %v", aws.StringValueSlice(ids))
+	}
+
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		remaining, err := s.describeVPCEndpoints()
+		if err != nil {
+			return false, err
+		}
+		return len(remaining) == 0, nil
+	}); err != nil {
+		return errors.Wrapf(err, "failed to wait for VPC endpoints %v to be deleted", aws.StringValueSlice(ids))
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulDeleteVPCEndpoints", "Deleted VPC endpoints %v", aws.StringValueSlice(ids))
+	s.scope.Info("Deleted VPC endpoints", "vpc-endpoint-ids", aws.StringValueSlice(ids))
+	return nil
+}
+
+// describeVPCEndpoints returns the VPC endpoints created for the cluster that are not being deleted, by service name.
+func (s *Service) describeVPCEndpoints() (map[string]*ec2.VpcEndpoint, error) {
+	input := &ec2.DescribeVpcEndpointsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+			filter.EC2.ClusterOwned(s.scope.Name()),
+			filter.EC2.ProviderRole(infrav1.VPCEndpointRoleTagValue),
+		},
+	}
+
+	endpoints := make(map[string]*ec2.VpcEndpoint)
+	err := s.scope.EC2.DescribeVpcEndpointsPages(input, func(page *ec2.DescribeVpcEndpointsOutput, lastPage bool) bool {
+		for _, endpoint := range page.VpcEndpoints {
+			// The API reports states in lower case, unlike the SDK enum values.
+			state := aws.StringValue(endpoint.State)
+			if strings.EqualFold(state, ec2.StateDeleting) || strings.EqualFold(state, ec2.StateDeleted) || strings.EqualFold(state, ec2.StateFailed) {
+				continue
+			}
+			endpoints[aws.StringValue(endpoint.ServiceName)] = endpoint
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe VPC endpoints of VPC %q", s.scope.VPC().ID)
+	}
+
+	return endpoints, nil
+}
+
+// vpcEndpointSubnetIDs returns a private subnet of each availability zone of the cluster, as interface
+// endpoints can only have a single subnet per availability zone.
+func (s *Service) vpcEndpointSubnetIDs() []string {
+	zones := map[string]bool{}
+	ids := []string{}
+	for _, sn := range s.scope.Subnets().FilterPrivate() {
+		if sn.ID == "" || zones[sn.AvailabilityZone] {
+			continue
+		}
+		zones[sn.AvailabilityZone] = true
+		ids = append(ids, sn.ID)
+	}
+	return ids
+}
+
+func (s *Service) vpcEndpointServiceName(service string) string {
+	return fmt.Sprintf("com.amazonaws.%s.%s", s.scope.Region(), service)
+}
+
+func (s *Service) getVPCEndpointTagParams(id, service string) infrav1.BuildParams {
+	name := fmt.Sprintf("%s-vpce-%s", s.scope.Name(), service)
+
+	return infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		ResourceID:  id,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(name),
+		Role:        aws.String(infrav1.VPCEndpointRoleTagValue),
+		Additional:  s.scope.AdditionalTags(),
+	}
+}
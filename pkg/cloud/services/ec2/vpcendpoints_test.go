@@ -0,0 +1,200 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+func TestReconcileVPCEndpoints(t *testing.T) {
+	describeVPCEndpoints := func(m *mock_ec2iface.MockEC2APIMockRecorder, endpoints ...*ec2.VpcEndpoint) {
+		m.DescribeVpcEndpointsPages(gomock.AssignableToTypeOf(&ec2.DescribeVpcEndpointsInput{}), gomock.Any()).
+			Do(func(_ *ec2.DescribeVpcEndpointsInput, fn func(*ec2.DescribeVpcEndpointsOutput, bool) bool) {
+				fn(&ec2.DescribeVpcEndpointsOutput{VpcEndpoints: endpoints}, true)
+			}).
+			Return(nil)
+	}
+
+	testCases := []struct {
+		name           string
+		sessionManager *infrav1.SessionManagerSpec
+		securityGroups map[infrav1.SecurityGroupRole]infrav1.SecurityGroup
+		expect         func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectErr      bool
+	}{
+		{
+			name: "endpoints are not enabled",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+			},
+		},
+		{
+			name:           "creates the missing endpoints in a private subnet per availability zone",
+			sessionManager: &infrav1.SessionManagerSpec{CreateVPCEndpoints: true},
+			securityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+				infrav1.SecurityGroupVPCEndpoint: {ID: "sg-vpce"},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeVPCEndpoints(m,
+					&ec2.VpcEndpoint{
+						VpcEndpointId: aws.String("vpce-1"),
+						ServiceName:   aws.String("com.amazonaws.us-east-1.ssm"),
+						State:         aws.String("available"),
+					},
+					&ec2.VpcEndpoint{
+						VpcEndpointId: aws.String("vpce-2"),
+						ServiceName:   aws.String("com.amazonaws.us-east-1.ec2messages"),
+						State:         aws.String("deleting"),
+					},
+				)
+				for i, service := range []string{"ssmmessages", "ec2messages"} {
+					m.CreateVpcEndpoint(gomock.Eq(&ec2.CreateVpcEndpointInput{
+						VpcEndpointType:   aws.String(ec2.VpcEndpointTypeInterface),
+						VpcId:             aws.String("vpc-1"),
+						ServiceName:       aws.String("com.amazonaws.us-east-1." + service),
+						SubnetIds:         aws.StringSlice([]string{"subnet-private-1a", "subnet-private-1b"}),
+						SecurityGroupIds:  aws.StringSlice([]string{"sg-vpce"}),
+						PrivateDnsEnabled: aws.Bool(true),
+					})).
+						Return(&ec2.CreateVpcEndpointOutput{
+							VpcEndpoint: &ec2.VpcEndpoint{VpcEndpointId: aws.String([]string{"vpce-3", "vpce-4"}[i])},
+						}, nil)
+					m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+						Return(nil, nil)
+				}
+			},
+		},
+		{
+			name:           "deletes an endpoint that can't be tagged",
+			sessionManager: &infrav1.SessionManagerSpec{CreateVPCEndpoints: true},
+			securityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+				infrav1.SecurityGroupVPCEndpoint: {ID: "sg-vpce"},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeVPCEndpoints(m)
+				m.CreateVpcEndpoint(gomock.AssignableToTypeOf(&ec2.CreateVpcEndpointInput{})).
+					Return(&ec2.CreateVpcEndpointOutput{
+						VpcEndpoint: &ec2.VpcEndpoint{VpcEndpointId: aws.String("vpce-1")},
+					}, nil)
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, awserr.New("UnauthorizedOperation", "not authorized to create tags", nil))
+				m.DeleteVpcEndpoints(gomock.Eq(&ec2.DeleteVpcEndpointsInput{
+					VpcEndpointIds: aws.StringSlice([]string{"vpce-1"}),
+				})).
+					Return(&ec2.DeleteVpcEndpointsOutput{}, nil)
+			},
+			expectErr: true,
+		},
+		{
+			name: "deletes the endpoints and their security group once disabled",
+			securityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+				infrav1.SecurityGroupVPCEndpoint: {ID: "sg-vpce"},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeVPCEndpoints(m,
+					&ec2.VpcEndpoint{
+						VpcEndpointId: aws.String("vpce-1"),
+						ServiceName:   aws.String("com.amazonaws.us-east-1.ssm"),
+						State:         aws.String("available"),
+					},
+				)
+				m.DeleteVpcEndpoints(gomock.Eq(&ec2.DeleteVpcEndpointsInput{
+					VpcEndpointIds: aws.StringSlice([]string{"vpce-1"}),
+				})).
+					Return(&ec2.DeleteVpcEndpointsOutput{}, nil)
+				describeVPCEndpoints(m)
+				m.DeleteSecurityGroup(gomock.Eq(&ec2.DeleteSecurityGroupInput{
+					GroupId: aws.String("sg-vpce"),
+				})).
+					Return(&ec2.DeleteSecurityGroupOutput{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						Region:         "us-east-1",
+						AccessMode:     infrav1.ClusterAccessModeSessionManager,
+						SessionManager: tc.sessionManager,
+						NetworkSpec: infrav1.NetworkSpec{
+							VPC: infrav1.VPCSpec{
+								ID: "vpc-1",
+								Tags: infrav1.Tags{
+									infrav1.ClusterTagKey("test-cluster"): string(infrav1.ResourceLifecycleOwned),
+								},
+							},
+							Subnets: infrav1.Subnets{
+								{ID: "subnet-private-1a", AvailabilityZone: "us-east-1a"},
+								{ID: "subnet-private-1a-2", AvailabilityZone: "us-east-1a"},
+								{ID: "subnet-private-1b", AvailabilityZone: "us-east-1b"},
+								{ID: "subnet-public-1a", AvailabilityZone: "us-east-1a", IsPublic: true},
+							},
+						},
+					},
+					Status: infrav1.AWSClusterStatus{
+						Network: infrav1.Network{
+							SecurityGroups: tc.securityGroups,
+						},
+					},
+				},
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			err = s.reconcileVPCEndpoints()
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			if _, ok := scope.SecurityGroups()[infrav1.SecurityGroupVPCEndpoint]; ok != (tc.sessionManager != nil) {
+				t.Fatalf("unexpected VPC endpoint security group in the cluster status: %v", scope.SecurityGroups())
+			}
+		})
+	}
+}
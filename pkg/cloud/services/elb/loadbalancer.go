@@ -208,6 +208,28 @@ func (s *Service) RegisterInstanceWithAPIServerELB(i *infrav1.Instance) error {
 	return nil
 }
 
+// DeregisterInstanceFromAPIServerELB deregisters an instance from the API server classic ELB.
+// Load balancers and instances that are already gone are ignored.
+func (s *Service) DeregisterInstanceFromAPIServerELB(i *infrav1.Instance) error {
+	name, err := GenerateELBName(s.scope.Name())
+	if err != nil {
+		return err
+	}
+
+	input := &elb.DeregisterInstancesFromLoadBalancerInput{
+		Instances:        []*elb.Instance{{InstanceId: aws.String(i.ID)}},
+		LoadBalancerName: aws.String(name),
+	}
+
+	_, err = s.scope.ELB.DeregisterInstancesFromLoadBalancer(input)
+	switch code, _ := awserrors.Code(err); {
+	case err == nil, IsNotFound(err), code == elb.ErrCodeInvalidEndPointException:
+		return nil
+	default:
+		return errors.Wrapf(err, "failed to deregister instance %q from load balancer %q", i.ID, name)
+	}
+}
+
 // GenerateELBName generates a formatted ELB name via either
 // concatenating the cluster name to the "-apiserver" suffix
 // or computing a hash for clusters with names above 32 characters.
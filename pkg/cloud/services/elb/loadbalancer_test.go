@@ -19,9 +19,15 @@ package elb
 import (
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb/mock_elbiface"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 )
 
@@ -127,3 +133,68 @@ func TestGetAPIServerClassicELBSpec_ControlPlaneLoadBalancer(t *testing.T) {
 	}
 
 }
+
+func TestDeregisterInstanceFromAPIServerELB(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name      string
+		err       error
+		expectErr bool
+	}{
+		{
+			name: "instance is deregistered",
+		},
+		{
+			name: "instance is not registered",
+			err:  awserr.New(elb.ErrCodeInvalidEndPointException, "not registered", nil),
+		},
+		{
+			name: "load balancer does not exist",
+			err:  awserr.New(elb.ErrCodeAccessPointNotFoundException, "does not exist", nil),
+		},
+		{
+			name:      "load balancer can't be reached",
+			err:       errors.New("connection error"),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			elbMock := mock_elbiface.NewMockELBAPI(mockCtrl)
+
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				AWSClients: scope.AWSClients{
+					ELB: elbMock,
+				},
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "foo",
+						Name:      "bar",
+					},
+				},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			elbMock.EXPECT().DeregisterInstancesFromLoadBalancer(gomock.Eq(&elb.DeregisterInstancesFromLoadBalancerInput{
+				Instances:        []*elb.Instance{{InstanceId: aws.String("i-1")}},
+				LoadBalancerName: aws.String("bar-apiserver"),
+			})).
+				Return(&elb.DeregisterInstancesFromLoadBalancerOutput{}, tc.err)
+
+			s := NewService(clusterScope)
+			err = s.DeregisterInstanceFromAPIServerELB(&infrav1.Instance{ID: "i-1"})
+			if tc.expectErr && err == nil {
+				t.Fatal("expected error but got none")
+			}
+			if !tc.expectErr && err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+		})
+	}
+}
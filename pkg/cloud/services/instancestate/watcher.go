@@ -0,0 +1,283 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancestate
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/version"
+)
+
+// InstanceEventType is the EventBridge detail type of an EC2 instance event.
+type InstanceEventType string
+
+var (
+	// InstanceStateChangeEvent is sent when the state of an EC2 instance changes.
+	InstanceStateChangeEvent = InstanceEventType("EC2 Instance State-change Notification")
+
+	// SpotInstanceInterruptionEvent is sent two minutes before EC2 interrupts a spot instance.
+	SpotInstanceInterruptionEvent = InstanceEventType("EC2 Spot Instance Interruption Warning")
+)
+
+const (
+	// receiveWaitTime is how long a receive call waits for messages to arrive in the queue.
+	receiveWaitTime = 20
+
+	// receiveRetryInterval is how long to wait before receiving messages again after a failure.
+	receiveRetryInterval = 10 * time.Second
+)
+
+// InstanceEvent is a notification about an EC2 instance.
+type InstanceEvent struct {
+	// Type is the type of the event.
+	Type InstanceEventType
+
+	// InstanceID is the ID of the instance the event is about.
+	InstanceID string
+
+	// State is the new state of the instance, for state-change events.
+	State string
+
+	// Action is the action EC2 takes on the instance, for spot interruption events.
+	Action string
+
+	// ClusterName is the name of the cluster the instance belongs to, from its tags.
+	ClusterName string
+
+	// Name is the name of the instance, from its Name tag.
+	Name string
+}
+
+// message is an EC2 event delivered to the queue by EventBridge.
+type message struct {
+	Source     string `json:"source"`
+	DetailType string `json:"detail-type"`
+	Detail     struct {
+		InstanceID     string `json:"instance-id"`
+		State          string `json:"state"`
+		InstanceAction string `json:"instance-action"`
+	} `json:"detail"`
+}
+
+// Watcher receives EC2 instance state-change and spot interruption events from an SQS queue
+// fed by EventBridge, and sends them to its Events channel.
+type Watcher struct {
+	sqs      sqsiface.SQSAPI
+	ec2      ec2iface.EC2API
+	queueURL string
+	events   chan InstanceEvent
+	logger   logr.Logger
+}
+
+// NewWatcher returns a watcher for the SQS queue with the given URL.
+func NewWatcher(queueURL string, logger logr.Logger) (*Watcher, error) {
+	region, err := regionFromQueueURL(queueURL)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := session.NewSession(aws.NewConfig().WithRegion(region))
+	if err != nil {
+		return nil, errors.Errorf("failed to create aws session: %v", err)
+	}
+
+	sqsClient := sqs.New(session)
+	sqsClient.Handlers.Build.PushFrontNamed(request.NamedHandler{
+		Name: "capa/user-agent",
+		Fn:   request.MakeAddToUserAgentHandler("aws.cluster.x-k8s.io", version.Get().String()),
+	})
+
+	ec2Client := ec2.New(session)
+	ec2Client.Handlers.Build.PushFrontNamed(request.NamedHandler{
+		Name: "capa/user-agent",
+		Fn:   request.MakeAddToUserAgentHandler("aws.cluster.x-k8s.io", version.Get().String()),
+	})
+
+	return newWatcher(sqsClient, ec2Client, queueURL, logger), nil
+}
+
+func newWatcher(sqsClient sqsiface.SQSAPI, ec2Client ec2iface.EC2API, queueURL string, logger logr.Logger) *Watcher {
+	return &Watcher{
+		sqs:      sqsClient,
+		ec2:      ec2Client,
+		queueURL: queueURL,
+		events:   make(chan InstanceEvent),
+		logger:   logger,
+	}
+}
+
+// Events returns the channel the instance events are sent to.
+func (w *Watcher) Events() <-chan InstanceEvent {
+	return w.events
+}
+
+// Start receives events from the queue until the stop channel is closed.
+// It implements manager.Runnable, so that only the leader receives events.
+func (w *Watcher) Start(stop <-chan struct{}) error {
+	w.logger.Info("Watching for EC2 instance events", "queue-url", w.queueURL)
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		if err := w.receive(stop); err != nil {
+			w.logger.Error(err, "Failed to receive EC2 instance events", "queue-url", w.queueURL)
+
+			select {
+			case <-stop:
+				return nil
+			case <-time.After(receiveRetryInterval):
+			}
+		}
+	}
+}
+
+// receive waits for messages in the queue, and sends the instance events they contain
+// to the Events channel before deleting them from the queue.
+func (w *Watcher) receive(stop <-chan struct{}) error {
+	out, err := w.sqs.ReceiveMessage(&sqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(w.queueURL),
+		MaxNumberOfMessages: aws.Int64(10),
+		WaitTimeSeconds:     aws.Int64(receiveWaitTime),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to receive messages from queue %q", w.queueURL)
+	}
+
+	for _, msg := range out.Messages {
+		event, err := parseInstanceEvent(aws.StringValue(msg.Body))
+		if err != nil {
+			// Messages that are not instance events would otherwise be received over and over again.
+			w.logger.V(2).Info("Discarding message that is not an EC2 instance event", "message-id", aws.StringValue(msg.MessageId), "reason", err.Error())
+		} else {
+			// Without its tags, the event can still be matched to the machine by its provider ID.
+			if err := w.addInstanceTags(event); err != nil {
+				w.logger.V(2).Info("Failed to get the tags of the EC2 instance", "instance-id", event.InstanceID, "reason", err.Error())
+			}
+
+			select {
+			case w.events <- *event:
+			case <-stop:
+				return nil
+			}
+		}
+
+		if _, err := w.sqs.DeleteMessage(&sqs.DeleteMessageInput{
+			QueueUrl:      aws.String(w.queueURL),
+			ReceiptHandle: msg.ReceiptHandle,
+		}); err != nil {
+			return errors.Wrapf(err, "failed to delete message %q from queue %q", aws.StringValue(msg.MessageId), w.queueURL)
+		}
+	}
+
+	return nil
+}
+
+// addInstanceTags sets the cluster and the name of the instance of the event from its tags, so that
+// the event can be matched to a machine which doesn't have the provider ID of the instance yet.
+func (w *Watcher) addInstanceTags(event *InstanceEvent) error {
+	out, err := w.ec2.DescribeInstances(&ec2.DescribeInstancesInput{
+		InstanceIds: aws.StringSlice([]string{event.InstanceID}),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to describe instance %q", event.InstanceID)
+	}
+
+	for _, res := range out.Reservations {
+		for _, instance := range res.Instances {
+			for _, tag := range instance.Tags {
+				key := aws.StringValue(tag.Key)
+				switch {
+				case key == "Name":
+					event.Name = aws.StringValue(tag.Value)
+				case strings.HasPrefix(key, infrav1.NameAWSProviderOwned):
+					event.ClusterName = strings.TrimPrefix(key, infrav1.NameAWSProviderOwned)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseInstanceEvent returns the instance event in the body of a message.
+func parseInstanceEvent(body string) (*InstanceEvent, error) {
+	var msg message
+	if err := json.Unmarshal([]byte(body), &msg); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal message")
+	}
+
+	if msg.Source != "aws.ec2" {
+		return nil, errors.Errorf("unexpected event source %q", msg.Source)
+	}
+	if msg.Detail.InstanceID == "" {
+		return nil, errors.New("event has no instance ID")
+	}
+
+	event := &InstanceEvent{
+		Type:       InstanceEventType(msg.DetailType),
+		InstanceID: msg.Detail.InstanceID,
+	}
+
+	switch event.Type {
+	case InstanceStateChangeEvent:
+		event.State = msg.Detail.State
+	case SpotInstanceInterruptionEvent:
+		event.Action = msg.Detail.InstanceAction
+	default:
+		return nil, errors.Errorf("unexpected event type %q", msg.DetailType)
+	}
+
+	return event, nil
+}
+
+// regionFromQueueURL returns the region of an SQS queue from its URL, which is either
+// https://sqs.<region>.amazonaws.com/<account>/<name> or https://<region>.queue.amazonaws.com/<account>/<name>.
+func regionFromQueueURL(queueURL string) (string, error) {
+	u, err := url.Parse(queueURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse queue URL %q", queueURL)
+	}
+
+	labels := strings.Split(u.Hostname(), ".")
+	if len(labels) >= 3 {
+		if labels[0] == "sqs" {
+			return labels[1], nil
+		}
+		if labels[1] == "queue" {
+			return labels[0], nil
+		}
+	}
+
+	return "", errors.Errorf("failed to find the region of queue %q", queueURL)
+}
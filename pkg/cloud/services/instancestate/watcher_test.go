@@ -0,0 +1,203 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancestate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"k8s.io/klog/klogr"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate/mock_sqsiface"
+)
+
+const testQueueURL = "https://sqs.us-east-1.amazonaws.com/123456789012/instance-events"
+
+func TestReceive(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	sqsMock := mock_sqsiface.NewMockSQSAPI(mockCtrl)
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+	w := newWatcher(sqsMock, ec2Mock, testQueueURL, klogr.New())
+
+	ec2Mock.EXPECT().DescribeInstances(gomock.Eq(&ec2.DescribeInstancesInput{
+		InstanceIds: aws.StringSlice([]string{"i-1"}),
+	})).
+		Return(&ec2.DescribeInstancesOutput{
+			Reservations: []*ec2.Reservation{{
+				Instances: []*ec2.Instance{{
+					InstanceId: aws.String("i-1"),
+					Tags: []*ec2.Tag{
+						{Key: aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"), Value: aws.String("owned")},
+						{Key: aws.String("Name"), Value: aws.String("test-machine")},
+					},
+				}},
+			}},
+		}, nil)
+	ec2Mock.EXPECT().DescribeInstances(gomock.Eq(&ec2.DescribeInstancesInput{
+		InstanceIds: aws.StringSlice([]string{"i-2"}),
+	})).
+		Return(nil, errors.New("UnauthorizedOperation"))
+
+	m := sqsMock.EXPECT()
+	m.ReceiveMessage(gomock.Eq(&sqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(testQueueURL),
+		MaxNumberOfMessages: aws.Int64(10),
+		WaitTimeSeconds:     aws.Int64(20),
+	})).
+		Return(&sqs.ReceiveMessageOutput{
+			Messages: []*sqs.Message{
+				{
+					MessageId:     aws.String("1"),
+					ReceiptHandle: aws.String("handle-1"),
+					Body:          aws.String(`{"source":"aws.ec2","detail-type":"EC2 Instance State-change Notification","detail":{"instance-id":"i-1","state":"stopped"}}`),
+				},
+				{
+					MessageId:     aws.String("2"),
+					ReceiptHandle: aws.String("handle-2"),
+					Body:          aws.String(`not an event`),
+				},
+				{
+					MessageId:     aws.String("3"),
+					ReceiptHandle: aws.String("handle-3"),
+					Body:          aws.String(`{"source":"aws.ec2","detail-type":"EC2 Spot Instance Interruption Warning","detail":{"instance-id":"i-2","instance-action":"terminate"}}`),
+				},
+			},
+		}, nil)
+	for _, handle := range []string{"handle-1", "handle-2", "handle-3"} {
+		m.DeleteMessage(gomock.Eq(&sqs.DeleteMessageInput{
+			QueueUrl:      aws.String(testQueueURL),
+			ReceiptHandle: aws.String(handle),
+		})).
+			Return(&sqs.DeleteMessageOutput{}, nil)
+	}
+
+	var events []InstanceEvent
+	done := make(chan struct{})
+	go func() {
+		for event := range w.Events() {
+			events = append(events, event)
+			if len(events) == 2 {
+				close(done)
+				return
+			}
+		}
+	}()
+
+	if err := w.receive(make(chan struct{})); err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+	<-done
+
+	expected := []InstanceEvent{
+		{Type: InstanceStateChangeEvent, InstanceID: "i-1", State: "stopped", ClusterName: "test-cluster", Name: "test-machine"},
+		{Type: SpotInstanceInterruptionEvent, InstanceID: "i-2", Action: "terminate"},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected events %v, got %v", expected, events)
+	}
+}
+
+func TestParseInstanceEvent(t *testing.T) {
+	testCases := []struct {
+		name      string
+		body      string
+		expected  *InstanceEvent
+		expectErr bool
+	}{
+		{
+			name:     "state-change notification",
+			body:     `{"source":"aws.ec2","detail-type":"EC2 Instance State-change Notification","detail":{"instance-id":"i-1","state":"terminated"}}`,
+			expected: &InstanceEvent{Type: InstanceStateChangeEvent, InstanceID: "i-1", State: "terminated"},
+		},
+		{
+			name:     "spot interruption warning",
+			body:     `{"source":"aws.ec2","detail-type":"EC2 Spot Instance Interruption Warning","detail":{"instance-id":"i-1","instance-action":"stop"}}`,
+			expected: &InstanceEvent{Type: SpotInstanceInterruptionEvent, InstanceID: "i-1", Action: "stop"},
+		},
+		{
+			name:      "event from another source",
+			body:      `{"source":"aws.autoscaling","detail-type":"EC2 Instance Launch Successful","detail":{"EC2InstanceId":"i-1"}}`,
+			expectErr: true,
+		},
+		{
+			name:      "unsupported EC2 event",
+			body:      `{"source":"aws.ec2","detail-type":"EBS Volume Notification","detail":{}}`,
+			expectErr: true,
+		},
+		{
+			name:      "not JSON",
+			body:      `hello`,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			event, err := parseInstanceEvent(tc.body)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(event, tc.expected) {
+				t.Fatalf("expected event %v, got %v", tc.expected, event)
+			}
+		})
+	}
+}
+
+func TestRegionFromQueueURL(t *testing.T) {
+	testCases := []struct {
+		url       string
+		expected  string
+		expectErr bool
+	}{
+		{url: "https://sqs.eu-west-1.amazonaws.com/123456789012/events", expected: "eu-west-1"},
+		{url: "https://sqs.cn-north-1.amazonaws.com.cn/123456789012/events", expected: "cn-north-1"},
+		{url: "https://us-west-2.queue.amazonaws.com/123456789012/events", expected: "us-west-2"},
+		{url: "https://example.com/events", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.url, func(t *testing.T) {
+			region, err := regionFromQueueURL(tc.url)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			if region != tc.expected {
+				t.Fatalf("expected region %q, got %q", tc.expected, region)
+			}
+		})
+	}
+}
@@ -30,17 +30,52 @@ type EC2MachineInterface interface {
 	GetRunningInstanceByTags(scope *scope.MachineScope) (*infrav1.Instance, error)
 
 	GetCoreSecurityGroups(machine *scope.MachineScope) ([]string, error)
+	GetAdditionalSecurityGroupsIDs(securityGroups []infrav1.AWSResourceReference) ([]string, error)
 	GetInstanceSecurityGroups(instanceID string) (map[string][]string, error)
 	UpdateInstanceSecurityGroups(id string, securityGroups []string) error
+	UpdateInstanceMetadataOptions(instance *infrav1.Instance, desired *infrav1.InstanceMetadataOptions) (bool, error)
 	UpdateResourceTags(resourceID *string, create map[string]string, remove map[string]string) error
 
 	TerminateInstanceAndWait(instanceID string) error
+	DisableTerminationProtection(instanceID string) error
+	StopInstance(instanceID string) error
+	StartInstance(instanceID string) error
+	GetInstanceScheduledEvents(instanceID string) ([]infrav1.InstanceScheduledEvent, error)
+	ModifyInstanceType(instanceID string, instanceType string) error
+	GetRootVolumeSize(instanceID string) (int64, error)
+	ModifyRootVolumeSize(instanceID string, size int64) error
+	CancelSpotInstanceRequest(requestID string) error
+	DisownInstance(scope *scope.MachineScope, instanceID string) error
 	DetachSecurityGroupsFromNetworkInterface(groups []string, interfaceID string) error
+	DetachCoreSecurityGroupsFromInstance(scope *scope.MachineScope, instanceID string) error
+
+	ReconcileElasticIP(scope *scope.MachineScope, instance *infrav1.Instance) error
+	ReleaseElasticIP(scope *scope.MachineScope) error
+	DeleteManagedNetworkInterfaces(scope *scope.MachineScope) error
+
+	DiscoverLaunchTemplateAMI(scope *scope.MachinePoolScope) (*string, error)
+	GetLaunchTemplate(name string) (*infrav1.AWSLaunchTemplate, string, error)
+	GetLaunchTemplateID(name string) (string, error)
+	CreateLaunchTemplate(scope *scope.MachinePoolScope, imageID *string, userData []byte) (string, error)
+	CreateLaunchTemplateVersion(scope *scope.MachinePoolScope, imageID *string, userData []byte) error
+	DeleteLaunchTemplate(id string) error
+	LaunchTemplateNeedsUpdate(scope *scope.MachinePoolScope, existing *infrav1.AWSLaunchTemplate, existingUserData string, imageID *string, userData []byte) (bool, error)
+}
+
+// ASGInterface encapsulates the methods exposed to the machine pool
+// actuator
+type ASGInterface interface {
+	ASGIfExists(name *string) (*infrav1.AutoScalingGroup, error)
+	GetASGByName(scope *scope.MachinePoolScope) (*infrav1.AutoScalingGroup, error)
+	CreateASG(scope *scope.MachinePoolScope) (*infrav1.AutoScalingGroup, error)
+	UpdateASG(scope *scope.MachinePoolScope) error
+	DeleteASG(name string) error
 }
 
-// SecretsManagerInterface encapsulated the methods exposed to the
-// machine actuator
-type SecretsManagerInterface interface {
+// SecretInterface encapsulated the methods exposed to the
+// machine actuator by the secure secrets backends
+type SecretInterface interface {
 	Delete(m *scope.MachineScope) error
 	Create(m *scope.MachineScope, data []byte) (string, int32, error)
+	UserData(secretPrefix string, chunks int32, region string, format infrav1.BootstrapFormat) ([]byte, error)
 }
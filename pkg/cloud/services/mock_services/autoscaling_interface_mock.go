@@ -0,0 +1,124 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services (interfaces: ASGInterface)
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	v1alpha3 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	scope "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+)
+
+// MockASGInterface is a mock of ASGInterface interface
+type MockASGInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockASGInterfaceMockRecorder
+}
+
+// MockASGInterfaceMockRecorder is the mock recorder for MockASGInterface
+type MockASGInterfaceMockRecorder struct {
+	mock *MockASGInterface
+}
+
+// NewMockASGInterface creates a new mock instance
+func NewMockASGInterface(ctrl *gomock.Controller) *MockASGInterface {
+	mock := &MockASGInterface{ctrl: ctrl}
+	mock.recorder = &MockASGInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockASGInterface) EXPECT() *MockASGInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ASGIfExists mocks base method
+func (m *MockASGInterface) ASGIfExists(arg0 *string) (*v1alpha3.AutoScalingGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ASGIfExists", arg0)
+	ret0, _ := ret[0].(*v1alpha3.AutoScalingGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ASGIfExists indicates an expected call of ASGIfExists
+func (mr *MockASGInterfaceMockRecorder) ASGIfExists(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ASGIfExists", reflect.TypeOf((*MockASGInterface)(nil).ASGIfExists), arg0)
+}
+
+// CreateASG mocks base method
+func (m *MockASGInterface) CreateASG(arg0 *scope.MachinePoolScope) (*v1alpha3.AutoScalingGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateASG", arg0)
+	ret0, _ := ret[0].(*v1alpha3.AutoScalingGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateASG indicates an expected call of CreateASG
+func (mr *MockASGInterfaceMockRecorder) CreateASG(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateASG", reflect.TypeOf((*MockASGInterface)(nil).CreateASG), arg0)
+}
+
+// DeleteASG mocks base method
+func (m *MockASGInterface) DeleteASG(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteASG", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteASG indicates an expected call of DeleteASG
+func (mr *MockASGInterfaceMockRecorder) DeleteASG(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteASG", reflect.TypeOf((*MockASGInterface)(nil).DeleteASG), arg0)
+}
+
+// GetASGByName mocks base method
+func (m *MockASGInterface) GetASGByName(arg0 *scope.MachinePoolScope) (*v1alpha3.AutoScalingGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetASGByName", arg0)
+	ret0, _ := ret[0].(*v1alpha3.AutoScalingGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetASGByName indicates an expected call of GetASGByName
+func (mr *MockASGInterfaceMockRecorder) GetASGByName(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetASGByName", reflect.TypeOf((*MockASGInterface)(nil).GetASGByName), arg0)
+}
+
+// UpdateASG mocks base method
+func (m *MockASGInterface) UpdateASG(arg0 *scope.MachinePoolScope) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateASG", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateASG indicates an expected call of UpdateASG
+func (mr *MockASGInterfaceMockRecorder) UpdateASG(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateASG", reflect.TypeOf((*MockASGInterface)(nil).UpdateASG), arg0)
+}
@@ -50,6 +50,20 @@ func (m *MockEC2MachineInterface) EXPECT() *MockEC2MachineInterfaceMockRecorder
 	return m.recorder
 }
 
+// CancelSpotInstanceRequest mocks base method
+func (m *MockEC2MachineInterface) CancelSpotInstanceRequest(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSpotInstanceRequest", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelSpotInstanceRequest indicates an expected call of CancelSpotInstanceRequest
+func (mr *MockEC2MachineInterfaceMockRecorder) CancelSpotInstanceRequest(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSpotInstanceRequest", reflect.TypeOf((*MockEC2MachineInterface)(nil).CancelSpotInstanceRequest), arg0)
+}
+
 // CreateInstance mocks base method
 func (m *MockEC2MachineInterface) CreateInstance(arg0 *scope.MachineScope, arg1 []byte) (*v1alpha3.Instance, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +79,77 @@ func (mr *MockEC2MachineInterfaceMockRecorder) CreateInstance(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInstance", reflect.TypeOf((*MockEC2MachineInterface)(nil).CreateInstance), arg0, arg1)
 }
 
+// CreateLaunchTemplate mocks base method
+func (m *MockEC2MachineInterface) CreateLaunchTemplate(arg0 *scope.MachinePoolScope, arg1 *string, arg2 []byte) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLaunchTemplate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLaunchTemplate indicates an expected call of CreateLaunchTemplate
+func (mr *MockEC2MachineInterfaceMockRecorder) CreateLaunchTemplate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLaunchTemplate", reflect.TypeOf((*MockEC2MachineInterface)(nil).CreateLaunchTemplate), arg0, arg1, arg2)
+}
+
+// CreateLaunchTemplateVersion mocks base method
+func (m *MockEC2MachineInterface) CreateLaunchTemplateVersion(arg0 *scope.MachinePoolScope, arg1 *string, arg2 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLaunchTemplateVersion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLaunchTemplateVersion indicates an expected call of CreateLaunchTemplateVersion
+func (mr *MockEC2MachineInterfaceMockRecorder) CreateLaunchTemplateVersion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLaunchTemplateVersion", reflect.TypeOf((*MockEC2MachineInterface)(nil).CreateLaunchTemplateVersion), arg0, arg1, arg2)
+}
+
+// DeleteLaunchTemplate mocks base method
+func (m *MockEC2MachineInterface) DeleteLaunchTemplate(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLaunchTemplate", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLaunchTemplate indicates an expected call of DeleteLaunchTemplate
+func (mr *MockEC2MachineInterfaceMockRecorder) DeleteLaunchTemplate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLaunchTemplate", reflect.TypeOf((*MockEC2MachineInterface)(nil).DeleteLaunchTemplate), arg0)
+}
+
+// DeleteManagedNetworkInterfaces mocks base method
+func (m *MockEC2MachineInterface) DeleteManagedNetworkInterfaces(arg0 *scope.MachineScope) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteManagedNetworkInterfaces", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteManagedNetworkInterfaces indicates an expected call of DeleteManagedNetworkInterfaces
+func (mr *MockEC2MachineInterfaceMockRecorder) DeleteManagedNetworkInterfaces(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteManagedNetworkInterfaces", reflect.TypeOf((*MockEC2MachineInterface)(nil).DeleteManagedNetworkInterfaces), arg0)
+}
+
+// DetachCoreSecurityGroupsFromInstance mocks base method
+func (m *MockEC2MachineInterface) DetachCoreSecurityGroupsFromInstance(arg0 *scope.MachineScope, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachCoreSecurityGroupsFromInstance", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachCoreSecurityGroupsFromInstance indicates an expected call of DetachCoreSecurityGroupsFromInstance
+func (mr *MockEC2MachineInterfaceMockRecorder) DetachCoreSecurityGroupsFromInstance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachCoreSecurityGroupsFromInstance", reflect.TypeOf((*MockEC2MachineInterface)(nil).DetachCoreSecurityGroupsFromInstance), arg0, arg1)
+}
+
 // DetachSecurityGroupsFromNetworkInterface mocks base method
 func (m *MockEC2MachineInterface) DetachSecurityGroupsFromNetworkInterface(arg0 []string, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -79,6 +164,64 @@ func (mr *MockEC2MachineInterfaceMockRecorder) DetachSecurityGroupsFromNetworkIn
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachSecurityGroupsFromNetworkInterface", reflect.TypeOf((*MockEC2MachineInterface)(nil).DetachSecurityGroupsFromNetworkInterface), arg0, arg1)
 }
 
+// DisableTerminationProtection mocks base method
+func (m *MockEC2MachineInterface) DisableTerminationProtection(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTerminationProtection", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTerminationProtection indicates an expected call of DisableTerminationProtection
+func (mr *MockEC2MachineInterfaceMockRecorder) DisableTerminationProtection(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTerminationProtection", reflect.TypeOf((*MockEC2MachineInterface)(nil).DisableTerminationProtection), arg0)
+}
+
+// DiscoverLaunchTemplateAMI mocks base method
+func (m *MockEC2MachineInterface) DiscoverLaunchTemplateAMI(arg0 *scope.MachinePoolScope) (*string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiscoverLaunchTemplateAMI", arg0)
+	ret0, _ := ret[0].(*string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiscoverLaunchTemplateAMI indicates an expected call of DiscoverLaunchTemplateAMI
+func (mr *MockEC2MachineInterfaceMockRecorder) DiscoverLaunchTemplateAMI(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscoverLaunchTemplateAMI", reflect.TypeOf((*MockEC2MachineInterface)(nil).DiscoverLaunchTemplateAMI), arg0)
+}
+
+// DisownInstance mocks base method
+func (m *MockEC2MachineInterface) DisownInstance(arg0 *scope.MachineScope, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisownInstance", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisownInstance indicates an expected call of DisownInstance
+func (mr *MockEC2MachineInterfaceMockRecorder) DisownInstance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisownInstance", reflect.TypeOf((*MockEC2MachineInterface)(nil).DisownInstance), arg0, arg1)
+}
+
+// GetAdditionalSecurityGroupsIDs mocks base method
+func (m *MockEC2MachineInterface) GetAdditionalSecurityGroupsIDs(arg0 []v1alpha3.AWSResourceReference) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdditionalSecurityGroupsIDs", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdditionalSecurityGroupsIDs indicates an expected call of GetAdditionalSecurityGroupsIDs
+func (mr *MockEC2MachineInterfaceMockRecorder) GetAdditionalSecurityGroupsIDs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdditionalSecurityGroupsIDs", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetAdditionalSecurityGroupsIDs), arg0)
+}
+
 // GetCoreSecurityGroups mocks base method
 func (m *MockEC2MachineInterface) GetCoreSecurityGroups(arg0 *scope.MachineScope) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +237,21 @@ func (mr *MockEC2MachineInterfaceMockRecorder) GetCoreSecurityGroups(arg0 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoreSecurityGroups", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetCoreSecurityGroups), arg0)
 }
 
+// GetInstanceScheduledEvents mocks base method
+func (m *MockEC2MachineInterface) GetInstanceScheduledEvents(arg0 string) ([]v1alpha3.InstanceScheduledEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstanceScheduledEvents", arg0)
+	ret0, _ := ret[0].([]v1alpha3.InstanceScheduledEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstanceScheduledEvents indicates an expected call of GetInstanceScheduledEvents
+func (mr *MockEC2MachineInterfaceMockRecorder) GetInstanceScheduledEvents(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceScheduledEvents", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetInstanceScheduledEvents), arg0)
+}
+
 // GetInstanceSecurityGroups mocks base method
 func (m *MockEC2MachineInterface) GetInstanceSecurityGroups(arg0 string) (map[string][]string, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +267,52 @@ func (mr *MockEC2MachineInterfaceMockRecorder) GetInstanceSecurityGroups(arg0 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceSecurityGroups", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetInstanceSecurityGroups), arg0)
 }
 
+// GetLaunchTemplate mocks base method
+func (m *MockEC2MachineInterface) GetLaunchTemplate(arg0 string) (*v1alpha3.AWSLaunchTemplate, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLaunchTemplate", arg0)
+	ret0, _ := ret[0].(*v1alpha3.AWSLaunchTemplate)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetLaunchTemplate indicates an expected call of GetLaunchTemplate
+func (mr *MockEC2MachineInterfaceMockRecorder) GetLaunchTemplate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLaunchTemplate", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetLaunchTemplate), arg0)
+}
+
+// GetLaunchTemplateID mocks base method
+func (m *MockEC2MachineInterface) GetLaunchTemplateID(arg0 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLaunchTemplateID", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLaunchTemplateID indicates an expected call of GetLaunchTemplateID
+func (mr *MockEC2MachineInterfaceMockRecorder) GetLaunchTemplateID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLaunchTemplateID", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetLaunchTemplateID), arg0)
+}
+
+// GetRootVolumeSize mocks base method
+func (m *MockEC2MachineInterface) GetRootVolumeSize(arg0 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRootVolumeSize", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRootVolumeSize indicates an expected call of GetRootVolumeSize
+func (mr *MockEC2MachineInterfaceMockRecorder) GetRootVolumeSize(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRootVolumeSize", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetRootVolumeSize), arg0)
+}
+
 // GetRunningInstanceByTags mocks base method
 func (m *MockEC2MachineInterface) GetRunningInstanceByTags(arg0 *scope.MachineScope) (*v1alpha3.Instance, error) {
 	m.ctrl.T.Helper()
@@ -139,6 +343,105 @@ func (mr *MockEC2MachineInterfaceMockRecorder) InstanceIfExists(arg0 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceIfExists", reflect.TypeOf((*MockEC2MachineInterface)(nil).InstanceIfExists), arg0)
 }
 
+// LaunchTemplateNeedsUpdate mocks base method
+func (m *MockEC2MachineInterface) LaunchTemplateNeedsUpdate(arg0 *scope.MachinePoolScope, arg1 *v1alpha3.AWSLaunchTemplate, arg2 string, arg3 *string, arg4 []byte) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LaunchTemplateNeedsUpdate", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LaunchTemplateNeedsUpdate indicates an expected call of LaunchTemplateNeedsUpdate
+func (mr *MockEC2MachineInterfaceMockRecorder) LaunchTemplateNeedsUpdate(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LaunchTemplateNeedsUpdate", reflect.TypeOf((*MockEC2MachineInterface)(nil).LaunchTemplateNeedsUpdate), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ModifyInstanceType mocks base method
+func (m *MockEC2MachineInterface) ModifyInstanceType(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModifyInstanceType", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModifyInstanceType indicates an expected call of ModifyInstanceType
+func (mr *MockEC2MachineInterfaceMockRecorder) ModifyInstanceType(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyInstanceType", reflect.TypeOf((*MockEC2MachineInterface)(nil).ModifyInstanceType), arg0, arg1)
+}
+
+// ModifyRootVolumeSize mocks base method
+func (m *MockEC2MachineInterface) ModifyRootVolumeSize(arg0 string, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModifyRootVolumeSize", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModifyRootVolumeSize indicates an expected call of ModifyRootVolumeSize
+func (mr *MockEC2MachineInterfaceMockRecorder) ModifyRootVolumeSize(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyRootVolumeSize", reflect.TypeOf((*MockEC2MachineInterface)(nil).ModifyRootVolumeSize), arg0, arg1)
+}
+
+// ReconcileElasticIP mocks base method
+func (m *MockEC2MachineInterface) ReconcileElasticIP(arg0 *scope.MachineScope, arg1 *v1alpha3.Instance) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileElasticIP", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReconcileElasticIP indicates an expected call of ReconcileElasticIP
+func (mr *MockEC2MachineInterfaceMockRecorder) ReconcileElasticIP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileElasticIP", reflect.TypeOf((*MockEC2MachineInterface)(nil).ReconcileElasticIP), arg0, arg1)
+}
+
+// ReleaseElasticIP mocks base method
+func (m *MockEC2MachineInterface) ReleaseElasticIP(arg0 *scope.MachineScope) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseElasticIP", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseElasticIP indicates an expected call of ReleaseElasticIP
+func (mr *MockEC2MachineInterfaceMockRecorder) ReleaseElasticIP(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseElasticIP", reflect.TypeOf((*MockEC2MachineInterface)(nil).ReleaseElasticIP), arg0)
+}
+
+// StartInstance mocks base method
+func (m *MockEC2MachineInterface) StartInstance(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartInstance", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartInstance indicates an expected call of StartInstance
+func (mr *MockEC2MachineInterfaceMockRecorder) StartInstance(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartInstance", reflect.TypeOf((*MockEC2MachineInterface)(nil).StartInstance), arg0)
+}
+
+// StopInstance mocks base method
+func (m *MockEC2MachineInterface) StopInstance(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopInstance", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StopInstance indicates an expected call of StopInstance
+func (mr *MockEC2MachineInterfaceMockRecorder) StopInstance(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopInstance", reflect.TypeOf((*MockEC2MachineInterface)(nil).StopInstance), arg0)
+}
+
 // TerminateInstance mocks base method
 func (m *MockEC2MachineInterface) TerminateInstance(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -167,6 +470,21 @@ func (mr *MockEC2MachineInterfaceMockRecorder) TerminateInstanceAndWait(arg0 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TerminateInstanceAndWait", reflect.TypeOf((*MockEC2MachineInterface)(nil).TerminateInstanceAndWait), arg0)
 }
 
+// UpdateInstanceMetadataOptions mocks base method
+func (m *MockEC2MachineInterface) UpdateInstanceMetadataOptions(arg0 *v1alpha3.Instance, arg1 *v1alpha3.InstanceMetadataOptions) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInstanceMetadataOptions", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateInstanceMetadataOptions indicates an expected call of UpdateInstanceMetadataOptions
+func (mr *MockEC2MachineInterfaceMockRecorder) UpdateInstanceMetadataOptions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInstanceMetadataOptions", reflect.TypeOf((*MockEC2MachineInterface)(nil).UpdateInstanceMetadataOptions), arg0, arg1)
+}
+
 // UpdateInstanceSecurityGroups mocks base method
 func (m *MockEC2MachineInterface) UpdateInstanceSecurityGroups(arg0 string, arg1 []string) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,40 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"bytes"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestGenerateCloudInitMIMEDocument(t *testing.T) {
+	doc, err := GenerateCloudInitMIMEDocument("bucket", "aws.cluster.x-k8s.io/test-cluster/object", "eu-west-1")
+	if err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+
+	_, err = mail.ReadMessage(bytes.NewBuffer(doc))
+	if err != nil {
+		t.Fatalf("Cannot parse MIME doc: %+v\n%s", err, string(doc))
+	}
+
+	if !strings.Contains(string(doc), `OBJECT="s3://bucket/aws.cluster.x-k8s.io/test-cluster/object"`) {
+		t.Fatalf("Expected the object URL in the script:\n%s", string(doc))
+	}
+}
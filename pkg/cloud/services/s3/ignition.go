@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// ignitionVersion is the version of the Ignition config specification of the generated configs,
+	// the first one supporting compressed remote configs.
+	ignitionVersion = "3.1.0"
+)
+
+type ignitionConfig struct {
+	Ignition ignitionMetadata `json:"ignition"`
+}
+
+type ignitionMetadata struct {
+	Version string                   `json:"version"`
+	Config  ignitionConfigReferences `json:"config"`
+}
+
+type ignitionConfigReferences struct {
+	Replace ignitionResource `json:"replace"`
+}
+
+type ignitionResource struct {
+	Source      string `json:"source"`
+	Compression string `json:"compression,omitempty"`
+}
+
+// GenerateIgnitionConfig creates an Ignition config replaced by the gzip-compressed config stored
+// in the given S3 object, which Ignition downloads using the instance profile permissions.
+func GenerateIgnitionConfig(bucket string, key string) ([]byte, error) {
+	config := ignitionConfig{
+		Ignition: ignitionMetadata{
+			Version: ignitionVersion,
+			Config: ignitionConfigReferences{
+				Replace: ignitionResource{
+					Source:      fmt.Sprintf("s3://%s/%s", bucket, key),
+					Compression: "gzip",
+				},
+			},
+		},
+	}
+
+	return json.Marshal(config)
+}
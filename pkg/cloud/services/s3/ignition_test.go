@@ -0,0 +1,33 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"testing"
+)
+
+func TestGenerateIgnitionConfig(t *testing.T) {
+	config, err := GenerateIgnitionConfig("bucket", "aws.cluster.x-k8s.io/test-cluster/object")
+	if err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+
+	expected := `{"ignition":{"version":"3.1.0","config":{"replace":{"source":"s3://bucket/aws.cluster.x-k8s.io/test-cluster/object","compression":"gzip"}}}}`
+	if string(config) != expected {
+		t.Fatalf("expected config %s, got %s", expected, string(config))
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"bytes"
+	"path"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+)
+
+const (
+	entryPrefix = "aws.cluster.x-k8s.io"
+)
+
+// Create stores data as a single object in the S3 bucket of the cluster for a given machine, encrypted
+// with AWS KMS. The key of the object is returned, along with a chunk count of 1.
+func (s *Service) Create(m *scope.MachineScope, data []byte) (string, int32, error) {
+	bucket, err := s.bucket()
+	if err != nil {
+		return "", 0, err
+	}
+
+	// Build the key, under a prefix specific to the cluster as the bucket can be shared.
+	key := m.GetSecretPrefix()
+	if key == "" {
+		key = path.Join(entryPrefix, s.scope.Name(), string(uuid.NewUUID()))
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket:               aws.String(bucket.Name),
+		Key:                  aws.String(key),
+		Body:                 bytes.NewReader(data),
+		ServerSideEncryption: aws.String(s3.ServerSideEncryptionAwsKms),
+	}
+	if bucket.KMSKeyID != "" {
+		input.SSEKMSKeyId = aws.String(bucket.KMSKeyID)
+	}
+
+	if _, err := s.scope.S3.PutObject(input); err != nil {
+		return key, 0, errors.Wrapf(err, "failed to put object %q in bucket %q", key, bucket.Name)
+	}
+
+	return key, 1, nil
+}
+
+// Delete the object belonging to a machine from the S3 bucket of the cluster
+func (s *Service) Delete(m *scope.MachineScope) error {
+	bucket, err := s.bucket()
+	if err != nil {
+		return err
+	}
+
+	// Deleting an object that does not exist succeeds, only a missing bucket has to be ignored.
+	_, err = s.scope.S3.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(bucket.Name),
+		Key:    aws.String(m.GetSecretPrefix()),
+	})
+	if code, ok := awserrors.Code(err); ok && code == s3.ErrCodeNoSuchBucket {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete object %q from bucket %q", m.GetSecretPrefix(), bucket.Name)
+	}
+
+	return nil
+}
+
+// UserData returns the userdata fetching the object with the given key from the S3 bucket of the cluster.
+func (s *Service) UserData(key string, chunks int32, region string, format infrav1.BootstrapFormat) ([]byte, error) {
+	bucket, err := s.bucket()
+	if err != nil {
+		return nil, err
+	}
+
+	if format == infrav1.BootstrapFormatIgnition {
+		return GenerateIgnitionConfig(bucket.Name, key)
+	}
+	return GenerateCloudInitMIMEDocument(bucket.Name, key, region)
+}
+
+func (s *Service) bucket() (*infrav1.S3Bucket, error) {
+	if s.scope.AWSCluster.Spec.S3Bucket == nil || s.scope.AWSCluster.Spec.S3Bucket.Name == "" {
+		return nil, errors.New("the s3 secure secrets backend requires spec.s3Bucket to be set on the AWSCluster")
+	}
+	return s.scope.AWSCluster.Spec.S3Bucket, nil
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/s3/mock_s3iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCreate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		bucket    *infrav1.S3Bucket
+		expect    func(m *mock_s3iface.MockS3APIMockRecorder)
+		expectErr bool
+	}{
+		{
+			name:   "stores the data encrypted with the AWS managed key",
+			bucket: &infrav1.S3Bucket{Name: "bucket"},
+			expect: func(m *mock_s3iface.MockS3APIMockRecorder) {
+				m.PutObject(gomock.AssignableToTypeOf(&s3.PutObjectInput{})).
+					DoAndReturn(func(input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+						if aws.StringValue(input.Bucket) != "bucket" {
+							t.Fatalf("expected bucket %q, got %q", "bucket", aws.StringValue(input.Bucket))
+						}
+						if !strings.HasPrefix(aws.StringValue(input.Key), "aws.cluster.x-k8s.io/test-cluster/") {
+							t.Fatalf("expected the key to be prefixed with the cluster name, got %q", aws.StringValue(input.Key))
+						}
+						if aws.StringValue(input.ServerSideEncryption) != s3.ServerSideEncryptionAwsKms || input.SSEKMSKeyId != nil {
+							t.Fatalf("expected SSE-KMS with the default key, got %q with key %v", aws.StringValue(input.ServerSideEncryption), input.SSEKMSKeyId)
+						}
+						return &s3.PutObjectOutput{}, nil
+					})
+			},
+		},
+		{
+			name:   "stores the data encrypted with the given key",
+			bucket: &infrav1.S3Bucket{Name: "bucket", KMSKeyID: "key"},
+			expect: func(m *mock_s3iface.MockS3APIMockRecorder) {
+				m.PutObject(gomock.AssignableToTypeOf(&s3.PutObjectInput{})).
+					DoAndReturn(func(input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+						if aws.StringValue(input.SSEKMSKeyId) != "key" {
+							t.Fatalf("expected KMS key %q, got %v", "key", input.SSEKMSKeyId)
+						}
+						return &s3.PutObjectOutput{}, nil
+					})
+			},
+		},
+		{
+			name:      "bucket is not configured",
+			expect:    func(m *mock_s3iface.MockS3APIMockRecorder) {},
+			expectErr: true,
+		},
+		{
+			name:   "object cannot be stored",
+			bucket: &infrav1.S3Bucket{Name: "bucket"},
+			expect: func(m *mock_s3iface.MockS3APIMockRecorder) {
+				m.PutObject(gomock.AssignableToTypeOf(&s3.PutObjectInput{})).
+					Return(nil, awserr.New("AccessDenied", "denied", nil))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			s3Mock := mock_s3iface.NewMockS3API(mockCtrl)
+			clusterScope, machineScope := newTestScopes(t, s3Mock, tc.bucket)
+
+			tc.expect(s3Mock.EXPECT())
+
+			s := NewService(clusterScope)
+			key, count, err := s.Create(machineScope, []byte("data"))
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			if key == "" || count != 1 {
+				t.Fatalf("expected a key and a count of 1, got %q and %d", key, count)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	testCases := []struct {
+		name      string
+		expect    func(m *mock_s3iface.MockS3APIMockRecorder)
+		expectErr bool
+	}{
+		{
+			name: "deletes the object",
+			expect: func(m *mock_s3iface.MockS3APIMockRecorder) {
+				m.DeleteObject(gomock.Eq(&s3.DeleteObjectInput{
+					Bucket: aws.String("bucket"),
+					Key:    aws.String("object"),
+				})).
+					Return(&s3.DeleteObjectOutput{}, nil)
+			},
+		},
+		{
+			name: "bucket does not exist",
+			expect: func(m *mock_s3iface.MockS3APIMockRecorder) {
+				m.DeleteObject(gomock.AssignableToTypeOf(&s3.DeleteObjectInput{})).
+					Return(nil, awserr.New(s3.ErrCodeNoSuchBucket, "not found", nil))
+			},
+		},
+		{
+			name: "object cannot be deleted",
+			expect: func(m *mock_s3iface.MockS3APIMockRecorder) {
+				m.DeleteObject(gomock.AssignableToTypeOf(&s3.DeleteObjectInput{})).
+					Return(nil, awserr.New("AccessDenied", "denied", nil))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			s3Mock := mock_s3iface.NewMockS3API(mockCtrl)
+			clusterScope, machineScope := newTestScopes(t, s3Mock, &infrav1.S3Bucket{Name: "bucket"})
+			machineScope.SetSecretPrefix("object")
+			machineScope.SetSecretCount(1)
+
+			tc.expect(s3Mock.EXPECT())
+
+			s := NewService(clusterScope)
+			err := s.Delete(machineScope)
+			if tc.expectErr && err == nil {
+				t.Fatal("expected an error")
+			}
+			if !tc.expectErr && err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// newTestScopes returns the cluster and machine scopes of a cluster using the given bucket.
+func newTestScopes(t *testing.T, s3Mock *mock_s3iface.MockS3API, bucket *infrav1.S3Bucket) (*scope.ClusterScope, *scope.MachineScope) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-machine"},
+	}
+	awsCluster := &infrav1.AWSCluster{
+		Spec: infrav1.AWSClusterSpec{
+			S3Bucket: bucket,
+		},
+	}
+	awsMachine := &infrav1.AWSMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-machine"},
+		Spec: infrav1.AWSMachineSpec{
+			CloudInit: infrav1.CloudInit{
+				SecureSecretsBackend: infrav1.SecretBackendS3,
+			},
+		},
+	}
+
+	client := fake.NewFakeClient()
+
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Client: client,
+		AWSClients: scope.AWSClients{
+			S3: s3Mock,
+		},
+		Cluster:    cluster,
+		AWSCluster: awsCluster,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client: client,
+		AWSClients: scope.AWSClients{
+			S3: s3Mock,
+		},
+		Cluster:    cluster,
+		Machine:    machine,
+		AWSMachine: awsMachine,
+		AWSCluster: awsCluster,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	return clusterScope, machineScope
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/pkg/errors"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
@@ -122,3 +123,12 @@ func (s *Service) Delete(m *scope.MachineScope) error {
 
 	return kerrors.NewAggregate(errs)
 }
+
+// UserData returns the userdata fetching the secrets with the given prefix from AWS Secrets Manager.
+// Only cloud-init is able to fetch them.
+func (s *Service) UserData(secretPrefix string, chunks int32, region string, format infrav1.BootstrapFormat) ([]byte, error) {
+	if format != infrav1.BootstrapFormatCloudConfig {
+		return nil, errors.Errorf("bootstrap format %q is not supported by the secrets-manager secure secrets backend", format)
+	}
+	return GenerateCloudInitMIMEDocument(secretPrefix, chunks, region)
+}